@@ -0,0 +1,12 @@
+// Package causality reconstructs the causal relationships between messages
+// from the envelopes that contain them.
+//
+// A [Graph] ingests [envelopepb.Envelope] and [envelopepb.MultiEnvelope]
+// values, such as those read from a journal or event stream, and links each
+// message to the message that caused it. Messages are grouped into a [Tree]
+// for each correlation ID, which identifies orphaned messages whose cause is
+// unknown and causal cycles that indicate corrupt meta-data.
+//
+// Trees can be rendered as indented text using [WriteText] or as a Graphviz
+// DOT graph using [WriteDOT].
+package causality
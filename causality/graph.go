@@ -0,0 +1,237 @@
+package causality
+
+import (
+	"fmt"
+
+	"github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+// A Graph is a set of messages and the causal relationships between them.
+//
+// The zero-value is an empty graph.
+type Graph struct {
+	nodes   uuidpb.Map[*Node]
+	pending uuidpb.Map[[]*Node]
+	trees   uuidpb.Map[*Tree]
+	order   []*Tree
+}
+
+// A Node is a single message within a [Graph].
+type Node struct {
+	// Envelope is the envelope that contains the message.
+	Envelope *envelopepb.Envelope
+
+	// Cause is the node for the message that caused this message.
+	//
+	// It is nil if the message is a root, or if the cause is not present in
+	// the graph.
+	Cause *Node
+
+	// Effects are the nodes for messages caused by this message, in the order
+	// they were added to the graph.
+	Effects []*Node
+}
+
+// MessageID returns the ID of the node's message.
+func (n *Node) MessageID() *uuidpb.UUID {
+	return n.Envelope.GetBody().GetMessageId()
+}
+
+// IsRoot returns true if the node's message is the first message in its
+// causal chain, such as a command produced outside of a handler.
+func (n *Node) IsRoot() bool {
+	return n.Envelope.GetHeader().GetCausationId().Equal(n.MessageID())
+}
+
+// IsOrphan returns true if the node's message is not a root, and its cause is
+// not present in the graph.
+func (n *Node) IsOrphan() bool {
+	return !n.IsRoot() && n.Cause == nil
+}
+
+// A Tree is the set of messages within a [Graph] that share the same
+// correlation ID.
+type Tree struct {
+	// CorrelationID is the correlation ID shared by all messages in the tree.
+	CorrelationID *uuidpb.UUID
+
+	// Nodes are the nodes in the tree, in the order they were added to the
+	// graph.
+	Nodes []*Node
+}
+
+// Add adds the message within env to the graph.
+//
+// It returns an error if env is not well-formed. Adding a message that is
+// already present in the graph has no effect.
+func (g *Graph) Add(env *envelopepb.Envelope) error {
+	if err := env.Validate(); err != nil {
+		return fmt.Errorf("invalid envelope: %w", err)
+	}
+
+	g.add(env)
+
+	return nil
+}
+
+// AddMulti adds each message within env to the graph.
+//
+// It returns an error if env is not well-formed. Adding a message that is
+// already present in the graph has no effect.
+func (g *Graph) AddMulti(env *envelopepb.MultiEnvelope) error {
+	if err := env.Validate(); err != nil {
+		return fmt.Errorf("invalid envelope: %w", err)
+	}
+
+	for e := range env.All() {
+		g.add(e)
+	}
+
+	return nil
+}
+
+// Node returns the node for the message with the given ID.
+func (g *Graph) Node(messageID *uuidpb.UUID) (*Node, bool) {
+	return g.nodes.Get(messageID)
+}
+
+// Tree returns the tree for the given correlation ID.
+func (g *Graph) Tree(correlationID *uuidpb.UUID) (*Tree, bool) {
+	return g.trees.Get(correlationID)
+}
+
+// Trees returns all trees in the graph, in the order that their first message
+// was added.
+func (g *Graph) Trees() []*Tree {
+	return g.order
+}
+
+func (g *Graph) add(env *envelopepb.Envelope) {
+	n := &Node{Envelope: env}
+	id := n.MessageID()
+
+	if g.nodes.Has(id) {
+		return
+	}
+	g.nodes.Set(id, n)
+
+	correlationID := env.GetHeader().GetCorrelationId()
+	t, ok := g.trees.Get(correlationID)
+	if !ok {
+		t = &Tree{CorrelationID: correlationID}
+		g.trees.Set(correlationID, t)
+		g.order = append(g.order, t)
+	}
+	t.Nodes = append(t.Nodes, n)
+
+	if !n.IsRoot() {
+		causationID := env.GetHeader().GetCausationId()
+
+		if cause, ok := g.nodes.Get(causationID); ok {
+			link(cause, n)
+		} else {
+			p, _ := g.pending.Get(causationID)
+			g.pending.Set(causationID, append(p, n))
+		}
+	}
+
+	if effects, ok := g.pending.Get(id); ok {
+		g.pending.Delete(id)
+		for _, e := range effects {
+			link(n, e)
+		}
+	}
+}
+
+// link records that effect was caused by cause, provided that both messages
+// share the same correlation ID.
+func link(cause, effect *Node) {
+	if !cause.Envelope.GetHeader().GetCorrelationId().Equal(
+		effect.Envelope.GetHeader().GetCorrelationId(),
+	) {
+		return
+	}
+
+	effect.Cause = cause
+	cause.Effects = append(cause.Effects, effect)
+}
+
+// Roots returns the nodes in the tree that are roots.
+func (t *Tree) Roots() []*Node {
+	var roots []*Node
+	for _, n := range t.Nodes {
+		if n.IsRoot() {
+			roots = append(roots, n)
+		}
+	}
+	return roots
+}
+
+// Orphans returns the nodes in the tree whose cause is not present in the
+// graph, or whose cause has a different correlation ID.
+func (t *Tree) Orphans() []*Node {
+	var orphans []*Node
+	for _, n := range t.Nodes {
+		if n.IsOrphan() {
+			orphans = append(orphans, n)
+		}
+	}
+	return orphans
+}
+
+// Cycles returns the causal cycles within the tree.
+//
+// Each cycle is a sequence of nodes in which each node is caused by the node
+// before it, and the first node is caused by the last. A valid tree has no
+// cycles.
+func (t *Tree) Cycles() [][]*Node {
+	var (
+		cycles [][]*Node
+		done   = map[*Node]struct{}{}
+	)
+
+	for _, n := range t.Nodes {
+		var (
+			chain []*Node
+			index = map[*Node]int{}
+		)
+
+		for x := n; x != nil; x = x.Cause {
+			if _, ok := done[x]; ok {
+				break
+			}
+
+			if i, ok := index[x]; ok {
+				cycle := chain[i:]
+				for l, r := 0, len(cycle)-1; l < r; l, r = l+1, r-1 {
+					cycle[l], cycle[r] = cycle[r], cycle[l]
+				}
+				cycles = append(cycles, cycle)
+				break
+			}
+
+			index[x] = len(chain)
+			chain = append(chain, x)
+		}
+
+		for _, x := range chain {
+			done[x] = struct{}{}
+		}
+	}
+
+	return cycles
+}
+
+// Heads returns the nodes from which every other node in the tree can be
+// reached by following effects: the roots, the orphans, and the first node
+// of each cycle.
+func (t *Tree) Heads() []*Node {
+	heads := append(t.Roots(), t.Orphans()...)
+
+	for _, c := range t.Cycles() {
+		heads = append(heads, c[0])
+	}
+
+	return heads
+}
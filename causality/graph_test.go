@@ -0,0 +1,254 @@
+package causality_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	. "github.com/dogmatiq/enginekit/causality"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	"github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+func TestGraph(t *testing.T) {
+	id := func(n int) *uuidpb.UUID {
+		return uuidpb.MustParse(fmt.Sprintf("00000000-0000-4000-8000-%012d", n))
+	}
+
+	var next int
+	packer := &envelopepb.Packer{
+		Application: identitypb.New("app", uuidpb.Generate()),
+		GenerateID: func() *uuidpb.UUID {
+			next++
+			return id(next)
+		},
+	}
+
+	command := packer.PackCommand(CommandA1)
+
+	aggregate := packer.PackEffects(
+		command,
+		identitypb.New("aggregate", uuidpb.Generate()),
+		envelopepb.WithInstanceID("<aggregate-instance>"),
+	)
+	event1 := aggregate.PackEvent(EventA1)
+	aggregate.PackEvent(EventA2)
+	events, _ := aggregate.Seal()
+
+	process := packer.PackEffects(
+		event1,
+		identitypb.New("process", uuidpb.Generate()),
+		envelopepb.WithInstanceID("<process-instance>"),
+	)
+	processCommand := process.PackCommand(CommandB1)
+
+	orphan := envelopepb.NewEnvelopeBuilder().
+		From(processCommand).
+		WithHeader(
+			envelopepb.NewHeaderBuilder().
+				From(processCommand.GetHeader()).
+				WithCausationId(id(99)).
+				Build(),
+		).
+		WithBody(
+			envelopepb.NewBodyBuilder().
+				From(processCommand.GetBody()).
+				WithMessageId(id(5)).
+				Build(),
+		).
+		Build()
+
+	cyclic := func(messageID, causationID int) *envelopepb.Envelope {
+		return envelopepb.NewEnvelopeBuilder().
+			From(command).
+			WithHeader(
+				envelopepb.NewHeaderBuilder().
+					From(command.GetHeader()).
+					WithCausationId(id(causationID)).
+					WithCorrelationId(id(50)).
+					Build(),
+			).
+			WithBody(
+				envelopepb.NewBodyBuilder().
+					From(command.GetBody()).
+					WithMessageId(id(messageID)).
+					Build(),
+			).
+			Build()
+	}
+
+	g := &Graph{}
+
+	for _, env := range []*envelopepb.Envelope{
+		processCommand, // added before its cause
+		command,
+		orphan,
+		cyclic(51, 52),
+		cyclic(52, 51),
+	} {
+		if err := g.Add(env); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := g.AddMulti(events); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := g.Add(command); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("it links messages to their causes", func(t *testing.T) {
+		n, ok := g.Node(id(4))
+		if !ok {
+			t.Fatal("expected node to be present")
+		}
+
+		Expect(t, "unexpected cause", n.Cause.MessageID(), id(2))
+		Expect(t, "unexpected effects", len(n.Cause.Effects), 1)
+		Expect(t, "unexpected root", n.Cause.Cause.MessageID(), id(1))
+
+		if !n.Cause.Cause.IsRoot() {
+			t.Fatal("expected node to be a root")
+		}
+	})
+
+	t.Run("it groups messages by correlation ID", func(t *testing.T) {
+		trees := g.Trees()
+		Expect(t, "unexpected tree count", len(trees), 2)
+		Expect(t, "unexpected correlation ID", trees[0].CorrelationID, id(1))
+		Expect(t, "unexpected node count", len(trees[0].Nodes), 5)
+		Expect(t, "unexpected correlation ID", trees[1].CorrelationID, id(50))
+
+		tree, ok := g.Tree(id(50))
+		if !ok {
+			t.Fatal("expected tree to be present")
+		}
+		Expect(t, "unexpected tree", tree, trees[1])
+	})
+
+	t.Run("it finds orphans", func(t *testing.T) {
+		tree, _ := g.Tree(id(1))
+		orphans := tree.Orphans()
+
+		Expect(t, "unexpected orphan count", len(orphans), 1)
+		Expect(t, "unexpected orphan", orphans[0].MessageID(), id(5))
+	})
+
+	t.Run("it finds cycles", func(t *testing.T) {
+		tree, _ := g.Tree(id(1))
+		Expect(t, "unexpected cycle count", len(tree.Cycles()), 0)
+
+		tree, _ = g.Tree(id(50))
+		cycles := tree.Cycles()
+
+		Expect(t, "unexpected cycle count", len(cycles), 1)
+		Expect(t, "unexpected cycle length", len(cycles[0]), 2)
+		Expect(t, "unexpected roots", len(tree.Roots()), 0)
+	})
+
+	t.Run("func WriteText()", func(t *testing.T) {
+		t.Run("it renders the tree", func(t *testing.T) {
+			tree, _ := g.Tree(id(1))
+
+			var w strings.Builder
+			if err := WriteText(&w, tree.Heads()...); err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(
+				t,
+				"unexpected output",
+				w.String(),
+				fmt.Sprintf(
+					strings.Join(
+						[]string{
+							"00000000-0000-4000-8000-000000000001 %s",
+							"├── 00000000-0000-4000-8000-000000000002 %s (handler: aggregate, instance: <aggregate-instance>)",
+							"│   └── 00000000-0000-4000-8000-000000000004 %s (handler: process, instance: <process-instance>)",
+							"└── 00000000-0000-4000-8000-000000000003 %s (handler: aggregate, instance: <aggregate-instance>)",
+							"(orphan) 00000000-0000-4000-8000-000000000005 %s (handler: process, instance: <process-instance>)",
+							"",
+						},
+						"\n",
+					),
+					CommandA1.MessageDescription(),
+					EventA1.MessageDescription(),
+					CommandB1.MessageDescription(),
+					EventA2.MessageDescription(),
+					CommandB1.MessageDescription(),
+				),
+			)
+		})
+
+		t.Run("it renders cycles", func(t *testing.T) {
+			tree, _ := g.Tree(id(50))
+
+			var w strings.Builder
+			if err := WriteText(&w, tree.Heads()...); err != nil {
+				t.Fatal(err)
+			}
+
+			d := CommandA1.MessageDescription()
+
+			Expect(
+				t,
+				"unexpected output",
+				w.String(),
+				"00000000-0000-4000-8000-000000000052 "+d+"\n"+
+					"└── 00000000-0000-4000-8000-000000000051 "+d+"\n"+
+					"    └── 00000000-0000-4000-8000-000000000052 "+d+" (cycle)\n",
+			)
+		})
+	})
+
+	t.Run("func WriteDOT()", func(t *testing.T) {
+		t.Run("it renders the tree", func(t *testing.T) {
+			n, _ := g.Node(id(2))
+			tree, _ := g.Tree(id(1))
+			orphan := tree.Orphans()[0]
+
+			var w strings.Builder
+			if err := WriteDOT(&w, n, orphan); err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(
+				t,
+				"unexpected output",
+				w.String(),
+				fmt.Sprintf(
+					strings.Join(
+						[]string{
+							`digraph {`,
+							`	node [shape=box];`,
+							`	"00000000-0000-4000-8000-000000000002" [label="00000000-0000-4000-8000-000000000002\n%s\nhandler: aggregate\ninstance: <aggregate-instance>"];`,
+							`	"00000000-0000-4000-8000-000000000002" -> "00000000-0000-4000-8000-000000000004";`,
+							`	"00000000-0000-4000-8000-000000000004" [label="00000000-0000-4000-8000-000000000004\n%s\nhandler: process\ninstance: <process-instance>"];`,
+							`	"00000000-0000-4000-8000-000000000005" [label="00000000-0000-4000-8000-000000000005\n%s\nhandler: process\ninstance: <process-instance>"];`,
+							`	"00000000-0000-4000-8000-000000000099" [label="00000000-0000-4000-8000-000000000099\n(missing)", style=dashed];`,
+							`	"00000000-0000-4000-8000-000000000099" -> "00000000-0000-4000-8000-000000000005" [style=dashed];`,
+							`}`,
+							``,
+						},
+						"\n",
+					),
+					EventA1.MessageDescription(),
+					CommandB1.MessageDescription(),
+					CommandB1.MessageDescription(),
+				),
+			)
+		})
+	})
+
+	t.Run("it returns an error if the envelope is invalid", func(t *testing.T) {
+		err := g.Add(&envelopepb.Envelope{})
+		if err == nil || !strings.HasPrefix(err.Error(), "invalid envelope: invalid header:") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
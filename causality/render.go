@@ -0,0 +1,175 @@
+package causality
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteText writes an indented textual representation of the given nodes, and
+// all of the nodes reachable from them via their effects, to w.
+//
+// Use [Tree.Heads] to render an entire tree.
+func WriteText(w io.Writer, nodes ...*Node) error {
+	r := &textRenderer{
+		w:       w,
+		visited: map[*Node]struct{}{},
+	}
+
+	for _, n := range nodes {
+		r.write(n, "", "")
+	}
+
+	return r.err
+}
+
+// WriteDOT writes a Graphviz DOT graph of the given nodes, and all of the
+// nodes reachable from them via their effects, to w.
+//
+// Orphaned nodes are connected to a dashed placeholder node that represents
+// their missing cause.
+//
+// Use [Tree.Heads] to render an entire tree.
+func WriteDOT(w io.Writer, nodes ...*Node) error {
+	r := &dotRenderer{
+		w:       w,
+		visited: map[*Node]struct{}{},
+	}
+
+	r.printf("digraph {\n")
+	r.printf("\tnode [shape=box];\n")
+
+	for _, n := range nodes {
+		r.write(n)
+	}
+
+	r.printf("}\n")
+
+	return r.err
+}
+
+type textRenderer struct {
+	w       io.Writer
+	visited map[*Node]struct{}
+	err     error
+}
+
+func (r *textRenderer) write(n *Node, prefix, childPrefix string) {
+	if r.err != nil {
+		return
+	}
+
+	label := describe(n)
+	if n.IsOrphan() {
+		label = "(orphan) " + label
+	}
+
+	if _, ok := r.visited[n]; ok {
+		_, r.err = fmt.Fprintf(r.w, "%s%s (cycle)\n", prefix, label)
+		return
+	}
+	r.visited[n] = struct{}{}
+
+	if _, r.err = fmt.Fprintf(r.w, "%s%s\n", prefix, label); r.err != nil {
+		return
+	}
+
+	for i, e := range n.Effects {
+		if i == len(n.Effects)-1 {
+			r.write(e, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			r.write(e, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+type dotRenderer struct {
+	w       io.Writer
+	visited map[*Node]struct{}
+	err     error
+}
+
+func (r *dotRenderer) write(n *Node) {
+	if _, ok := r.visited[n]; ok {
+		return
+	}
+	r.visited[n] = struct{}{}
+
+	r.printf(
+		"\t%s [label=%s];\n",
+		dotQuote(n.MessageID().AsString()),
+		dotQuote(strings.Join(details(n), "\n")),
+	)
+
+	if n.IsOrphan() {
+		causationID := n.Envelope.GetHeader().GetCausationId().AsString()
+
+		r.printf(
+			"\t%s [label=%s, style=dashed];\n",
+			dotQuote(causationID),
+			dotQuote(causationID+"\n(missing)"),
+		)
+		r.printf(
+			"\t%s -> %s [style=dashed];\n",
+			dotQuote(causationID),
+			dotQuote(n.MessageID().AsString()),
+		)
+	}
+
+	for _, e := range n.Effects {
+		r.printf(
+			"\t%s -> %s;\n",
+			dotQuote(n.MessageID().AsString()),
+			dotQuote(e.MessageID().AsString()),
+		)
+	}
+
+	for _, e := range n.Effects {
+		r.write(e)
+	}
+}
+
+func (r *dotRenderer) printf(format string, args ...any) {
+	if r.err == nil {
+		_, r.err = fmt.Fprintf(r.w, format, args...)
+	}
+}
+
+// describe returns a single-line description of n.
+func describe(n *Node) string {
+	d := details(n)
+
+	if len(d) == 2 {
+		return d[0] + " " + d[1]
+	}
+
+	return fmt.Sprintf("%s %s (%s)", d[0], d[1], strings.Join(d[2:], ", "))
+}
+
+// details returns the message ID, description and source of n.
+func details(n *Node) []string {
+	source := n.Envelope.GetHeader().GetSource()
+
+	d := []string{
+		n.MessageID().AsString(),
+		n.Envelope.GetBody().GetMessage().GetDescription(),
+	}
+
+	if h := source.GetHandler(); h != nil {
+		d = append(d, "handler: "+h.GetName())
+	}
+
+	if id := source.GetInstanceId(); id != "" {
+		d = append(d, "instance: "+id)
+	}
+
+	return d
+}
+
+// dotQuote returns s as a quoted DOT string.
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -165,22 +165,15 @@ func reportIdentityConflicts(ctx *validateContext, app *Application) {
 		}
 	}
 
-	normalizeKey := func(k string) string {
-		if id, err := uuidpb.Parse(k); err == nil {
-			return id.AsString()
-		}
-		return k
-	}
-
 	for _, id := range app.identities() {
 		// We don't need to check for conflicts with the application's name
 		// because it's allowed to be the same as one of the handler's names.
-		push(byKey, optional.Transform(id.Key, normalizeKey), app)
+		push(byKey, optional.Transform(id.Key, normalizeIdentityKey), app)
 	}
 
 	for _, h := range app.HandlerComponents {
 		for _, id := range h.EntityProperties().IdentityComponents {
-			push(byKey, optional.Transform(id.Key, normalizeKey), h)
+			push(byKey, optional.Transform(id.Key, normalizeIdentityKey), h)
 			push(byName, id.Name, h)
 		}
 	}
@@ -225,3 +218,12 @@ func reportRouteConflicts(ctx *validateContext, app *Application) {
 		}
 	}
 }
+
+// normalizeIdentityKey returns the canonical representation of an identity key,
+// such that keys that differ only in their UUID formatting compare equal.
+func normalizeIdentityKey(k string) string {
+	if id, err := uuidpb.Parse(k); err == nil {
+		return id.AsString()
+	}
+	return k
+}
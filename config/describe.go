@@ -53,6 +53,7 @@ type DescribeOption func(*describeOptions)
 
 type describeOptions struct {
 	ValidationResult optional.Optional[error]
+	Annotations      []func(Component) []string
 }
 
 // WithValidationResult is a [DescribeOption] that sets the validation result to
@@ -63,6 +64,17 @@ func WithValidationResult(err error) DescribeOption {
 	}
 }
 
+// WithAnnotations is a [DescribeOption] that includes additional information
+// about each component in the description, such as the findings of a linter.
+//
+// fn is called for each component in the description. Each string it returns
+// is included beneath the component, alongside any validation errors.
+func WithAnnotations(fn func(Component) []string) DescribeOption {
+	return func(opts *describeOptions) {
+		opts.Annotations = append(opts.Annotations, fn)
+	}
+}
+
 type describeContext struct {
 	Component Component
 
@@ -83,21 +95,26 @@ func hasError[T error](ctx *describeContext) bool {
 }
 
 func (ctx *describeContext) DescribeFidelity() {
-	p := ctx.Component.ComponentProperties()
+	ctx.Print(ctx.Fidelity(), " ")
 
-	if p.IsPartial || hasError[PartialConfigurationError](ctx) || hasError[ConfigurationUnavailableError](ctx) {
-		ctx.Print("incomplete ")
-	} else if !ctx.options.ValidationResult.IsPresent() {
-		ctx.Print("unvalidated ")
-	} else if len(ctx.errors) == 0 {
-		ctx.Print("valid ")
-	} else {
-		ctx.Print("invalid ")
+	if ctx.Component.ComponentProperties().IsSpeculative {
+		ctx.Print("speculative ")
 	}
+}
 
-	if p.IsSpeculative {
-		ctx.Print("speculative ")
+// Fidelity returns a single word that describes the completeness and validity
+// of the component.
+func (ctx *describeContext) Fidelity() string {
+	if ctx.Component.ComponentProperties().IsPartial ||
+		hasError[PartialConfigurationError](ctx) ||
+		hasError[ConfigurationUnavailableError](ctx) {
+		return "incomplete"
+	} else if !ctx.options.ValidationResult.IsPresent() {
+		return "unvalidated"
+	} else if len(ctx.errors) == 0 {
+		return "valid"
 	}
+	return "invalid"
 }
 
 func (ctx *describeContext) DescribeChild(c Component) {
@@ -130,4 +147,20 @@ func (ctx *describeContext) DescribeErrors() {
 		ctx.renderer.Print(err.Error(), "\n")
 		ctx.renderer.EndChild()
 	}
+
+	for _, a := range ctx.Annotations() {
+		ctx.renderer.StartChild()
+		ctx.renderer.Print(a, "\n")
+		ctx.renderer.EndChild()
+	}
+}
+
+// Annotations returns the annotations for the component, as provided by the
+// [WithAnnotations] option.
+func (ctx *describeContext) Annotations() []string {
+	var annotations []string
+	for _, fn := range ctx.options.Annotations {
+		annotations = append(annotations, fn(ctx.Component)...)
+	}
+	return annotations
 }
@@ -0,0 +1,361 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/optional"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// ComponentDescription is a machine-readable description of a [Component], as
+// produced by [DescribeJSON].
+type ComponentDescription struct {
+	// Component is the type of component, such as "application", "aggregate",
+	// "identity" or "route".
+	Component string `json:"component"`
+
+	// String is the short human-readable representation of the component, as
+	// returned by its String() method.
+	String string `json:"string"`
+
+	// Fidelity describes the completeness and validity of the component. It is
+	// one of "incomplete", "unvalidated", "valid" or "invalid".
+	Fidelity string `json:"fidelity"`
+
+	IsPartial     bool `json:"isPartial"`
+	IsSpeculative bool `json:"isSpeculative"`
+
+	// TypeName is the fully-qualified name of the Go type that implements an
+	// [Entity], if available.
+	TypeName *string `json:"typeName,omitempty"`
+
+	// IsSourceAvailable indicates whether the value that implements an
+	// [Entity] is available.
+	IsSourceAvailable *bool `json:"isSourceAvailable,omitempty"`
+
+	// Name and Key are the elements of an [Identity], if available.
+	Name *string `json:"name,omitempty"`
+	Key  *string `json:"key,omitempty"`
+
+	// RouteType, MessageTypeID and MessageTypeName are the properties of a
+	// [Route], if available.
+	RouteType       *string `json:"routeType,omitempty"`
+	MessageTypeID   *string `json:"messageTypeId,omitempty"`
+	MessageTypeName *string `json:"messageTypeName,omitempty"`
+
+	// IsMessageTypeAvailable indicates whether the [message.Type] of a [Route]
+	// is available.
+	IsMessageTypeAvailable *bool `json:"isMessageTypeAvailable,omitempty"`
+
+	// Symbol is the name of the [Symbol] that identifies a [Flag].
+	Symbol *string `json:"symbol,omitempty"`
+
+	// Value is the value of a [Flag] or [ConcurrencyPreference], if available.
+	Value any `json:"value,omitempty"`
+
+	// Options is the string representation of each option that was supplied
+	// when a [Flag] was set.
+	Options []string `json:"options,omitempty"`
+
+	// Errors is the list of validation errors that are directly associated
+	// with the component.
+	Errors []ErrorDescription `json:"errors,omitempty"`
+
+	// Annotations is the list of annotations for the component, as provided
+	// by the [WithAnnotations] option.
+	Annotations []string `json:"annotations,omitempty"`
+
+	// Extensions is the list of engine-specific extensions attached to an
+	// [Entity].
+	Extensions []ExtensionDescription `json:"extensions,omitempty"`
+
+	Identities             []ComponentDescription `json:"identities,omitempty"`
+	Routes                 []ComponentDescription `json:"routes,omitempty"`
+	Flags                  []ComponentDescription `json:"flags,omitempty"`
+	ConcurrencyPreferences []ComponentDescription `json:"concurrencyPreferences,omitempty"`
+	Handlers               []ComponentDescription `json:"handlers,omitempty"`
+}
+
+// ErrorDescription is a machine-readable description of a validation error,
+// as produced by [DescribeJSON].
+type ErrorDescription struct {
+	// Type is the name of the error's Go type, such as
+	// "MissingRouteTypeError".
+	Type string `json:"type"`
+
+	// Message is the human-readable error message.
+	Message string `json:"message"`
+
+	// Fields contains the exported fields of the error, keyed by field name.
+	//
+	// Fields that refer to other components are represented by the
+	// component's String() representation.
+	Fields map[string]any `json:"fields,omitempty"`
+}
+
+// ExtensionDescription is a machine-readable description of an extension
+// attached to an [Entity], as produced by [DescribeJSON].
+type ExtensionDescription struct {
+	// Type is the fully-qualified name of the extension's Protocol Buffers
+	// message type.
+	Type string `json:"type"`
+
+	// Value is the Protocol Buffers JSON representation of the extension.
+	Value json.RawMessage `json:"value"`
+}
+
+// DescribeJSON writes a machine-readable JSON description of a [Component] to
+// w.
+//
+// It describes the same information as [Describe]. When the
+// [WithValidationResult] option is used, each validation error is attached to
+// the component that it belongs to, as per [ErrorsByComponent].
+func DescribeJSON(
+	w io.Writer,
+	c Component,
+	options ...DescribeOption,
+) error {
+	var opts describeOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(describeJSON(c, opts))
+}
+
+func describeJSON(c Component, opts describeOptions) ComponentDescription {
+	ctx := &describeContext{
+		Component: c,
+		options:   opts,
+	}
+
+	if err, ok := opts.ValidationResult.TryGet(); ok {
+		ctx.errors = ErrorsByComponent(c, err)
+	}
+
+	p := c.ComponentProperties()
+
+	d := ComponentDescription{
+		String:        c.String(),
+		Fidelity:      ctx.Fidelity(),
+		IsPartial:     p.IsPartial,
+		IsSpeculative: p.IsSpeculative,
+	}
+
+	for _, err := range ctx.errors {
+		d.Errors = append(d.Errors, describeErrorJSON(err))
+	}
+
+	d.Annotations = ctx.Annotations()
+
+	children := func(target *[]ComponentDescription, components ...Component) {
+		for _, c := range components {
+			*target = append(*target, describeJSON(c, opts))
+		}
+	}
+
+	switch c := c.(type) {
+	case *Application:
+		describeEntityJSON(&d, c, c.Source, opts)
+
+		for _, h := range c.HandlerComponents {
+			children(&d.Handlers, h)
+		}
+
+	case *Aggregate:
+		describeHandlerJSON(&d, c, c.Source, opts)
+
+	case *Process:
+		describeHandlerJSON(&d, c, c.Source, opts)
+
+	case *Integration:
+		describeHandlerJSON(&d, c, c.Source, opts)
+
+		for _, p := range c.ConcurrencyPreferences {
+			children(&d.ConcurrencyPreferences, p)
+		}
+
+	case *Projection:
+		describeHandlerJSON(&d, c, c.Source, opts)
+
+		for _, p := range c.ConcurrencyPreferences {
+			children(&d.ConcurrencyPreferences, p)
+		}
+
+	case *Identity:
+		d.Component = "identity"
+		d.Name = optionalJSON(c.Name)
+		d.Key = optionalJSON(c.Key)
+
+	case *Route:
+		d.Component = "route"
+		d.RouteType = optionalJSON(optional.Transform(c.RouteType, RouteType.String))
+		d.MessageTypeID = optionalJSON(c.MessageTypeID)
+		d.MessageTypeName = optionalJSON(c.MessageTypeName)
+		d.IsMessageTypeAvailable = ptr(c.MessageType.IsPresent())
+
+	case *Flag[Disabled]:
+		describeFlagJSON(&d, c)
+
+	case *ConcurrencyPreference:
+		d.Component = "concurrency preference"
+
+		if v, ok := c.Value.TryGet(); ok {
+			switch v {
+			case dogma.MinimizeConcurrency:
+				d.Value = "dogma.MinimizeConcurrency"
+			case dogma.MaximizeConcurrency:
+				d.Value = "dogma.MaximizeConcurrency"
+			default:
+				d.Value = int(v)
+			}
+		}
+	}
+
+	return d
+}
+
+func describeEntityJSON[T any](
+	d *ComponentDescription,
+	e Entity,
+	source optional.Optional[T],
+	opts describeOptions,
+) {
+	p := e.EntityProperties()
+
+	d.Component = entityLabel(e)
+	d.TypeName = optionalJSON(p.TypeName)
+	d.IsSourceAvailable = ptr(source.IsPresent())
+
+	for _, i := range p.IdentityComponents {
+		d.Identities = append(d.Identities, describeJSON(i, opts))
+	}
+
+	for _, x := range p.Extensions {
+		v, err := protojson.Marshal(x)
+		if err != nil {
+			v, _ = json.Marshal(err.Error())
+		}
+
+		d.Extensions = append(
+			d.Extensions,
+			ExtensionDescription{
+				Type:  string(extensionName(x)),
+				Value: v,
+			},
+		)
+	}
+}
+
+func describeHandlerJSON[T any](
+	d *ComponentDescription,
+	h Handler,
+	source optional.Optional[T],
+	opts describeOptions,
+) {
+	describeEntityJSON(d, h, source, opts)
+
+	p := h.HandlerProperties()
+
+	for _, r := range p.RouteComponents {
+		d.Routes = append(d.Routes, describeJSON(r, opts))
+	}
+
+	for _, f := range p.DisabledFlags {
+		d.Flags = append(d.Flags, describeJSON(f, opts))
+	}
+}
+
+func describeFlagJSON[S Symbol](d *ComponentDescription, f *Flag[S]) {
+	d.Component = "flag"
+	d.Symbol = ptr(stringifySymbol[S]())
+
+	if v, ok := f.Value.TryGet(); ok {
+		d.Value = v
+	}
+
+	d.Options = f.optionStrings()
+}
+
+func describeErrorJSON(err error) ErrorDescription {
+	d := ErrorDescription{
+		Type:    fmt.Sprintf("%T", err),
+		Message: err.Error(),
+	}
+
+	rv := reflect.ValueOf(err)
+	if rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+
+	if t := rv.Type(); t.Name() != "" {
+		d.Type = t.Name()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return d
+	}
+
+	for i := range rv.NumField() {
+		f := rv.Type().Field(i)
+		if f.IsExported() {
+			if d.Fields == nil {
+				d.Fields = map[string]any{}
+			}
+			d.Fields[f.Name] = errorFieldJSON(rv.Field(i))
+		}
+	}
+
+	return d
+}
+
+// errorFieldJSON returns a JSON-friendly representation of a field within a
+// validation error.
+func errorFieldJSON(v reflect.Value) any {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+	}
+
+	switch x := v.Interface().(type) {
+	case Component:
+		return x.String()
+	case fmt.Stringer:
+		return x.String()
+	case error:
+		return x.Error()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		values := make([]any, v.Len())
+		for i := range values {
+			values[i] = errorFieldJSON(v.Index(i))
+		}
+		return values
+	case reflect.Interface:
+		return errorFieldJSON(v.Elem())
+	default:
+		return v.Interface()
+	}
+}
+
+func optionalJSON[T any](v optional.Optional[T]) *T {
+	if v, ok := v.TryGet(); ok {
+		return &v
+	}
+	return nil
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,283 @@
+package config_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/config/internal/configbuilder"
+	"github.com/dogmatiq/enginekit/config/runtimeconfig"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/internal/test"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestDescribeJSON(t *testing.T) {
+	describe := func(t *testing.T, c Component, options ...DescribeOption) ComponentDescription {
+		t.Helper()
+
+		var w strings.Builder
+		if err := DescribeJSON(&w, c, options...); err != nil {
+			t.Fatal(err)
+		}
+
+		var d ComponentDescription
+		if err := json.Unmarshal([]byte(w.String()), &d); err != nil {
+			t.Fatal(err)
+		}
+
+		return d
+	}
+
+	t.Run("it describes the component tree", func(t *testing.T) {
+		app := configbuilder.Application(
+			func(b *configbuilder.ApplicationBuilder) {
+				b.TypeName("pkg.SomeApplication")
+				b.Identity(func(b *configbuilder.IdentityBuilder) {
+					b.Name("app")
+					b.Key("14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+				})
+				b.Integration(func(b *configbuilder.IntegrationBuilder) {
+					b.TypeName("pkg.SomeIntegration")
+					b.Speculative()
+					b.Identity(func(b *configbuilder.IdentityBuilder) {
+						b.Name("integration")
+						b.Partial()
+					})
+					b.Route(func(b *configbuilder.RouteBuilder) {
+						b.RouteType(HandlesCommandRouteType)
+						b.MessageTypeID("c6b1ea0e-9d3c-4bd8-8a3e-4e5b6a5c1b11")
+						b.MessageTypeName("pkg.SomeCommand")
+					})
+					b.Disabled(func(b *configbuilder.FlagBuilder[Disabled]) {
+						b.Value(true)
+					})
+					b.ConcurrencyPreference(func(b *configbuilder.ConcurrencyPreferenceBuilder) {
+						b.Value(dogma.MinimizeConcurrency)
+					})
+				})
+			},
+		)
+
+		name := func(s string) *string { return &s }
+		flag := func(b bool) *bool { return &b }
+
+		test.Expect(
+			t,
+			"unexpected description",
+			describe(t, app),
+			ComponentDescription{
+				Component:         "application",
+				String:            "application:SomeApplication",
+				Fidelity:          "unvalidated",
+				TypeName:          name("pkg.SomeApplication"),
+				IsSourceAvailable: flag(false),
+				Identities: []ComponentDescription{
+					{
+						Component: "identity",
+						String:    "identity:app/14769f7f-87fe-48dd-916e-5bcab6ba6aca",
+						Fidelity:  "unvalidated",
+						Name:      name("app"),
+						Key:       name("14769f7f-87fe-48dd-916e-5bcab6ba6aca"),
+					},
+				},
+				Handlers: []ComponentDescription{
+					{
+						Component:         "integration",
+						String:            "integration:SomeIntegration",
+						Fidelity:          "unvalidated",
+						IsSpeculative:     true,
+						TypeName:          name("pkg.SomeIntegration"),
+						IsSourceAvailable: flag(false),
+						Identities: []ComponentDescription{
+							{
+								Component: "identity",
+								String:    "identity:integration/?",
+								Fidelity:  "incomplete",
+								IsPartial: true,
+								Name:      name("integration"),
+							},
+						},
+						Routes: []ComponentDescription{
+							{
+								Component:              "route",
+								String:                 "route:handles-command:SomeCommand",
+								Fidelity:               "unvalidated",
+								RouteType:              name("handles-command"),
+								MessageTypeID:          name("c6b1ea0e-9d3c-4bd8-8a3e-4e5b6a5c1b11"),
+								MessageTypeName:        name("pkg.SomeCommand"),
+								IsMessageTypeAvailable: flag(false),
+							},
+						},
+						Flags: []ComponentDescription{
+							{
+								Component: "flag",
+								String:    "flag:disabled:true",
+								Fidelity:  "unvalidated",
+								Symbol:    name("disabled"),
+								Value:     true,
+							},
+						},
+						ConcurrencyPreferences: []ComponentDescription{
+							{
+								Component: "concurrency preference",
+								String:    "concurrency preference:dogma.MinimizeConcurrency",
+								Fidelity:  "unvalidated",
+								Value:     "dogma.MinimizeConcurrency",
+							},
+						},
+					},
+				},
+			},
+		)
+	})
+
+	t.Run("it attaches typed validation errors to the components they belong to", func(t *testing.T) {
+		app := runtimeconfig.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("app", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+				c.Routes(
+					dogma.ViaAggregate(&AggregateMessageHandlerStub[*AggregateRootStub]{
+						ConfigureFunc: func(c dogma.AggregateConfigurer) {
+							c.Identity("aggregate", "40ddf2a2-f053-485c-8621-1fc8a58f8ddf")
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+							)
+						},
+					}),
+					dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+						ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+							c.Identity("integration", "40ddf2a2-f053-485c-8621-1fc8a58f8ddf")
+							c.Routes(
+								dogma.HandlesCommand[*CommandStub[TypeA]](),
+							)
+						},
+					}),
+				)
+			},
+		})
+
+		d := describe(t, app, WithValidationResult(Validate(app)))
+
+		test.Expect(
+			t,
+			"unexpected application errors",
+			d.Errors,
+			[]ErrorDescription{
+				{
+					Type:    "IdentityKeyConflictError",
+					Message: `identity key "40ddf2a2-f053-485c-8621-1fc8a58f8ddf" is shared by 2 entities`,
+					Fields: map[string]any{
+						"ConflictingKey": "40ddf2a2-f053-485c-8621-1fc8a58f8ddf",
+						"Entities": []any{
+							"aggregate:AggregateMessageHandlerStub[AggregateRootStub]",
+							"integration:IntegrationMessageHandlerStub",
+						},
+					},
+				},
+				{
+					Type:    "RouteConflictError",
+					Message: "handles-command route for *github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA] is shared by 2 handlers",
+					Fields: map[string]any{
+						"ConflictingRouteType":       "handles-command",
+						"ConflictingMessageTypeName": "*github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]",
+						"Handlers": []any{
+							"aggregate:AggregateMessageHandlerStub[AggregateRootStub]",
+							"integration:IntegrationMessageHandlerStub",
+						},
+					},
+				},
+			},
+		)
+
+		test.Expect(
+			t,
+			"unexpected aggregate fidelity",
+			d.Handlers[0].Fidelity,
+			"invalid",
+		)
+
+		test.Expect(
+			t,
+			"unexpected aggregate errors",
+			d.Handlers[0].Errors,
+			[]ErrorDescription{
+				{
+					Type:    "MissingRouteTypeError",
+					Message: "no records-event routes",
+					Fields: map[string]any{
+						"RouteType": "records-event",
+					},
+				},
+			},
+		)
+
+		test.Expect(
+			t,
+			"unexpected integration errors",
+			len(d.Handlers[1].Errors),
+			0,
+		)
+	})
+
+	t.Run("it describes extensions using their JSON representation", func(t *testing.T) {
+		app := configbuilder.Application(
+			func(b *configbuilder.ApplicationBuilder) {
+				b.TypeName("pkg.SomeApplication")
+			},
+		)
+
+		SetExtension(app, wrapperspb.String("value"))
+
+		d := describe(t, app)
+
+		test.Expect(
+			t,
+			"unexpected extensions",
+			len(d.Extensions),
+			1,
+		)
+
+		test.Expect(
+			t,
+			"unexpected extension type",
+			d.Extensions[0].Type,
+			"google.protobuf.StringValue",
+		)
+
+		test.Expect(
+			t,
+			"unexpected extension value",
+			string(d.Extensions[0].Value),
+			`"value"`,
+		)
+	})
+
+	t.Run("it describes the options of a flag", func(t *testing.T) {
+		h := configbuilder.Integration(func(b *configbuilder.IntegrationBuilder) {
+			b.TypeName("pkg.SomeIntegration")
+			b.Disabled(func(b *configbuilder.FlagBuilder[Disabled]) {
+				b.Value(true)
+				b.Option("maintenance")
+			})
+		})
+
+		d := describe(t, h)
+
+		test.Expect(
+			t,
+			"unexpected flags",
+			len(d.Flags),
+			1,
+		)
+
+		test.Expect(
+			t,
+			"unexpected flag options",
+			d.Flags[0].Options,
+			[]string{"maintenance"},
+		)
+	})
+}
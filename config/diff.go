@@ -0,0 +1,608 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/enginekit/config/internal/renderer"
+	"github.com/dogmatiq/enginekit/internal/enum"
+)
+
+// ChangeType is an enumeration of the types of changes that can be made
+// between two versions of an [Application]'s configuration.
+type ChangeType int
+
+const (
+	// ApplicationKeyChangedChangeType is a [ChangeType] that indicates the
+	// identity key of the [Application] has changed.
+	ApplicationKeyChangedChangeType ChangeType = iota
+
+	// ApplicationRenamedChangeType is a [ChangeType] that indicates the
+	// identity name of the [Application] has changed.
+	ApplicationRenamedChangeType
+
+	// HandlerAddedChangeType is a [ChangeType] that indicates a [Handler] has
+	// been added to the application.
+	HandlerAddedChangeType
+
+	// HandlerRemovedChangeType is a [ChangeType] that indicates a [Handler]
+	// has been removed from the application.
+	HandlerRemovedChangeType
+
+	// HandlerKeyChangedChangeType is a [ChangeType] that indicates the
+	// identity key of a [Handler] has changed, but its name and [HandlerType]
+	// have not.
+	HandlerKeyChangedChangeType
+
+	// HandlerRenamedChangeType is a [ChangeType] that indicates the identity
+	// name of a [Handler] has changed.
+	HandlerRenamedChangeType
+
+	// HandlerTypeChangedChangeType is a [ChangeType] that indicates the
+	// [HandlerType] of a [Handler] has changed.
+	HandlerTypeChangedChangeType
+
+	// HandlerDisabledChangeType is a [ChangeType] that indicates a [Handler]
+	// that was enabled has been disabled.
+	HandlerDisabledChangeType
+
+	// HandlerEnabledChangeType is a [ChangeType] that indicates a [Handler]
+	// that was disabled has been enabled.
+	HandlerEnabledChangeType
+
+	// RouteAddedChangeType is a [ChangeType] that indicates a [Route] has been
+	// added to an existing [Handler].
+	RouteAddedChangeType
+
+	// RouteRemovedChangeType is a [ChangeType] that indicates a [Route] has
+	// been removed from an existing [Handler].
+	RouteRemovedChangeType
+
+	// CommandHandlerChangedChangeType is a [ChangeType] that indicates that
+	// a [HandlesCommandRouteType] route has moved from one [Handler] to
+	// another.
+	CommandHandlerChangedChangeType
+)
+
+func (t ChangeType) String() string {
+	return enum.String(
+		t,
+		"application key changed",
+		"application renamed",
+		"handler added",
+		"handler removed",
+		"handler key changed",
+		"handler renamed",
+		"handler type changed",
+		"handler disabled",
+		"handler enabled",
+		"route added",
+		"route removed",
+		"command handler changed",
+	)
+}
+
+// Change is a single difference between two versions of an [Application]'s
+// configuration.
+type Change struct {
+	// Type is the type of the change.
+	Type ChangeType
+
+	// IsBreaking is true if the change is incompatible with state persisted by
+	// the old version of the application.
+	IsBreaking bool
+
+	// Key is the identity key of the [Entity] that the change applies to, as
+	// it appears in the old version of the application.
+	//
+	// For changes of type [HandlerAddedChangeType] it is the identity key of
+	// the handler in the new version of the application.
+	Key string
+
+	// Old is the [Entity] as it appears in the old version of the
+	// application, or nil if the change is an addition.
+	Old Entity
+
+	// New is the [Entity] as it appears in the new version of the
+	// application, or nil if the change is a removal.
+	New Entity
+
+	// Route is the [Route] that was added, removed or moved, if applicable.
+	Route *Route
+
+	// Dependents is the set of handlers in the new version of the application
+	// that still depend on a removed [Route], if any.
+	//
+	// For example, when a [RecordsEventRouteType] route is removed, the
+	// dependents are the handlers that still handle that event type.
+	Dependents []Handler
+}
+
+func (c Change) String() string {
+	var w strings.Builder
+
+	switch c.Type {
+	case ApplicationKeyChangedChangeType:
+		fmt.Fprintf(
+			&w,
+			"changed key of application %q from %s to %s",
+			c.New.Identity().GetName(),
+			c.Old.Identity().GetKey().AsString(),
+			c.New.Identity().GetKey().AsString(),
+		)
+
+	case ApplicationRenamedChangeType:
+		fmt.Fprintf(
+			&w,
+			"renamed application %s from %q to %q",
+			c.Key,
+			c.Old.Identity().GetName(),
+			c.New.Identity().GetName(),
+		)
+
+	case HandlerAddedChangeType:
+		fmt.Fprintf(&w, "added %s", handlerLabel(c.New.(Handler)))
+
+	case HandlerRemovedChangeType:
+		fmt.Fprintf(&w, "removed %s", handlerLabel(c.Old.(Handler)))
+
+	case HandlerKeyChangedChangeType:
+		fmt.Fprintf(
+			&w,
+			"changed key of %s %q from %s to %s",
+			c.New.(Handler).HandlerType(),
+			c.New.Identity().GetName(),
+			c.Old.Identity().GetKey().AsString(),
+			c.New.Identity().GetKey().AsString(),
+		)
+
+	case HandlerRenamedChangeType:
+		fmt.Fprintf(
+			&w,
+			"renamed %s %s from %q to %q",
+			c.New.(Handler).HandlerType(),
+			c.Key,
+			c.Old.Identity().GetName(),
+			c.New.Identity().GetName(),
+		)
+
+	case HandlerTypeChangedChangeType:
+		fmt.Fprintf(
+			&w,
+			"changed type of %s from %s to %s",
+			identityLabel(c.New),
+			c.Old.(Handler).HandlerType(),
+			c.New.(Handler).HandlerType(),
+		)
+
+	case HandlerDisabledChangeType:
+		fmt.Fprintf(&w, "disabled %s", handlerLabel(c.New.(Handler)))
+
+	case HandlerEnabledChangeType:
+		fmt.Fprintf(&w, "enabled %s", handlerLabel(c.New.(Handler)))
+
+	case RouteAddedChangeType:
+		fmt.Fprintf(
+			&w,
+			"added %s route for %s to %s",
+			c.Route.RouteType.Get(),
+			c.Route.MessageTypeName.Get(),
+			handlerLabel(c.New.(Handler)),
+		)
+
+	case RouteRemovedChangeType:
+		fmt.Fprintf(
+			&w,
+			"removed %s route for %s from %s",
+			c.Route.RouteType.Get(),
+			c.Route.MessageTypeName.Get(),
+			handlerLabel(c.Old.(Handler)),
+		)
+
+	case CommandHandlerChangedChangeType:
+		fmt.Fprintf(
+			&w,
+			"moved %s route for %s from %s to %s",
+			c.Route.RouteType.Get(),
+			c.Route.MessageTypeName.Get(),
+			handlerLabel(c.Old.(Handler)),
+			handlerLabel(c.New.(Handler)),
+		)
+	}
+
+	if len(c.Dependents) != 0 {
+		w.WriteString(" (still required by ")
+
+		for i, h := range c.Dependents {
+			if i > 0 {
+				w.WriteString(", ")
+			}
+			w.WriteString(handlerLabel(h))
+		}
+
+		w.WriteString(")")
+	}
+
+	return w.String()
+}
+
+// ChangeSet is the set of changes between two versions of an [Application]'s
+// configuration.
+type ChangeSet struct {
+	Changes []Change
+}
+
+// IsBreaking returns true if any of the changes in the set are breaking.
+func (s ChangeSet) IsBreaking() bool {
+	return slices.ContainsFunc(
+		s.Changes,
+		func(c Change) bool { return c.IsBreaking },
+	)
+}
+
+// Breaking returns the breaking changes in the set.
+func (s ChangeSet) Breaking() []Change {
+	return s.filter(true)
+}
+
+// NonBreaking returns the non-breaking changes in the set.
+func (s ChangeSet) NonBreaking() []Change {
+	return s.filter(false)
+}
+
+// Describe writes a human-readable report of the changes to w.
+func (s ChangeSet) Describe(w io.Writer) (int, error) {
+	r := &renderer.Renderer{
+		Target: w,
+	}
+
+	if len(s.Changes) == 0 {
+		r.Print("no changes\n")
+		return r.Done()
+	}
+
+	describe := func(adjective string, changes []Change) {
+		if len(changes) == 0 {
+			return
+		}
+
+		noun := "changes"
+		if len(changes) == 1 {
+			noun = "change"
+		}
+
+		r.Printf("%d %s %s\n", len(changes), adjective, noun)
+
+		for _, c := range changes {
+			r.StartChild()
+			r.Print(c.String(), "\n")
+			r.EndChild()
+		}
+	}
+
+	describe("breaking", s.Breaking())
+	describe("non-breaking", s.NonBreaking())
+
+	return r.Done()
+}
+
+// Description returns a human-readable report of the changes.
+func (s ChangeSet) Description() string {
+	var w strings.Builder
+
+	if _, err := s.Describe(&w); err != nil {
+		panic(err)
+	}
+
+	return w.String()
+}
+
+func (s ChangeSet) filter(breaking bool) []Change {
+	var changes []Change
+	for _, c := range s.Changes {
+		if c.IsBreaking == breaking {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+// Diff returns the changes between two versions of an [Application]'s
+// configuration.
+//
+// Handlers are matched by their identity key, such that renaming a handler is
+// not reported as a removal and an addition. A handler whose key has changed
+// is matched to a handler with the same name and [HandlerType], if any.
+//
+// It panics if either configuration is incomplete or invalid.
+func Diff(old, new *Application) ChangeSet {
+	d := &differ{
+		OldApp:   old,
+		NewApp:   new,
+		OldIndex: indexRoutes(old),
+		NewIndex: indexRoutes(new),
+	}
+
+	d.diffApplication()
+	d.diffHandlers()
+
+	return ChangeSet{d.Changes}
+}
+
+type differ struct {
+	OldApp, NewApp *Application
+	OldIndex       map[routeKey][]Handler
+	NewIndex       map[routeKey][]Handler
+	Changes        []Change
+}
+
+func (d *differ) add(c Change) {
+	d.Changes = append(d.Changes, c)
+}
+
+func (d *differ) diffApplication() {
+	oldID := d.OldApp.Identity()
+	newID := d.NewApp.Identity()
+	key := oldID.GetKey().AsString()
+
+	if !oldID.GetKey().Equal(newID.GetKey()) {
+		d.add(Change{
+			Type:       ApplicationKeyChangedChangeType,
+			IsBreaking: true,
+			Key:        key,
+			Old:        d.OldApp,
+			New:        d.NewApp,
+		})
+	}
+
+	if oldID.GetName() != newID.GetName() {
+		d.add(Change{
+			Type: ApplicationRenamedChangeType,
+			Key:  key,
+			Old:  d.OldApp,
+			New:  d.NewApp,
+		})
+	}
+}
+
+func (d *differ) diffHandlers() {
+	var (
+		oldHandlers = d.OldApp.Handlers()
+		newHandlers = d.NewApp.Handlers()
+		pairs       = map[Handler]Handler{}
+		matched     = map[Handler]struct{}{}
+	)
+
+	for _, o := range oldHandlers {
+		for _, n := range newHandlers {
+			if o.Identity().GetKey().Equal(n.Identity().GetKey()) {
+				pairs[o] = n
+				matched[n] = struct{}{}
+				break
+			}
+		}
+	}
+
+	// Match any handlers whose key has changed by their name and type.
+	for _, o := range oldHandlers {
+		if _, ok := pairs[o]; ok {
+			continue
+		}
+
+		for _, n := range newHandlers {
+			if _, ok := matched[n]; ok {
+				continue
+			}
+
+			if o.Identity().GetName() == n.Identity().GetName() &&
+				o.HandlerType() == n.HandlerType() {
+				pairs[o] = n
+				matched[n] = struct{}{}
+				break
+			}
+		}
+	}
+
+	for _, o := range oldHandlers {
+		if n, ok := pairs[o]; ok {
+			d.diffHandler(o, n)
+		} else {
+			d.diffRemovedHandler(o)
+		}
+	}
+
+	for _, n := range newHandlers {
+		if _, ok := matched[n]; !ok {
+			d.add(Change{
+				Type: HandlerAddedChangeType,
+				Key:  n.Identity().GetKey().AsString(),
+				New:  n,
+			})
+		}
+	}
+}
+
+func (d *differ) diffHandler(o, n Handler) {
+	oldID := o.Identity()
+	newID := n.Identity()
+	key := oldID.GetKey().AsString()
+
+	change := func(t ChangeType, breaking bool) {
+		d.add(Change{
+			Type:       t,
+			IsBreaking: breaking,
+			Key:        key,
+			Old:        o,
+			New:        n,
+		})
+	}
+
+	if !oldID.GetKey().Equal(newID.GetKey()) {
+		change(HandlerKeyChangedChangeType, true)
+	}
+
+	if oldID.GetName() != newID.GetName() {
+		change(HandlerRenamedChangeType, false)
+	}
+
+	if o.HandlerType() != n.HandlerType() {
+		change(HandlerTypeChangedChangeType, true)
+	}
+
+	if o.IsDisabled() != n.IsDisabled() {
+		if n.IsDisabled() {
+			change(HandlerDisabledChangeType, false)
+		} else {
+			change(HandlerEnabledChangeType, false)
+		}
+	}
+
+	oldRoutes := routesByKey(o)
+	newRoutes := routesByKey(n)
+
+	for _, r := range o.HandlerProperties().RouteComponents {
+		k, _ := r.key()
+		if _, ok := newRoutes[k]; !ok {
+			d.add(d.diffRemovedRoute(key, o, n, r, k))
+		}
+	}
+
+	for _, r := range n.HandlerProperties().RouteComponents {
+		k, _ := r.key()
+		if _, ok := oldRoutes[k]; ok {
+			continue
+		}
+
+		if k.RouteType == HandlesCommandRouteType && len(d.OldIndex[k]) != 0 {
+			// The command has moved from another handler, which is reported
+			// against that handler's removed route.
+			continue
+		}
+
+		d.add(Change{
+			Type:  RouteAddedChangeType,
+			Key:   key,
+			Old:   o,
+			New:   n,
+			Route: r,
+		})
+	}
+}
+
+// diffRemovedHandler adds the changes describing the removal of the handler o.
+//
+// In addition to the removal itself, it reports each of o's routes that has
+// moved to another handler, or that other handlers still depend upon. Its
+// remaining routes are implied by the removal of the handler.
+func (d *differ) diffRemovedHandler(o Handler) {
+	key := o.Identity().GetKey().AsString()
+
+	d.add(Change{
+		Type:       HandlerRemovedChangeType,
+		IsBreaking: true,
+		Key:        key,
+		Old:        o,
+	})
+
+	for _, r := range o.HandlerProperties().RouteComponents {
+		k, _ := r.key()
+		c := d.diffRemovedRoute(key, o, nil, r, k)
+
+		if c.Type != RouteRemovedChangeType || len(c.Dependents) != 0 {
+			d.add(c)
+		}
+	}
+}
+
+// diffRemovedRoute returns a change describing the removal of r from the
+// handler o, which is known as n in the new version of the application, or nil
+// if o has been removed.
+func (d *differ) diffRemovedRoute(
+	key string,
+	o, n Handler,
+	r *Route,
+	k routeKey,
+) Change {
+	c := Change{
+		Type:  RouteRemovedChangeType,
+		Key:   key,
+		Old:   o,
+		New:   n,
+		Route: r,
+	}
+
+	switch k.RouteType {
+	case HandlesCommandRouteType:
+		if handlers := d.NewIndex[k]; len(handlers) != 0 {
+			c.Type = CommandHandlerChangedChangeType
+			c.IsBreaking = true
+			c.New = handlers[0]
+		} else {
+			c.Dependents = d.NewIndex[routeKey{ExecutesCommandRouteType, k.MessageTypeName}]
+		}
+
+	case RecordsEventRouteType:
+		if len(d.NewIndex[k]) == 0 {
+			c.Dependents = d.NewIndex[routeKey{HandlesEventRouteType, k.MessageTypeName}]
+		}
+
+	case SchedulesDeadlineRouteType:
+		// Deadlines scheduled by the old version may still be pending.
+		c.IsBreaking = true
+	}
+
+	if len(c.Dependents) != 0 {
+		c.IsBreaking = true
+	}
+
+	return c
+}
+
+// indexRoutes returns the handlers within app indexed by the routes they are
+// configured with.
+func indexRoutes(app *Application) map[routeKey][]Handler {
+	index := map[routeKey][]Handler{}
+
+	for _, h := range app.Handlers() {
+		for k := range routesByKey(h) {
+			index[k] = append(index[k], h)
+		}
+	}
+
+	for _, handlers := range index {
+		slices.SortFunc(
+			handlers,
+			func(a, b Handler) int {
+				return strings.Compare(a.Identity().GetName(), b.Identity().GetName())
+			},
+		)
+	}
+
+	return index
+}
+
+func routesByKey(h Handler) map[routeKey]*Route {
+	routes := map[routeKey]*Route{}
+
+	for _, r := range h.HandlerProperties().RouteComponents {
+		if k, ok := r.key(); ok {
+			routes[k] = r
+		}
+	}
+
+	return routes
+}
+
+// handlerLabel returns a short human-readable label for h, including its
+// handler type and identity.
+func handlerLabel(h Handler) string {
+	return h.HandlerType().String() + " " + identityLabel(h)
+}
+
+// identityLabel returns the identity of e in "name/key" format.
+func identityLabel(e Entity) string {
+	id := e.Identity()
+	return id.GetName() + "/" + id.GetKey().AsString()
+}
@@ -0,0 +1,357 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/config/runtimeconfig"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/internal/test"
+)
+
+func TestDiff(t *testing.T) {
+	const (
+		appKey         = "14769f7f-87fe-48dd-916e-5bcab6ba6aca"
+		aggregateKey   = "40ddf2a2-f053-485c-8621-1fc8a58f8ddf"
+		processKey     = "dc2b8d3b-f2e8-4ffe-9cb5-3e1a4f2e0d6a"
+		integrationKey = "4a0a6c0e-1e8a-4b4e-8a3e-3b3f5f5a1f5e"
+		projectionKey  = "f8f2b0f6-3b4b-4c43-8c1e-6b7d8e6c4c8a"
+		otherKey       = "9b6f1b0e-5f3a-4d3e-9c2f-7a8e0d1c2b3a"
+	)
+
+	app := func(name, key string, routes ...dogma.HandlerRoute) *Application {
+		return runtimeconfig.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity(name, key)
+				c.Routes(routes...)
+			},
+		})
+	}
+
+	aggregate := func(name, key string, routes ...dogma.AggregateRoute) dogma.HandlerRoute {
+		return dogma.ViaAggregate(&AggregateMessageHandlerStub[*AggregateRootStub]{
+			ConfigureFunc: func(c dogma.AggregateConfigurer) {
+				c.Identity(name, key)
+				c.Routes(routes...)
+			},
+		})
+	}
+
+	process := func(name, key string, routes ...dogma.ProcessRoute) dogma.HandlerRoute {
+		return dogma.ViaProcess(&ProcessMessageHandlerStub[*ProcessRootStub]{
+			ConfigureFunc: func(c dogma.ProcessConfigurer) {
+				c.Identity(name, key)
+				c.Routes(routes...)
+			},
+		})
+	}
+
+	integration := func(name, key string, disabled bool, routes ...dogma.IntegrationRoute) dogma.HandlerRoute {
+		return dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+			ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+				c.Identity(name, key)
+				c.Routes(routes...)
+				if disabled {
+					c.Disable()
+				}
+			},
+		})
+	}
+
+	projection := func(name, key string, routes ...dogma.ProjectionRoute) dogma.HandlerRoute {
+		return dogma.ViaProjection(&ProjectionMessageHandlerStub{
+			ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+				c.Identity(name, key)
+				c.Routes(routes...)
+			},
+		})
+	}
+
+	baselineAggregate := aggregate(
+		"aggregate", aggregateKey,
+		dogma.HandlesCommand[*CommandStub[TypeA]](),
+		dogma.RecordsEvent[*EventStub[TypeA]](),
+	)
+
+	baselineProcess := process(
+		"process", processKey,
+		dogma.HandlesEvent[*EventStub[TypeA]](),
+		dogma.ExecutesCommand[*CommandStub[TypeB]](),
+		dogma.SchedulesDeadline[*DeadlineStub[TypeA]](),
+	)
+
+	baselineIntegration := integration(
+		"integration", integrationKey, false,
+		dogma.HandlesCommand[*CommandStub[TypeB]](),
+	)
+
+	baselineProjection := projection(
+		"projection", projectionKey,
+		dogma.HandlesEvent[*EventStub[TypeA]](),
+	)
+
+	baseline := app(
+		"app", appKey,
+		baselineAggregate,
+		baselineProcess,
+		baselineIntegration,
+		baselineProjection,
+	)
+
+	cases := []struct {
+		Name        string
+		New         *Application
+		Description string
+	}{
+		{
+			Name:        "no changes",
+			New:         baseline,
+			Description: multiline(`no changes`, ``),
+		},
+		{
+			Name: "application identity changed",
+			New: app(
+				"renamed", otherKey,
+				baselineAggregate,
+				baselineProcess,
+				baselineIntegration,
+				baselineProjection,
+			),
+			Description: multiline(
+				`1 breaking change`,
+				`  - changed key of application "renamed" from `+appKey+` to `+otherKey,
+				`1 non-breaking change`,
+				`  - renamed application `+appKey+` from "app" to "renamed"`,
+				``,
+			),
+		},
+		{
+			Name: "handlers added, removed and re-identified",
+			New: app(
+				"app", appKey,
+				aggregate(
+					"renamed", aggregateKey,
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+					dogma.RecordsEvent[*EventStub[TypeA]](),
+				),
+				process(
+					"process", otherKey,
+					dogma.HandlesEvent[*EventStub[TypeA]](),
+					dogma.ExecutesCommand[*CommandStub[TypeB]](),
+					dogma.SchedulesDeadline[*DeadlineStub[TypeA]](),
+				),
+				baselineIntegration,
+				projection(
+					"other-projection", "c3f5f0a6-7e3d-4b0a-9a7e-5f1d9b3c2e4d",
+					dogma.HandlesEvent[*EventStub[TypeA]](),
+				),
+			),
+			Description: multiline(
+				`2 breaking changes`,
+				`  - changed key of process "process" from `+processKey+` to `+otherKey,
+				`  - removed projection projection/`+projectionKey,
+				`2 non-breaking changes`,
+				`  - renamed aggregate `+aggregateKey+` from "aggregate" to "renamed"`,
+				`  - added projection other-projection/c3f5f0a6-7e3d-4b0a-9a7e-5f1d9b3c2e4d`,
+				``,
+			),
+		},
+		{
+			Name: "event no longer recorded but still handled",
+			New: app(
+				"app", appKey,
+				aggregate(
+					"aggregate", aggregateKey,
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+					dogma.RecordsEvent[*EventStub[TypeB]](),
+				),
+				baselineProcess,
+				baselineIntegration,
+				baselineProjection,
+			),
+			Description: multiline(
+				`1 breaking change`,
+				`  - removed records-event route for *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA] from aggregate aggregate/`+aggregateKey+` (still required by process process/`+processKey+`, projection projection/`+projectionKey+`)`,
+				`1 non-breaking change`,
+				`  - added records-event route for *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeB] to aggregate aggregate/`+aggregateKey,
+				``,
+			),
+		},
+		{
+			Name: "command moved to a different handler",
+			New: app(
+				"app", appKey,
+				aggregate(
+					"aggregate", aggregateKey,
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+					dogma.HandlesCommand[*CommandStub[TypeB]](),
+					dogma.RecordsEvent[*EventStub[TypeA]](),
+				),
+				baselineProcess,
+				integration(
+					"integration", integrationKey, false,
+					dogma.HandlesCommand[*CommandStub[TypeC]](),
+				),
+				baselineProjection,
+			),
+			Description: multiline(
+				`1 breaking change`,
+				`  - moved handles-command route for *github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeB] from integration integration/`+integrationKey+` to aggregate aggregate/`+aggregateKey,
+				`1 non-breaking change`,
+				`  - added handles-command route for *github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeC] to integration integration/`+integrationKey,
+				``,
+			),
+		},
+		{
+			Name: "command moved from a removed handler",
+			New: app(
+				"app", appKey,
+				baselineProcess,
+				integration(
+					"integration", integrationKey, false,
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+					dogma.HandlesCommand[*CommandStub[TypeB]](),
+				),
+				baselineProjection,
+			),
+			Description: multiline(
+				`3 breaking changes`,
+				`  - removed aggregate aggregate/`+aggregateKey,
+				`  - moved handles-command route for *github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA] from aggregate aggregate/`+aggregateKey+` to integration integration/`+integrationKey,
+				`  - removed records-event route for *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA] from aggregate aggregate/`+aggregateKey+` (still required by process process/`+processKey+`, projection projection/`+projectionKey+`)`,
+				``,
+			),
+		},
+		{
+			Name: "deadline no longer scheduled",
+			New: app(
+				"app", appKey,
+				baselineAggregate,
+				process(
+					"process", processKey,
+					dogma.HandlesEvent[*EventStub[TypeA]](),
+					dogma.ExecutesCommand[*CommandStub[TypeB]](),
+				),
+				baselineIntegration,
+				baselineProjection,
+			),
+			Description: multiline(
+				`1 breaking change`,
+				`  - removed schedules-deadline route for *github.com/dogmatiq/enginekit/enginetest/stubs.DeadlineStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA] from process process/`+processKey,
+				``,
+			),
+		},
+		{
+			Name: "handler type changed",
+			New: app(
+				"app", appKey,
+				baselineAggregate,
+				baselineProcess,
+				aggregate(
+					"integration", integrationKey,
+					dogma.HandlesCommand[*CommandStub[TypeB]](),
+					dogma.RecordsEvent[*EventStub[TypeB]](),
+				),
+				projection(
+					"projection", projectionKey,
+					dogma.HandlesEvent[*EventStub[TypeA]](),
+					dogma.HandlesEvent[*EventStub[TypeB]](),
+				),
+			),
+			Description: multiline(
+				`1 breaking change`,
+				`  - changed type of integration/`+integrationKey+` from integration to aggregate`,
+				`2 non-breaking changes`,
+				`  - added records-event route for *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeB] to aggregate integration/`+integrationKey,
+				`  - added handles-event route for *github.com/dogmatiq/enginekit/enginetest/stubs.EventStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeB] to projection projection/`+projectionKey,
+				``,
+			),
+		},
+		{
+			Name: "handler disabled",
+			New: app(
+				"app", appKey,
+				baselineAggregate,
+				baselineProcess,
+				integration(
+					"integration", integrationKey, true,
+					dogma.HandlesCommand[*CommandStub[TypeB]](),
+				),
+				baselineProjection,
+			),
+			Description: multiline(
+				`1 non-breaking change`,
+				`  - disabled integration integration/`+integrationKey,
+				``,
+			),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			changes := Diff(baseline, c.New)
+
+			test.Expect(
+				t,
+				"unexpected description",
+				changes.Description(),
+				c.Description,
+			)
+
+			test.Expect(
+				t,
+				"unexpected breaking status",
+				changes.IsBreaking(),
+				len(changes.Breaking()) != 0,
+			)
+		})
+	}
+
+	t.Run("it keys changes by identity key", func(t *testing.T) {
+		changes := Diff(
+			baseline,
+			app(
+				"app", appKey,
+				aggregate(
+					"renamed", aggregateKey,
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+					dogma.RecordsEvent[*EventStub[TypeA]](),
+				),
+				baselineProcess,
+				baselineIntegration,
+				baselineProjection,
+			),
+		)
+
+		type change struct {
+			Type       ChangeType
+			IsBreaking bool
+			Key        string
+		}
+
+		var got []change
+		for _, c := range changes.Changes {
+			got = append(got, change{c.Type, c.IsBreaking, c.Key})
+		}
+
+		test.Expect(
+			t,
+			"unexpected changes",
+			got,
+			[]change{
+				{HandlerRenamedChangeType, false, aggregateKey},
+			},
+		)
+	})
+
+	t.Run("it panics if either application is invalid", func(t *testing.T) {
+		test.ExpectPanic(
+			t,
+			"no identity",
+			func() {
+				Diff(baseline, &Application{})
+			},
+		)
+	})
+}
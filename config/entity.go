@@ -9,6 +9,7 @@ import (
 	"github.com/dogmatiq/enginekit/optional"
 	"github.com/dogmatiq/enginekit/protobuf/identitypb"
 	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+	"google.golang.org/protobuf/proto"
 )
 
 // An Entity is a [Component] that that represents the configuration of a Dogma
@@ -45,6 +46,10 @@ type EntityCommon struct {
 	ComponentCommon
 	TypeName           optional.Optional[string]
 	IdentityComponents []*Identity
+
+	// Extensions is the set of engine-specific extensions attached to the
+	// entity. Use [SetExtension] and [GetExtension] to manipulate them.
+	Extensions []proto.Message
 }
 
 // EntityProperties returns the properties common to all [Entity] types.
@@ -174,4 +179,10 @@ func describeEntity[T any](
 	for _, i := range p.IdentityComponents {
 		ctx.DescribeChild(i)
 	}
+
+	for _, x := range p.Extensions {
+		ctx.renderer.StartChild()
+		ctx.Printf("extension %s\n", extensionName(x))
+		ctx.renderer.EndChild()
+	}
 }
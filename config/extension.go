@@ -0,0 +1,70 @@
+package config
+
+import (
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// SetExtension sets an engine-specific extension on e, replacing any existing
+// extension of the same type.
+//
+// Extensions allow engines to attach their own settings to an application or
+// handler, such as concurrency limits or retry policies. Each extension is
+// identified by its Protocol Buffers message type. An [anypb.Any] extension is
+// identified by the message type named by its type URL, such that extensions of
+// types that are not present in the Protocol Buffers registry do not replace
+// each other.
+func SetExtension[T proto.Message](e Entity, x T) {
+	if !x.ProtoReflect().IsValid() {
+		panic("extension must not be nil")
+	}
+
+	p := e.EntityProperties()
+	name := extensionName(x)
+
+	for i, ext := range p.Extensions {
+		if extensionName(ext) == name {
+			p.Extensions[i] = x
+			return
+		}
+	}
+
+	p.Extensions = append(p.Extensions, x)
+}
+
+// GetExtension returns the extension of type T that is set on e.
+//
+// The second return value reports whether an extension of the matching type
+// was present.
+func GetExtension[T proto.Message](e Entity) (T, bool) {
+	for _, ext := range e.EntityProperties().Extensions {
+		if x, ok := ext.(T); ok {
+			return x, true
+		}
+	}
+
+	var zero T
+	return zero, false
+}
+
+// RemoveExtension removes the extension of type T from e, if present.
+func RemoveExtension[T proto.Message](e Entity) {
+	p := e.EntityProperties()
+
+	for i, ext := range p.Extensions {
+		if _, ok := ext.(T); ok {
+			p.Extensions = append(p.Extensions[:i:i], p.Extensions[i+1:]...)
+			return
+		}
+	}
+}
+
+// extensionName returns the name of the message type that identifies the
+// extension x.
+func extensionName(x proto.Message) protoreflect.FullName {
+	if x, ok := x.(*anypb.Any); ok {
+		return x.MessageName()
+	}
+	return x.ProtoReflect().Descriptor().FullName()
+}
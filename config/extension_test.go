@@ -0,0 +1,112 @@
+package config_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/config/internal/configbuilder"
+	"github.com/dogmatiq/enginekit/internal/test"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestExtension(t *testing.T) {
+	newHandler := func() *Integration {
+		return configbuilder.Integration(func(b *configbuilder.IntegrationBuilder) {
+			b.TypeName("pkg.SomeIntegration")
+			b.Identity(func(b *configbuilder.IdentityBuilder) {
+				b.Name("name")
+				b.Key("19cb98d5-dd17-4daf-ae00-1b413b7b899a")
+			})
+			b.Route(func(b *configbuilder.RouteBuilder) {
+				b.RouteType(HandlesCommandRouteType)
+				b.MessageTypeName("pkg.SomeCommand")
+			})
+		})
+	}
+
+	t.Run("it returns the extension of the matching type", func(t *testing.T) {
+		h := newHandler()
+		SetExtension(h, durationpb.New(5*time.Second))
+		SetExtension(h, wrapperspb.String("<partition>"))
+
+		d, ok := GetExtension[*durationpb.Duration](h)
+		test.Expect(t, "unexpected presence", ok, true)
+		test.Expect(t, "unexpected value", d.AsDuration(), 5*time.Second)
+
+		s, ok := GetExtension[*wrapperspb.StringValue](h)
+		test.Expect(t, "unexpected presence", ok, true)
+		test.Expect(t, "unexpected value", s.GetValue(), "<partition>")
+
+		_, ok = GetExtension[*wrapperspb.Int32Value](h)
+		test.Expect(t, "unexpected presence", ok, false)
+	})
+
+	t.Run("it replaces an existing extension of the same type", func(t *testing.T) {
+		h := newHandler()
+		SetExtension(h, durationpb.New(5*time.Second))
+		SetExtension(h, durationpb.New(10*time.Second))
+
+		d, _ := GetExtension[*durationpb.Duration](h)
+		test.Expect(t, "unexpected value", d.AsDuration(), 10*time.Second)
+		test.Expect(t, "unexpected extension count", len(h.Extensions), 1)
+	})
+
+	t.Run("it distinguishes between unresolved extensions by their type URL", func(t *testing.T) {
+		h := newHandler()
+
+		a := &anypb.Any{TypeUrl: "type.googleapis.com/pkg.ExtensionA", Value: []byte("<a>")}
+		b := &anypb.Any{TypeUrl: "type.googleapis.com/pkg.ExtensionB", Value: []byte("<b>")}
+		SetExtension(h, a)
+		SetExtension(h, b)
+
+		test.Expect(t, "unexpected extensions", h.Extensions, []proto.Message{a, b})
+
+		replacement := &anypb.Any{TypeUrl: "type.googleapis.com/pkg.ExtensionA", Value: []byte("<replacement>")}
+		SetExtension(h, replacement)
+
+		test.Expect(t, "unexpected extensions", h.Extensions, []proto.Message{replacement, b})
+	})
+
+	t.Run("it removes the extension of the matching type", func(t *testing.T) {
+		h := newHandler()
+		SetExtension(h, durationpb.New(5*time.Second))
+		SetExtension(h, wrapperspb.String("<partition>"))
+		RemoveExtension[*durationpb.Duration](h)
+
+		_, ok := GetExtension[*durationpb.Duration](h)
+		test.Expect(t, "unexpected presence", ok, false)
+		test.Expect(t, "unexpected extension count", len(h.Extensions), 1)
+	})
+
+	t.Run("it panics if the extension is nil", func(t *testing.T) {
+		test.ExpectPanic(
+			t,
+			"extension must not be nil",
+			func() {
+				SetExtension(newHandler(), (*durationpb.Duration)(nil))
+			},
+		)
+	})
+
+	t.Run("it includes extensions in the description", func(t *testing.T) {
+		h := newHandler()
+		SetExtension(h, durationpb.New(5*time.Second))
+
+		test.Expect(
+			t,
+			"unexpected description",
+			Description(h),
+			multiline(
+				`unvalidated integration pkg.SomeIntegration (value unavailable)`,
+				`  - unvalidated identity name/19cb98d5-dd17-4daf-ae00-1b413b7b899a`,
+				`  - extension google.protobuf.Duration`,
+				`  - unvalidated handles-command route for pkg.SomeCommand (type unavailable)`,
+				``,
+			),
+		)
+	})
+}
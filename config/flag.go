@@ -29,6 +29,15 @@ type Flag[S Symbol] struct {
 
 	// Value is the boolean value to which the flag was set, if known.
 	Value optional.Optional[bool]
+
+	// Options is the set of options that were supplied when the flag was set,
+	// such as the [dogma.DisableOption] values passed to a handler's Disable()
+	// method.
+	//
+	// Options are described using their default string representation, as
+	// per [fmt.Sprint]. Options that implement [fmt.Stringer] can therefore
+	// control how they are displayed.
+	Options []any
 }
 
 func (f *Flag[S]) String() string {
@@ -61,5 +70,18 @@ func (f *Flag[S]) describe(ctx *describeContext) {
 		ctx.Printf(", set to %t", v)
 	}
 
+	if opts := f.optionStrings(); len(opts) != 0 {
+		ctx.Printf(" (options: %s)", strings.Join(opts, ", "))
+	}
+
 	ctx.Print("\n")
 }
+
+// optionStrings returns the string representation of each of f's options.
+func (f *Flag[S]) optionStrings() []string {
+	var opts []string
+	for _, opt := range f.Options {
+		opts = append(opts, fmt.Sprint(opt))
+	}
+	return opts
+}
@@ -0,0 +1,8 @@
+// Package flowgraph renders the message flow of a [config.Application] as a
+// graph, in either Graphviz DOT or Mermaid flowchart format.
+//
+// Each [config.Handler] is a node in the graph, and each message type that
+// flows between handlers is an edge. Messages that originate from, or are
+// delivered to, something outside of the application are connected to a
+// single "external" node.
+package flowgraph
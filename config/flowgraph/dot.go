@@ -0,0 +1,80 @@
+package flowgraph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dogmatiq/enginekit/config"
+)
+
+// WriteDOT writes a Graphviz DOT representation of g to w.
+func (g *Graph) WriteDOT(w io.Writer) (int, error) {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "digraph %s {\n", dotQuote(g.Name))
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [fontname=\"sans-serif\", style=filled];\n")
+	b.WriteString("  edge [fontname=\"sans-serif\"];\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(
+			&b,
+			"  %s [label=%s, %s];\n",
+			dotQuote(n.ID),
+			dotQuote(n.Label()),
+			dotNodeStyle(n),
+		)
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(
+			&b,
+			"  %s -> %s [label=%s];\n",
+			dotQuote(e.From.ID),
+			dotQuote(e.To.ID),
+			dotQuote(e.Label()),
+		)
+	}
+
+	b.WriteString("}\n")
+
+	return io.WriteString(w, b.String())
+}
+
+// DOT returns a Graphviz DOT representation of g.
+func (g *Graph) DOT() string {
+	var w strings.Builder
+
+	if _, err := g.WriteDOT(&w); err != nil {
+		panic(err)
+	}
+
+	return w.String()
+}
+
+func dotNodeStyle(n *Node) string {
+	if n.IsExternal() {
+		return `shape=circle, style=dashed`
+	}
+
+	style := config.MapByHandlerType(
+		n.Handler.HandlerType(),
+		`shape=box, fillcolor="#fde68a"`,
+		`shape=hexagon, fillcolor="#c4b5fd"`,
+		`shape=parallelogram, fillcolor="#fca5a5"`,
+		`shape=cylinder, fillcolor="#86efac"`,
+	)
+
+	if n.Handler.IsDisabled() {
+		style += `, style="filled,dashed"`
+	}
+
+	return style
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
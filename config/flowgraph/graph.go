@@ -0,0 +1,275 @@
+package flowgraph
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// Graph is a directed graph of the messages that flow between the handlers of
+// a [config.Application].
+type Graph struct {
+	// Name is the name of the graph, which is the identity name of the
+	// application.
+	Name string
+
+	// Nodes is the set of nodes in the graph, ordered by ID.
+	Nodes []*Node
+
+	// Edges is the set of edges in the graph.
+	Edges []*Edge
+}
+
+// Node is a node in a [Graph].
+type Node struct {
+	// ID is a unique identifier for the node within the graph.
+	ID string
+
+	// Handler is the handler that the node represents, or nil if the node
+	// represents the "outside world".
+	Handler config.Handler
+}
+
+// IsExternal returns true if the node represents the "outside world", rather
+// than a handler within the application.
+func (n *Node) IsExternal() bool {
+	return n.Handler == nil
+}
+
+// Label returns a human-readable label for the node.
+func (n *Node) Label() string {
+	if n.IsExternal() {
+		return "external"
+	}
+	return n.Handler.Identity().GetName()
+}
+
+// Edge is an edge in a [Graph], representing one or more message types that
+// flow from one node to another.
+type Edge struct {
+	From, To *Node
+
+	// MessageTypes is the set of message types that flow along the edge. It
+	// contains exactly one element unless the graph was built with
+	// [WithCollapsedEdges].
+	MessageTypes []message.Type
+}
+
+// Label returns a human-readable label for the edge.
+//
+// If the edge represents a single message type, the label is the message
+// type's name, suffixed by its [message.Kind] symbol. Otherwise, it is a count
+// of the message types of each kind.
+func (e *Edge) Label() string {
+	if len(e.MessageTypes) == 1 {
+		mt := e.MessageTypes[0]
+		return strings.TrimPrefix(mt.String(), "*") + mt.Kind().Symbol()
+	}
+
+	counts := map[message.Kind]int{}
+	for _, mt := range e.MessageTypes {
+		counts[mt.Kind()]++
+	}
+
+	var labels []string
+	for _, k := range []message.Kind{message.CommandKind, message.EventKind, message.DeadlineKind} {
+		if n := counts[k]; n != 0 {
+			labels = append(labels, fmt.Sprintf("%d%s", n, k.Symbol()))
+		}
+	}
+
+	return strings.Join(labels, " ")
+}
+
+// Option is an option that changes the behavior of [Build].
+type Option func(*buildOptions)
+
+type buildOptions struct {
+	Filters   []config.RouteSetFilter
+	Collapsed bool
+}
+
+// WithRouteSetFilter is an [Option] that limits the graph to the routes that
+// match all of the given filters.
+func WithRouteSetFilter(filters ...config.RouteSetFilter) Option {
+	return func(opts *buildOptions) {
+		opts.Filters = append(opts.Filters, filters...)
+	}
+}
+
+// WithCollapsedEdges is an [Option] that combines all of the message types
+// that flow between the same pair of nodes into a single edge.
+func WithCollapsedEdges() Option {
+	return func(opts *buildOptions) {
+		opts.Collapsed = true
+	}
+}
+
+// Build returns the message flow graph of app.
+//
+// It panics if the application's configuration is incomplete or invalid.
+func Build(app *config.Application, options ...Option) *Graph {
+	var opts buildOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	b := &builder{
+		Graph: &Graph{
+			Name: app.Identity().GetName(),
+		},
+		Nodes: map[config.Handler]*Node{},
+	}
+
+	routes := app.RouteSet().Filter(opts.Filters...)
+
+	byMessageType := map[message.Type]*flow{}
+	for r, h := range routes.Routes() {
+		mt := r.MessageType.Get()
+
+		f, ok := byMessageType[mt]
+		if !ok {
+			f = &flow{}
+			byMessageType[mt] = f
+		}
+
+		dir := r.RouteType.Get().Direction()
+
+		if dir.Has(config.OutboundDirection) {
+			f.Producers = append(f.Producers, b.handlerNode(h))
+		}
+
+		if dir.Has(config.InboundDirection) {
+			f.Consumers = append(f.Consumers, b.handlerNode(h))
+		}
+	}
+
+	b.assignIDs()
+
+	for mt, f := range byMessageType {
+		if len(f.Producers) == 0 {
+			f.Producers = []*Node{b.externalNode()}
+		}
+
+		if len(f.Consumers) == 0 {
+			f.Consumers = []*Node{b.externalNode()}
+		}
+
+		for _, from := range f.Producers {
+			for _, to := range f.Consumers {
+				b.addEdge(from, to, mt, opts.Collapsed)
+			}
+		}
+	}
+
+	b.sort()
+
+	return b.Graph
+}
+
+// flow is the set of nodes that produce and consume a specific message type.
+type flow struct {
+	Producers []*Node
+	Consumers []*Node
+}
+
+type builder struct {
+	Graph    *Graph
+	Nodes    map[config.Handler]*Node
+	External *Node
+}
+
+func (b *builder) handlerNode(h config.Handler) *Node {
+	if n, ok := b.Nodes[h]; ok {
+		return n
+	}
+
+	n := &Node{Handler: h}
+	b.Nodes[h] = n
+	b.Graph.Nodes = append(b.Graph.Nodes, n)
+
+	return n
+}
+
+func (b *builder) externalNode() *Node {
+	if b.External == nil {
+		b.External = &Node{ID: "external"}
+		b.Graph.Nodes = append(b.Graph.Nodes, b.External)
+	}
+	return b.External
+}
+
+// assignIDs assigns a stable ID to each handler node, based on the handler
+// type and the order of the handlers' names.
+func (b *builder) assignIDs() {
+	slices.SortFunc(
+		b.Graph.Nodes,
+		func(x, y *Node) int {
+			return cmp.Or(
+				cmp.Compare(x.Handler.HandlerType(), y.Handler.HandlerType()),
+				cmp.Compare(x.Label(), y.Label()),
+			)
+		},
+	)
+
+	counts := map[config.HandlerType]int{}
+
+	for _, n := range b.Graph.Nodes {
+		t := n.Handler.HandlerType()
+		n.ID = fmt.Sprintf("%s%d", t, counts[t])
+		counts[t]++
+	}
+}
+
+func (b *builder) addEdge(from, to *Node, mt message.Type, collapsed bool) {
+	if collapsed {
+		for _, e := range b.Graph.Edges {
+			if e.From == from && e.To == to {
+				e.MessageTypes = append(e.MessageTypes, mt)
+				return
+			}
+		}
+	}
+
+	b.Graph.Edges = append(
+		b.Graph.Edges,
+		&Edge{
+			From:         from,
+			To:           to,
+			MessageTypes: []message.Type{mt},
+		},
+	)
+}
+
+func (b *builder) sort() {
+	slices.SortFunc(
+		b.Graph.Nodes,
+		func(x, y *Node) int {
+			return cmp.Compare(x.ID, y.ID)
+		},
+	)
+
+	for _, e := range b.Graph.Edges {
+		slices.SortFunc(
+			e.MessageTypes,
+			func(x, y message.Type) int {
+				return cmp.Compare(x.Name(), y.Name())
+			},
+		)
+	}
+
+	slices.SortFunc(
+		b.Graph.Edges,
+		func(x, y *Edge) int {
+			return cmp.Or(
+				cmp.Compare(x.From.ID, y.From.ID),
+				cmp.Compare(x.To.ID, y.To.ID),
+				cmp.Compare(x.MessageTypes[0].Name(), y.MessageTypes[0].Name()),
+			)
+		},
+	)
+}
@@ -0,0 +1,198 @@
+package flowgraph_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config"
+	. "github.com/dogmatiq/enginekit/config/flowgraph"
+	"github.com/dogmatiq/enginekit/config/runtimeconfig"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	"github.com/dogmatiq/enginekit/message"
+)
+
+func TestGraph(t *testing.T) {
+	app := runtimeconfig.FromApplication(&ApplicationStub{
+		ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+			c.Identity("app", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+			c.Routes(
+				dogma.ViaAggregate(&AggregateMessageHandlerStub[*AggregateRootStub]{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("orders", "40ddf2a2-f053-485c-8621-1fc8a58f8ddf")
+						c.Routes(
+							dogma.HandlesCommand[*CommandStub[TypeA]](),
+							dogma.RecordsEvent[*EventStub[TypeA]](),
+							dogma.RecordsEvent[*EventStub[TypeB]](),
+						)
+					},
+				}),
+				dogma.ViaProcess(&ProcessMessageHandlerStub[*ProcessRootStub]{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("fulfilment", "dc2b8d3b-f2e8-4ffe-9cb5-3e1a4f2e0d6a")
+						c.Routes(
+							dogma.HandlesEvent[*EventStub[TypeA]](),
+							dogma.ExecutesCommand[*CommandStub[TypeB]](),
+							dogma.SchedulesDeadline[*DeadlineStub[TypeA]](),
+						)
+					},
+				}),
+				dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+					ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+						c.Identity("shipping", "4a0a6c0e-1e8a-4b4e-8a3e-3b3f5f5a1f5e")
+						c.Routes(
+							dogma.HandlesCommand[*CommandStub[TypeB]](),
+						)
+						c.Disable()
+					},
+				}),
+				dogma.ViaProjection(&ProjectionMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+						c.Identity("reports", "f8f2b0f6-3b4b-4c43-8c1e-6b7d8e6c4c8a")
+						c.Routes(
+							dogma.HandlesEvent[*EventStub[TypeA]](),
+							dogma.HandlesEvent[*EventStub[TypeB]](),
+						)
+					},
+				}),
+			)
+		},
+	})
+
+	t.Run("func Build()", func(t *testing.T) {
+		t.Run("it connects producers to consumers", func(t *testing.T) {
+			g := Build(app)
+
+			type edge struct {
+				From, To, Label string
+			}
+
+			var got []edge
+			for _, e := range g.Edges {
+				got = append(got, edge{e.From.Label(), e.To.Label(), e.Label()})
+			}
+
+			Expect(
+				t,
+				"unexpected edges",
+				got,
+				[]edge{
+					{"orders", "fulfilment", "stubs.EventStub[TypeA]!"},
+					{"orders", "reports", "stubs.EventStub[TypeA]!"},
+					{"orders", "reports", "stubs.EventStub[TypeB]!"},
+					{"external", "orders", "stubs.CommandStub[TypeA]?"},
+					{"fulfilment", "shipping", "stubs.CommandStub[TypeB]?"},
+					{"fulfilment", "fulfilment", "stubs.DeadlineStub[TypeA]@"},
+				},
+			)
+		})
+
+		t.Run("it collapses edges between the same nodes", func(t *testing.T) {
+			g := Build(app, WithCollapsedEdges())
+
+			var got []string
+			for _, e := range g.Edges {
+				got = append(got, e.From.ID+" -> "+e.To.ID+": "+e.Label())
+			}
+
+			Expect(
+				t,
+				"unexpected edges",
+				got,
+				[]string{
+					"aggregate0 -> process0: stubs.EventStub[TypeA]!",
+					"aggregate0 -> projection0: 2!",
+					"external -> aggregate0: stubs.CommandStub[TypeA]?",
+					"process0 -> integration0: stubs.CommandStub[TypeB]?",
+					"process0 -> process0: stubs.DeadlineStub[TypeA]@",
+				},
+			)
+		})
+
+		t.Run("it filters routes", func(t *testing.T) {
+			g := Build(
+				app,
+				WithRouteSetFilter(
+					config.FilterByMessageKind(message.CommandKind),
+				),
+			)
+
+			var got []string
+			for _, e := range g.Edges {
+				got = append(got, e.From.ID+" -> "+e.To.ID+": "+e.Label())
+			}
+
+			Expect(
+				t,
+				"unexpected edges",
+				got,
+				[]string{
+					"external -> aggregate0: stubs.CommandStub[TypeA]?",
+					"process0 -> integration0: stubs.CommandStub[TypeB]?",
+				},
+			)
+		})
+	})
+
+	t.Run("func DOT()", func(t *testing.T) {
+		Expect(
+			t,
+			"unexpected DOT output",
+			Build(app).DOT(),
+			multiline(
+				`digraph "app" {`,
+				`  rankdir=LR;`,
+				`  node [fontname="sans-serif", style=filled];`,
+				`  edge [fontname="sans-serif"];`,
+				`  "aggregate0" [label="orders", shape=box, fillcolor="#fde68a"];`,
+				`  "external" [label="external", shape=circle, style=dashed];`,
+				`  "integration0" [label="shipping", shape=parallelogram, fillcolor="#fca5a5", style="filled,dashed"];`,
+				`  "process0" [label="fulfilment", shape=hexagon, fillcolor="#c4b5fd"];`,
+				`  "projection0" [label="reports", shape=cylinder, fillcolor="#86efac"];`,
+				`  "aggregate0" -> "process0" [label="stubs.EventStub[TypeA]!"];`,
+				`  "aggregate0" -> "projection0" [label="stubs.EventStub[TypeA]!"];`,
+				`  "aggregate0" -> "projection0" [label="stubs.EventStub[TypeB]!"];`,
+				`  "external" -> "aggregate0" [label="stubs.CommandStub[TypeA]?"];`,
+				`  "process0" -> "integration0" [label="stubs.CommandStub[TypeB]?"];`,
+				`  "process0" -> "process0" [label="stubs.DeadlineStub[TypeA]@"];`,
+				`}`,
+			),
+		)
+	})
+
+	t.Run("func Mermaid()", func(t *testing.T) {
+		Expect(
+			t,
+			"unexpected Mermaid output",
+			Build(app, WithCollapsedEdges()).Mermaid(),
+			multiline(
+				`flowchart LR`,
+				`  aggregate0["orders"]`,
+				`  external(("external"))`,
+				`  integration0[/"shipping"/]`,
+				`  process0{{"fulfilment"}}`,
+				`  projection0[("reports")]`,
+				`  aggregate0 -- "stubs.EventStub[TypeA]!" --> process0`,
+				`  aggregate0 -- "2!" --> projection0`,
+				`  external -- "stubs.CommandStub[TypeA]?" --> aggregate0`,
+				`  process0 -- "stubs.CommandStub[TypeB]?" --> integration0`,
+				`  process0 -- "stubs.DeadlineStub[TypeA]@" --> process0`,
+				`  class aggregate0 aggregate`,
+				`  class integration0 integration`,
+				`  class integration0 disabled`,
+				`  class process0 process`,
+				`  class projection0 projection`,
+				`  classDef aggregate fill:#fde68a`,
+				`  classDef process fill:#c4b5fd`,
+				`  classDef integration fill:#fca5a5`,
+				`  classDef projection fill:#86efac`,
+				`  classDef disabled stroke-dasharray:5 5`,
+			),
+		)
+	})
+}
+
+func multiline(lines ...string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
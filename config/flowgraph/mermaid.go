@@ -0,0 +1,81 @@
+package flowgraph
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dogmatiq/enginekit/config"
+)
+
+// WriteMermaid writes a Mermaid flowchart representation of g to w.
+func (g *Graph) WriteMermaid(w io.Writer) (int, error) {
+	var b strings.Builder
+
+	b.WriteString("flowchart LR\n")
+
+	for _, n := range g.Nodes {
+		fmt.Fprintf(&b, "  %s\n", mermaidNode(n))
+	}
+
+	for _, e := range g.Edges {
+		fmt.Fprintf(
+			&b,
+			"  %s -- %s --> %s\n",
+			e.From.ID,
+			mermaidQuote(e.Label()),
+			e.To.ID,
+		)
+	}
+
+	for _, n := range g.Nodes {
+		if n.IsExternal() {
+			continue
+		}
+
+		fmt.Fprintf(&b, "  class %s %s\n", n.ID, n.Handler.HandlerType())
+
+		if n.Handler.IsDisabled() {
+			fmt.Fprintf(&b, "  class %s disabled\n", n.ID)
+		}
+	}
+
+	b.WriteString("  classDef aggregate fill:#fde68a\n")
+	b.WriteString("  classDef process fill:#c4b5fd\n")
+	b.WriteString("  classDef integration fill:#fca5a5\n")
+	b.WriteString("  classDef projection fill:#86efac\n")
+	b.WriteString("  classDef disabled stroke-dasharray:5 5\n")
+
+	return io.WriteString(w, b.String())
+}
+
+// Mermaid returns a Mermaid flowchart representation of g.
+func (g *Graph) Mermaid() string {
+	var w strings.Builder
+
+	if _, err := g.WriteMermaid(&w); err != nil {
+		panic(err)
+	}
+
+	return w.String()
+}
+
+func mermaidNode(n *Node) string {
+	label := mermaidQuote(n.Label())
+
+	if n.IsExternal() {
+		return n.ID + "((" + label + "))"
+	}
+
+	return config.MapByHandlerType(
+		n.Handler.HandlerType(),
+		n.ID+"["+label+"]",
+		n.ID+"{{"+label+"}}",
+		n.ID+"[/"+label+"/]",
+		n.ID+"[("+label+")]",
+	)
+}
+
+func mermaidQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "#quot;") + `"`
+}
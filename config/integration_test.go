@@ -243,6 +243,32 @@ func TestIntegration(t *testing.T) {
 					},
 				}),
 			},
+			{
+				Name:   "disabled with options",
+				String: `integration:SomeIntegration`,
+				Description: multiline(
+					`valid integration pkg.SomeIntegration (value unavailable)`,
+					`  - valid identity name/19cb98d5-dd17-4daf-ae00-1b413b7b899a`,
+					`  - valid handles-command route for pkg.SomeCommand (type unavailable)`,
+					`  - valid disabled flag, set to true (options: maintenance, requested by ops)`,
+				),
+				Component: configbuilder.Integration(func(b *configbuilder.IntegrationBuilder) {
+					b.TypeName("pkg.SomeIntegration")
+					b.Identity(func(b *configbuilder.IdentityBuilder) {
+						b.Name("name")
+						b.Key("19cb98d5-dd17-4daf-ae00-1b413b7b899a")
+					})
+					b.Route(func(b *configbuilder.RouteBuilder) {
+						b.RouteType(HandlesCommandRouteType)
+						b.MessageTypeName("pkg.SomeCommand")
+					})
+					b.Disabled(func(b *configbuilder.FlagBuilder[Disabled]) {
+						b.Value(true)
+						b.Option("maintenance")
+						b.Option("requested by ops")
+					})
+				}),
+			},
 			{
 				Name:   "no runtime values",
 				String: `integration:SomeIntegration`,
@@ -22,6 +22,12 @@ func (b *FlagBuilder[S]) Value(v bool) {
 	b.target.Value = optional.Some(v)
 }
 
+// Option adds an option that was supplied when the target [config.Flag] was
+// set.
+func (b *FlagBuilder[S]) Option(opt any) {
+	b.target.Options = append(b.target.Options, opt)
+}
+
 // Partial marks the compomnent as partially configured.
 func (b *FlagBuilder[S]) Partial() {
 	b.target.IsPartial = true
@@ -50,6 +50,11 @@ func (b *RouteBuilder) RouteType(t config.RouteType) {
 	b.target.RouteType = optional.Some(t)
 }
 
+// MessageTypeID sets the message type ID of the route.
+func (b *RouteBuilder) MessageTypeID(id string) {
+	b.target.MessageTypeID = optional.Some(id)
+}
+
 // MessageTypeName sets the message type name of the route.
 func (b *RouteBuilder) MessageTypeName(name string) {
 	b.target.MessageTypeName = optional.Some(name)
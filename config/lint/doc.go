@@ -0,0 +1,11 @@
+// Package lint reports design smells within a [config.Application].
+//
+// Unlike [config.Validate], which rejects configurations that an engine cannot
+// host, the rules in this package identify configurations that are valid but
+// are very likely to be mistakes, such as an event that is recorded but never
+// consumed.
+//
+// Rules are held in a [Registry]. Each rule has a default [Severity] which can
+// be overridden, and findings can be suppressed for specific handlers. The
+// resulting [Report] can be included in the output of [config.Describe].
+package lint
@@ -0,0 +1,160 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dogmatiq/enginekit/config"
+)
+
+// Finding is a design smell reported by a [Rule].
+type Finding struct {
+	// RuleID is the ID of the rule that reported the finding.
+	RuleID string
+
+	// Severity is the severity of the finding.
+	Severity Severity
+
+	// Handler is the handler that the finding is about.
+	Handler config.Handler
+
+	// Message is a human-readable description of the finding.
+	Message string
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s [%s]: %s", f.Severity, f.RuleID, f.Message)
+}
+
+// Report is the result of linting an application.
+type Report struct {
+	Findings []Finding
+}
+
+// HasSeverity returns true if the report contains any findings with a severity
+// of at least s.
+func (r Report) HasSeverity(s Severity) bool {
+	return slices.ContainsFunc(
+		r.Findings,
+		func(f Finding) bool {
+			return f.Severity >= s
+		},
+	)
+}
+
+// FindingsFor returns the findings about h.
+func (r Report) FindingsFor(h config.Handler) []Finding {
+	var findings []Finding
+	for _, f := range r.Findings {
+		if f.Handler == h {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// DescribeOption returns a [config.DescribeOption] that includes the findings
+// in the description of the handlers they are about.
+func (r Report) DescribeOption() config.DescribeOption {
+	return config.WithAnnotations(
+		func(c config.Component) []string {
+			h, ok := c.(config.Handler)
+			if !ok {
+				return nil
+			}
+
+			var annotations []string
+			for _, f := range r.FindingsFor(h) {
+				annotations = append(annotations, f.String())
+			}
+			return annotations
+		},
+	)
+}
+
+// Option is an option that changes the behavior of [Lint].
+type Option func(*lintOptions)
+
+// WithRegistry is an [Option] that sets the registry of rules to check. By
+// default, the rules in [DefaultRegistry] are checked.
+func WithRegistry(r *Registry) Option {
+	return func(opts *lintOptions) {
+		opts.Registry = r
+	}
+}
+
+// WithSeverity is an [Option] that overrides the severity of the findings
+// reported by the rule with the given ID.
+func WithSeverity(ruleID string, s Severity) Option {
+	return func(opts *lintOptions) {
+		opts.Severities[ruleID] = s
+	}
+}
+
+// Suppress is an [Option] that discards the findings reported by the rule with
+// the given ID about specific handlers.
+//
+// Each handler is specified by its identity name or key.
+func Suppress(ruleID string, handlers ...string) Option {
+	return func(opts *lintOptions) {
+		opts.Suppressions[ruleID] = append(opts.Suppressions[ruleID], handlers...)
+	}
+}
+
+type lintOptions struct {
+	Registry     *Registry
+	Severities   map[string]Severity
+	Suppressions map[string][]string
+}
+
+func (opts *lintOptions) isSuppressed(ruleID string, h config.Handler) bool {
+	id := h.Identity()
+
+	for _, x := range opts.Suppressions[ruleID] {
+		if x == id.GetName() || x == id.GetKey().AsString() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Lint checks app against a set of rules and returns a report of the findings.
+//
+// It panics if app is incomplete or invalid, as it relies on the application's
+// [config.RouteSet]. Use [config.Validate] to check the application first.
+func Lint(app *config.Application, options ...Option) Report {
+	opts := &lintOptions{
+		Severities:   map[string]Severity{},
+		Suppressions: map[string][]string{},
+	}
+
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	if opts.Registry == nil {
+		opts.Registry = DefaultRegistry()
+	}
+
+	var report Report
+	routes := app.RouteSet()
+
+	for _, rule := range opts.Registry.rules {
+		severity, ok := opts.Severities[rule.ID]
+		if !ok {
+			severity = rule.Severity
+		}
+
+		rule.Check(&Context{
+			Application: app,
+			RouteSet:    routes,
+			rule:        rule,
+			severity:    severity,
+			options:     opts,
+			report:      &report,
+		})
+	}
+
+	return report
+}
@@ -0,0 +1,178 @@
+package lint_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config"
+	. "github.com/dogmatiq/enginekit/config/lint"
+	"github.com/dogmatiq/enginekit/config/runtimeconfig"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+)
+
+func TestLint(t *testing.T) {
+	app := runtimeconfig.FromApplication(&ApplicationStub{
+		ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+			c.Identity("app", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+			c.Routes(
+				dogma.ViaAggregate(&AggregateMessageHandlerStub[*AggregateRootStub]{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("orders", "40ddf2a2-f053-485c-8621-1fc8a58f8ddf")
+						c.Routes(
+							dogma.HandlesCommand[*CommandStub[TypeA]](),
+							dogma.RecordsEvent[*EventStub[TypeA]](),
+							dogma.RecordsEvent[*EventStub[TypeB]](),
+						)
+					},
+				}),
+				dogma.ViaProcess(&ProcessMessageHandlerStub[*ProcessRootStub]{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("fulfilment", "dc2b8d3b-f2e8-4ffe-9cb5-3e1a4f2e0d6a")
+						c.Routes(
+							dogma.HandlesEvent[*EventStub[TypeA]](),
+							dogma.ExecutesCommand[*CommandStub[TypeB]](),
+							dogma.ExecutesCommand[*CommandStub[TypeC]](),
+							dogma.SchedulesDeadline[*DeadlineStub[TypeA]](),
+						)
+					},
+				}),
+				dogma.ViaProcess(&ProcessMessageHandlerStub[*ProcessRootStub]{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("billing", "9b6f1b0e-5f3a-4d3e-9c2f-7a8e0d1c2b3a")
+						c.Routes(
+							dogma.HandlesEvent[*EventStub[TypeA]](),
+							dogma.ExecutesCommand[*CommandStub[TypeA]](),
+							dogma.SchedulesDeadline[*DeadlineStub[TypeA]](),
+						)
+					},
+				}),
+				dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+					ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+						c.Identity("shipping", "4a0a6c0e-1e8a-4b4e-8a3e-3b3f5f5a1f5e")
+						c.Routes(
+							dogma.HandlesCommand[*CommandStub[TypeB]](),
+						)
+						c.Disable()
+					},
+				}),
+			)
+		},
+	})
+
+	findings := func(r Report) []string {
+		var lines []string
+		for _, f := range r.Findings {
+			lines = append(lines, f.Handler.Identity().GetName()+": "+f.String())
+		}
+		return lines
+	}
+
+	t.Run("it reports findings from the default rules", func(t *testing.T) {
+		Expect(
+			t,
+			"unexpected findings",
+			findings(Lint(app)),
+			[]string{
+				`orders: warning [unconsumed-event]: *stubs.EventStub[TypeB] is recorded but not consumed by any handler`,
+				`fulfilment: error [unhandled-command]: *stubs.CommandStub[TypeC] is executed but not handled by any handler`,
+				`fulfilment: error [foreign-deadline]: *stubs.DeadlineStub[TypeA] is also scheduled by process "billing", processes must only handle their own deadlines`,
+				`billing: error [foreign-deadline]: *stubs.DeadlineStub[TypeA] is also scheduled by process "fulfilment", processes must only handle their own deadlines`,
+				`shipping: warning [disabled-sole-consumer]: handler is disabled but is the only consumer of *stubs.CommandStub[TypeB]`,
+			},
+		)
+	})
+
+	t.Run("it overrides rule severities", func(t *testing.T) {
+		r := Lint(
+			app,
+			WithSeverity(UnhandledCommandRule.ID, Info),
+			WithSeverity(ForeignDeadlineRule.ID, Info),
+		)
+
+		Expect(t, "unexpected severity", r.Findings[1].Severity, Info)
+		Expect(t, "unexpected error status", r.HasSeverity(Error), false)
+		Expect(t, "unexpected warning status", r.HasSeverity(Warning), true)
+	})
+
+	t.Run("it suppresses findings by handler name or key", func(t *testing.T) {
+		r := Lint(
+			app,
+			Suppress(ForeignDeadlineRule.ID, "billing", "dc2b8d3b-f2e8-4ffe-9cb5-3e1a4f2e0d6a"),
+			Suppress(DisabledSoleConsumerRule.ID, "shipping"),
+		)
+
+		Expect(
+			t,
+			"unexpected findings",
+			findings(r),
+			[]string{
+				`orders: warning [unconsumed-event]: *stubs.EventStub[TypeB] is recorded but not consumed by any handler`,
+				`fulfilment: error [unhandled-command]: *stubs.CommandStub[TypeC] is executed but not handled by any handler`,
+			},
+		)
+	})
+
+	t.Run("it checks custom rules", func(t *testing.T) {
+		reg := &Registry{}
+		reg.Register(Rule{
+			ID:       "no-integrations",
+			Severity: Info,
+			Check: func(ctx *Context) {
+				for _, h := range ctx.Application.HandlerComponents {
+					if h.HandlerType() == config.IntegrationHandlerType {
+						ctx.Report(h, "integrations are discouraged")
+					}
+				}
+			},
+		})
+
+		Expect(
+			t,
+			"unexpected findings",
+			findings(Lint(app, WithRegistry(reg))),
+			[]string{
+				`shipping: info [no-integrations]: integrations are discouraged`,
+			},
+		)
+	})
+
+	t.Run("it includes findings in the application's description", func(t *testing.T) {
+		r := Lint(app, WithRegistry(&Registry{}))
+		Expect(t, "unexpected findings", len(r.Findings), 0)
+
+		r = Lint(app)
+		desc := config.Description(app, r.DescribeOption())
+
+		for _, f := range r.Findings {
+			if !strings.Contains(desc, "  - "+f.String()+"\n") {
+				t.Fatalf("expected description to contain %q:\n%s", f.String(), desc)
+			}
+		}
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("it panics if a rule is registered twice", func(t *testing.T) {
+		reg := DefaultRegistry()
+
+		ExpectPanic(
+			t,
+			`rule "unconsumed-event" is already registered`,
+			func() {
+				reg.Register(UnconsumedEventRule)
+			},
+		)
+	})
+
+	t.Run("it panics if a rule has no check function", func(t *testing.T) {
+		ExpectPanic(
+			t,
+			`rule "x" must have a check function`,
+			func() {
+				(&Registry{}).Register(Rule{ID: "x"})
+			},
+		)
+	})
+}
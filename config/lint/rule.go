@@ -0,0 +1,150 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/message"
+)
+
+// Rule is a check that identifies a specific design smell within an
+// application.
+type Rule struct {
+	// ID is a short, unique identifier for the rule, such as
+	// "unconsumed-event". It is used to override the rule's severity and to
+	// suppress its findings.
+	ID string
+
+	// Description is a human-readable description of the smell that the rule
+	// identifies.
+	Description string
+
+	// Severity is the default severity of the rule's findings.
+	Severity Severity
+
+	// Check inspects the application and reports its findings via
+	// [Context.Report].
+	Check func(*Context)
+}
+
+// Context is the context in which a [Rule] is checked.
+type Context struct {
+	// Application is the application being checked.
+	Application *config.Application
+
+	// RouteSet is the set of all routes within the application.
+	RouteSet config.RouteSet
+
+	rule     Rule
+	severity Severity
+	options  *lintOptions
+	report   *Report
+}
+
+// Report adds a finding about h to the report.
+//
+// The finding is discarded if the rule's findings are suppressed for h.
+func (ctx *Context) Report(h config.Handler, format string, args ...any) {
+	if ctx.options.isSuppressed(ctx.rule.ID, h) {
+		return
+	}
+
+	ctx.report.Findings = append(
+		ctx.report.Findings,
+		Finding{
+			RuleID:   ctx.rule.ID,
+			Severity: ctx.severity,
+			Handler:  h,
+			Message:  fmt.Sprintf(format, args...),
+		},
+	)
+}
+
+// MessageTypes returns the message types that are routed to or from any of the
+// application's handlers, in the order they are first configured.
+func (ctx *Context) MessageTypes() []message.Type {
+	var types []message.Type
+
+	for _, h := range ctx.Application.HandlerComponents {
+		for _, r := range h.HandlerProperties().RouteComponents {
+			if mt, ok := r.MessageType.TryGet(); ok && !slices.Contains(types, mt) {
+				types = append(types, mt)
+			}
+		}
+	}
+
+	return types
+}
+
+// Handlers returns the handlers that have a route of one of the given types
+// for messages of type t, in the order they are configured within the
+// application.
+func (ctx *Context) Handlers(t message.Type, types ...config.RouteType) []config.Handler {
+	var handlers []config.Handler
+
+	routes := ctx.RouteSet.Filter(config.FilterByRouteType(types...))
+
+	for _, h := range ctx.Application.HandlerComponents {
+		for r, x := range routes.Routes() {
+			if x == h && r.MessageType.Get() == t {
+				handlers = append(handlers, h)
+				break
+			}
+		}
+	}
+
+	return handlers
+}
+
+// Registry is a collection of [Rule] values.
+//
+// The zero value is an empty registry. Use [DefaultRegistry] to obtain a
+// registry that contains the built-in rules.
+type Registry struct {
+	rules []Rule
+}
+
+// DefaultRegistry returns a new [Registry] that contains the built-in rules.
+func DefaultRegistry() *Registry {
+	r := &Registry{}
+	for _, rule := range builtInRules {
+		r.Register(rule)
+	}
+	return r
+}
+
+// Register adds a rule to the registry.
+//
+// It panics if the rule has no ID or [Rule.Check] function, or if the registry
+// already contains a rule with the same ID.
+func (r *Registry) Register(rule Rule) {
+	if rule.ID == "" {
+		panic("rule must have an ID")
+	}
+
+	if rule.Check == nil {
+		panic(fmt.Sprintf("rule %q must have a check function", rule.ID))
+	}
+
+	if _, ok := r.Rule(rule.ID); ok {
+		panic(fmt.Sprintf("rule %q is already registered", rule.ID))
+	}
+
+	r.rules = append(r.rules, rule)
+}
+
+// Rule returns the rule with the given ID.
+func (r *Registry) Rule(id string) (Rule, bool) {
+	for _, rule := range r.rules {
+		if rule.ID == id {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// Rules returns the rules in the registry, in the order they were registered.
+func (r *Registry) Rules() []Rule {
+	return slices.Clone(r.rules)
+}
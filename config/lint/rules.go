@@ -0,0 +1,120 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/message"
+)
+
+var builtInRules = []Rule{
+	UnconsumedEventRule,
+	UnhandledCommandRule,
+	ForeignDeadlineRule,
+	DisabledSoleConsumerRule,
+}
+
+// UnconsumedEventRule is a [Rule] that reports events that are recorded by a
+// handler but not handled by any handler within the application.
+var UnconsumedEventRule = Rule{
+	ID:          "unconsumed-event",
+	Description: "an event is recorded but not consumed by any handler",
+	Severity:    Warning,
+	Check: func(ctx *Context) {
+		for _, mt := range ctx.MessageTypes() {
+			if len(ctx.Handlers(mt, config.HandlesEventRouteType)) != 0 {
+				continue
+			}
+
+			for _, h := range ctx.Handlers(mt, config.RecordsEventRouteType) {
+				ctx.Report(h, "%s is recorded but not consumed by any handler", mt)
+			}
+		}
+	},
+}
+
+// UnhandledCommandRule is a [Rule] that reports commands that are executed by a
+// handler but not handled by any handler within the application.
+var UnhandledCommandRule = Rule{
+	ID:          "unhandled-command",
+	Description: "a command is executed but not handled by any handler",
+	Severity:    Error,
+	Check: func(ctx *Context) {
+		for _, mt := range ctx.MessageTypes() {
+			if len(ctx.Handlers(mt, config.HandlesCommandRouteType)) != 0 {
+				continue
+			}
+
+			for _, h := range ctx.Handlers(mt, config.ExecutesCommandRouteType) {
+				ctx.Report(h, "%s is executed but not handled by any handler", mt)
+			}
+		}
+	},
+}
+
+// ForeignDeadlineRule is a [Rule] that reports deadlines that are delivered to
+// a process other than the one that scheduled them.
+//
+// A process handles each deadline type that it schedules, so deadlines of a
+// type that is scheduled by more than one process are also delivered to
+// processes that did not schedule them.
+var ForeignDeadlineRule = Rule{
+	ID:          "foreign-deadline",
+	Description: "a deadline is scheduled by a process that is not its only handler",
+	Severity:    Error,
+	Check: func(ctx *Context) {
+		for _, mt := range ctx.MessageTypes() {
+			handlers := ctx.Handlers(mt, config.SchedulesDeadlineRouteType)
+			if len(handlers) < 2 {
+				continue
+			}
+
+			for _, h := range handlers {
+				ctx.Report(
+					h,
+					"%s is also scheduled by %s, processes must only handle their own deadlines",
+					mt,
+					joinHandlers(handlers, h),
+				)
+			}
+		}
+	},
+}
+
+// DisabledSoleConsumerRule is a [Rule] that reports disabled handlers that are
+// the only consumer of a message type.
+var DisabledSoleConsumerRule = Rule{
+	ID:          "disabled-sole-consumer",
+	Description: "a disabled handler is the only consumer of a message",
+	Severity:    Warning,
+	Check: func(ctx *Context) {
+		for _, mt := range ctx.MessageTypes() {
+			if mt.Kind() == message.DeadlineKind {
+				continue
+			}
+
+			handlers := ctx.Handlers(
+				mt,
+				config.HandlesCommandRouteType,
+				config.HandlesEventRouteType,
+			)
+
+			if len(handlers) == 1 && handlers[0].IsDisabled() {
+				ctx.Report(handlers[0], "handler is disabled but is the only consumer of %s", mt)
+			}
+		}
+	},
+}
+
+// joinHandlers returns a comma-separated list of the handlers in handlers,
+// excluding h.
+func joinHandlers(handlers []config.Handler, h config.Handler) string {
+	var names []string
+	for _, x := range handlers {
+		if x != h {
+			names = append(names, fmt.Sprintf("%s %q", x.HandlerType(), x.Identity().GetName()))
+		}
+	}
+	return strings.Join(names, ", ")
+}
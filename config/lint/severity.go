@@ -0,0 +1,31 @@
+package lint
+
+import "fmt"
+
+// Severity is the severity of a [Finding].
+type Severity int
+
+const (
+	// Info indicates a finding that is purely informational.
+	Info Severity = iota
+
+	// Warning indicates a finding that is likely, but not certainly, a
+	// mistake.
+	Warning
+
+	// Error indicates a finding that is almost certainly a mistake.
+	Error
+)
+
+func (s Severity) String() string {
+	switch s {
+	case Info:
+		return "info"
+	case Warning:
+		return "warning"
+	case Error:
+		return "error"
+	default:
+		return fmt.Sprintf("Severity(%d)", int(s))
+	}
+}
@@ -0,0 +1,230 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dogmatiq/enginekit/collections/maps"
+)
+
+// ValidateApplications returns an error if any of the given applications are
+// invalid, or if they conflict with each other in a manner that prevents them
+// from being hosted by the same engine.
+//
+// Each application is validated as per [Validate]. Additionally, identity keys
+// must be unique across all of the applications, and no two applications may
+// handle commands of the same type.
+func ValidateApplications(apps []*Application, options ...ValidateOption) error {
+	var errs []error
+
+	apps = uniqueApplications(apps)
+
+	for _, app := range apps {
+		if err := Validate(app, options...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	errs = append(errs, reportCrossApplicationIdentityConflicts(apps)...)
+	errs = append(errs, reportCrossApplicationRouteConflicts(apps)...)
+
+	return errors.Join(errs...)
+}
+
+// uniqueApplications returns a copy of apps with any repeated pointers
+// removed, preserving the order in which each application first appears.
+func uniqueApplications(apps []*Application) []*Application {
+	seen := map[*Application]struct{}{}
+	unique := make([]*Application, 0, len(apps))
+
+	for _, app := range apps {
+		if _, ok := seen[app]; !ok {
+			seen[app] = struct{}{}
+			unique = append(unique, app)
+		}
+	}
+
+	return unique
+}
+
+// ApplicationEntity is an [Entity] and the [Application] that it belongs to.
+//
+// If the entity is the application itself, Entity and Application are the
+// same value.
+type ApplicationEntity struct {
+	Application *Application
+	Entity      Entity
+}
+
+func (e ApplicationEntity) String() string {
+	return qualifiedString(e.Application, e.Entity)
+}
+
+// ApplicationHandler is a [Handler] and the [Application] that it belongs to.
+type ApplicationHandler struct {
+	Application *Application
+	Handler     Handler
+}
+
+func (h ApplicationHandler) String() string {
+	return qualifiedString(h.Application, h.Handler)
+}
+
+// CrossApplicationIdentityKeyConflictError indicates that entities within more
+// than one [Application] share the same "key" element of an [Identity].
+type CrossApplicationIdentityKeyConflictError struct {
+	ConflictingKey string
+	Entities       []ApplicationEntity
+}
+
+func (e CrossApplicationIdentityKeyConflictError) Error() string {
+	return fmt.Sprintf(
+		"identity key %q is shared by %d entities across multiple applications: %s",
+		e.ConflictingKey,
+		len(e.Entities),
+		joinStrings(e.Entities),
+	)
+}
+
+// CrossApplicationRouteConflictError indicates that handlers within more than
+// one [Application] are configured with routes for the same [MessageType] in a
+// manner that is not permitted.
+type CrossApplicationRouteConflictError struct {
+	ConflictingRouteType       RouteType
+	ConflictingMessageTypeName string
+	Handlers                   []ApplicationHandler
+}
+
+func (e CrossApplicationRouteConflictError) Error() string {
+	return fmt.Sprintf(
+		"%s route for %s is shared by %d handlers across multiple applications: %s",
+		e.ConflictingRouteType,
+		e.ConflictingMessageTypeName,
+		len(e.Handlers),
+		joinStrings(e.Handlers),
+	)
+}
+
+// reportCrossApplicationIdentityConflicts returns errors describing identity
+// keys that are used by entities within more than one application.
+func reportCrossApplicationIdentityConflicts(apps []*Application) []error {
+	var byKey maps.Ordered[string, []ApplicationEntity]
+
+	push := func(app *Application, e Entity) {
+		for _, id := range e.EntityProperties().IdentityComponents {
+			if k, ok := id.Key.TryGet(); ok {
+				byKey.Update(
+					normalizeIdentityKey(k),
+					func(entities *[]ApplicationEntity) {
+						x := ApplicationEntity{app, e}
+						if !slices.Contains(*entities, x) {
+							*entities = append(*entities, x)
+						}
+					},
+				)
+			}
+		}
+	}
+
+	for _, app := range apps {
+		push(app, app)
+
+		for _, h := range app.HandlerComponents {
+			push(app, h)
+		}
+	}
+
+	var errs []error
+
+	for key, entities := range byKey.All() {
+		if spansApplications(entities, func(e ApplicationEntity) *Application { return e.Application }) {
+			errs = append(errs, CrossApplicationIdentityKeyConflictError{key, entities})
+		}
+	}
+
+	return errs
+}
+
+// reportCrossApplicationRouteConflicts returns errors describing command types
+// that are handled by handlers within more than one application.
+func reportCrossApplicationRouteConflicts(apps []*Application) []error {
+	var byKey maps.OrderedByKey[routeKey, []ApplicationHandler]
+
+	for _, app := range apps {
+		for _, h := range app.HandlerComponents {
+			for _, r := range h.HandlerProperties().RouteComponents {
+				if k, ok := r.key(); ok && k.RouteType == HandlesCommandRouteType {
+					byKey.Update(
+						k,
+						func(handlers *[]ApplicationHandler) {
+							x := ApplicationHandler{app, h}
+							if !slices.Contains(*handlers, x) {
+								*handlers = append(*handlers, x)
+							}
+						},
+					)
+				}
+			}
+		}
+	}
+
+	var errs []error
+
+	for key, handlers := range byKey.All() {
+		if spansApplications(handlers, func(h ApplicationHandler) *Application { return h.Application }) {
+			errs = append(errs, CrossApplicationRouteConflictError{
+				key.RouteType,
+				key.MessageTypeName,
+				handlers,
+			})
+		}
+	}
+
+	return errs
+}
+
+// spansApplications returns true if the elements of s belong to more than one
+// application.
+func spansApplications[T any](s []T, app func(T) *Application) bool {
+	for _, x := range s[1:] {
+		if app(x) != app(s[0]) {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifiedString returns a string representation of e that includes the
+// name of the application that it belongs to.
+func qualifiedString(app *Application, e Entity) string {
+	if Entity(app) == e {
+		return "application " + identityNameOrString(app)
+	}
+	return "application " + identityNameOrString(app) + " " + e.String()
+}
+
+// identityNameOrString returns the name of e's identity, if it has exactly one
+// identity with a known name, otherwise it returns e.String().
+func identityNameOrString(e Entity) string {
+	if ids := e.EntityProperties().IdentityComponents; len(ids) == 1 {
+		if n, ok := ids[0].Name.TryGet(); ok {
+			return n
+		}
+	}
+	return e.String()
+}
+
+func joinStrings[T fmt.Stringer](values []T) string {
+	var w strings.Builder
+
+	for i, v := range values {
+		if i > 0 {
+			w.WriteString(", ")
+		}
+		w.WriteString(v.String())
+	}
+
+	return w.String()
+}
@@ -0,0 +1,183 @@
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/config/runtimeconfig"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	"github.com/dogmatiq/enginekit/internal/test"
+)
+
+func TestValidateApplications(t *testing.T) {
+	newApp := func(name, key string, routes ...dogma.HandlerRoute) *Application {
+		return runtimeconfig.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity(name, key)
+				c.Routes(routes...)
+			},
+		})
+	}
+
+	newIntegration := func(name, key string, routes ...dogma.IntegrationRoute) dogma.HandlerRoute {
+		return dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+			ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+				c.Identity(name, key)
+				c.Routes(routes...)
+			},
+		})
+	}
+
+	t.Run("it returns nil if the applications do not conflict", func(t *testing.T) {
+		err := ValidateApplications([]*Application{
+			newApp(
+				"app1", "14769f7f-87fe-48dd-916e-5bcab6ba6aca",
+				newIntegration(
+					"integration", "40ddf2a2-f053-485c-8621-1fc8a58f8ddf",
+					dogma.HandlesCommand[*CommandStub[TypeA]](),
+				),
+			),
+			newApp(
+				"app2", "dc2b8d3b-f2e8-4ffe-9cb5-3e1a4f2e0d6a",
+				newIntegration(
+					"integration", "4a0a6c0e-1e8a-4b4e-8a3e-3b3f5f5a1f5e",
+					dogma.HandlesCommand[*CommandStub[TypeB]](),
+				),
+			),
+		})
+
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("it reports identity keys that are shared across applications", func(t *testing.T) {
+		app1 := newApp(
+			"app1", "14769f7f-87fe-48dd-916e-5bcab6ba6aca",
+			newIntegration(
+				"integration", "40ddf2a2-f053-485c-8621-1fc8a58f8ddf",
+				dogma.HandlesCommand[*CommandStub[TypeA]](),
+			),
+		)
+
+		app2 := newApp(
+			"app2", "40DDF2A2-F053-485C-8621-1FC8A58F8DDF", // <-- SAME KEY AS INTEGRATION IN app1
+			newIntegration(
+				"integration", "4a0a6c0e-1e8a-4b4e-8a3e-3b3f5f5a1f5e",
+				dogma.HandlesCommand[*CommandStub[TypeB]](),
+			),
+		)
+
+		err := ValidateApplications([]*Application{app1, app2})
+
+		test.Expect(
+			t,
+			"unexpected error message",
+			err.Error(),
+			`identity key "40ddf2a2-f053-485c-8621-1fc8a58f8ddf" is shared by 2 entities across multiple applications: application app1 integration:IntegrationMessageHandlerStub, application app2`,
+		)
+
+		var conflict CrossApplicationIdentityKeyConflictError
+		if !errors.As(err, &conflict) {
+			t.Fatalf("expected %T, got %T", conflict, err)
+		}
+
+		test.Expect(
+			t,
+			"unexpected conflicting entities",
+			conflict.Entities,
+			[]ApplicationEntity{
+				{app1, app1.HandlerComponents[0]},
+				{app2, app2},
+			},
+		)
+	})
+
+	t.Run("it reports commands that are handled by more than one application", func(t *testing.T) {
+		app1 := newApp(
+			"app1", "14769f7f-87fe-48dd-916e-5bcab6ba6aca",
+			newIntegration(
+				"integration1", "40ddf2a2-f053-485c-8621-1fc8a58f8ddf",
+				dogma.HandlesCommand[*CommandStub[TypeA]](),
+			),
+		)
+
+		app2 := newApp(
+			"app2", "dc2b8d3b-f2e8-4ffe-9cb5-3e1a4f2e0d6a",
+			newIntegration(
+				"integration2", "4a0a6c0e-1e8a-4b4e-8a3e-3b3f5f5a1f5e",
+				dogma.HandlesCommand[*CommandStub[TypeA]](), // <-- SAME COMMAND AS app1
+			),
+		)
+
+		err := ValidateApplications([]*Application{app1, app2})
+
+		var conflict CrossApplicationRouteConflictError
+		if !errors.As(err, &conflict) {
+			t.Fatalf("expected %T, got %v", conflict, err)
+		}
+
+		test.Expect(
+			t,
+			"unexpected route type",
+			conflict.ConflictingRouteType,
+			HandlesCommandRouteType,
+		)
+
+		test.Expect(
+			t,
+			"unexpected conflicting handlers",
+			conflict.Handlers,
+			[]ApplicationHandler{
+				{app1, app1.HandlerComponents[0]},
+				{app2, app2.HandlerComponents[0]},
+			},
+		)
+	})
+
+	t.Run("it ignores repeated occurrences of the same application", func(t *testing.T) {
+		app1 := newApp(
+			"app1", "14769f7f-87fe-48dd-916e-5bcab6ba6aca",
+			newIntegration(
+				"integration", "40ddf2a2-f053-485c-8621-1fc8a58f8ddf",
+				dogma.HandlesCommand[*CommandStub[TypeA]](),
+			),
+		)
+
+		app2 := newApp(
+			"app2", "dc2b8d3b-f2e8-4ffe-9cb5-3e1a4f2e0d6a",
+		)
+
+		if err := ValidateApplications([]*Application{app1, app2, app1}); err != nil {
+			t.Fatal(err)
+		}
+
+		invalid := &Application{}
+		err := ValidateApplications([]*Application{invalid, app1, invalid})
+
+		test.Expect(
+			t,
+			"unexpected error message",
+			err.Error(),
+			`application is invalid: no identity`,
+		)
+	})
+
+	t.Run("it includes errors from validating each application", func(t *testing.T) {
+		err := ValidateApplications([]*Application{
+			newApp(
+				"app1", "14769f7f-87fe-48dd-916e-5bcab6ba6aca",
+			),
+			{},
+		})
+
+		test.Expect(
+			t,
+			"unexpected error message",
+			err.Error(),
+			`application is invalid: no identity`,
+		)
+	})
+}
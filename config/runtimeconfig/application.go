@@ -11,7 +11,19 @@ import (
 // FromApplication returns a new [config.Application] that represents the
 // configuration of the given [dogma.Application].
 func FromApplication(app dogma.Application) *config.Application {
-	return configbuilder.Application(
+	return FromApplicationWithOptions(app)
+}
+
+// FromApplicationWithOptions returns a new [config.Application] that represents
+// the configuration of the given [dogma.Application], built according to the
+// given options.
+func FromApplicationWithOptions(app dogma.Application, options ...Option) *config.Application {
+	var opts fromOptions
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	cfg := configbuilder.Application(
 		func(b *configbuilder.ApplicationBuilder) {
 			if app == nil {
 				b.Partial()
@@ -21,6 +33,40 @@ func FromApplication(app dogma.Application) *config.Application {
 			}
 		},
 	)
+
+	for _, hook := range opts.ExtensionHooks {
+		for _, h := range cfg.HandlerComponents {
+			hook(h)
+		}
+		hook(cfg)
+	}
+
+	return cfg
+}
+
+// Option is an option that changes the behavior of
+// [FromApplicationWithOptions].
+type Option func(*fromOptions)
+
+// ExtensionHook is a function that attaches engine-specific extensions to the
+// configuration of an application or handler, typically by calling
+// [config.SetExtension].
+//
+// The entity's source value, such as the [dogma.Application] or handler
+// implementation, is available via the Source field of the concrete
+// [config.Entity] type.
+type ExtensionHook func(config.Entity)
+
+// WithExtensionHook is an [Option] that calls hook for each handler within the
+// application, and then for the application itself.
+func WithExtensionHook(hook ExtensionHook) Option {
+	return func(opts *fromOptions) {
+		opts.ExtensionHooks = append(opts.ExtensionHooks, hook)
+	}
+}
+
+type fromOptions struct {
+	ExtensionHooks []ExtensionHook
 }
 
 type applicationConfigurer struct {
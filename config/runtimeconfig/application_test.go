@@ -9,6 +9,7 @@ import (
 	. "github.com/dogmatiq/enginekit/enginetest/stubs"
 	. "github.com/dogmatiq/enginekit/internal/test"
 	"github.com/dogmatiq/enginekit/optional"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 )
 
 func TestFromApplication(t *testing.T) {
@@ -123,4 +124,46 @@ func TestFromApplication(t *testing.T) {
 			)
 		})
 	}
+
+	t.Run("it calls extension hooks for each handler and the application", func(t *testing.T) {
+		app := FromApplicationWithOptions(
+			&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("app", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+					c.Routes(
+						dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+							ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+								c.Identity("integration", "51ffcb6f-171f-41a1-90e7-6fe1111649cd")
+								c.Routes(
+									dogma.HandlesCommand[*CommandStub[TypeA]](),
+								)
+							},
+						}),
+					)
+				},
+			},
+			WithExtensionHook(func(e config.Entity) {
+				config.SetExtension(e, wrapperspb.String(e.String()))
+			}),
+		)
+
+		var got []string
+		for _, e := range []config.Entity{app, app.HandlerComponents[0]} {
+			x, ok := config.GetExtension[*wrapperspb.StringValue](e)
+			if !ok {
+				t.Fatalf("expected %s to have an extension", e)
+			}
+			got = append(got, x.GetValue())
+		}
+
+		Expect(
+			t,
+			"unexpected extensions",
+			got,
+			[]string{
+				"application:ApplicationStub",
+				"integration:IntegrationMessageHandlerStub",
+			},
+		)
+	})
 }
@@ -37,9 +37,16 @@ func (c *handlerConfigurer[R, T, H]) Routes(routes ...R) {
 	}
 }
 
-func (c *handlerConfigurer[R, T, H]) Disable(...dogma.DisableOption) {
+// Disable adds a [config.Flag] that disables the handler.
+//
+// The options are retained by the flag.
+func (c *handlerConfigurer[R, T, H]) Disable(options ...dogma.DisableOption) {
 	c.b.Disabled(func(b *configbuilder.FlagBuilder[config.Disabled]) {
 		b.Value(true)
+
+		for _, opt := range options {
+			b.Option(opt)
+		}
 	})
 }
 
@@ -97,6 +97,48 @@ func TestFromIntegration(t *testing.T) {
 				}
 			},
 		},
+		{
+			"disabled handler with options",
+			&IntegrationMessageHandlerStub{
+				ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+					c.Identity("integration", "51ffcb6f-171f-41a1-90e7-6fe1111649cd")
+					c.Routes(
+						dogma.HandlesCommand[*CommandStub[TypeA]](),
+					)
+					c.Disable(disableOptionStub{Value: "maintenance"})
+				},
+			},
+			func(h dogma.IntegrationMessageHandler) *config.Integration {
+				return &config.Integration{
+					HandlerCommon: config.HandlerCommon{
+						EntityCommon: config.EntityCommon{
+							TypeName: optional.Some("*github.com/dogmatiq/enginekit/enginetest/stubs.IntegrationMessageHandlerStub"),
+							IdentityComponents: []*config.Identity{
+								{
+									Name: optional.Some("integration"),
+									Key:  optional.Some("51ffcb6f-171f-41a1-90e7-6fe1111649cd"),
+								},
+							},
+						},
+						RouteComponents: []*config.Route{
+							{
+								RouteType:       optional.Some(config.HandlesCommandRouteType),
+								MessageTypeID:   optional.Some(MessageTypeID[*CommandStub[TypeA]]()),
+								MessageTypeName: optional.Some("*github.com/dogmatiq/enginekit/enginetest/stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]"),
+								MessageType:     optional.Some(message.TypeFor[*CommandStub[TypeA]]()),
+							},
+						},
+						DisabledFlags: []*config.Flag[config.Disabled]{
+							{
+								Value:   optional.Some(true),
+								Options: []any{disableOptionStub{Value: "maintenance"}},
+							},
+						},
+					},
+					Source: optional.Some(h),
+				}
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -110,3 +152,10 @@ func TestFromIntegration(t *testing.T) {
 		})
 	}
 }
+
+// disableOptionStub is a [dogma.DisableOption] used to verify that options are
+// retained by the disabled flag.
+type disableOptionStub struct {
+	dogma.DisableOption
+	Value string
+}
@@ -0,0 +1,211 @@
+package staticconfig
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"go/types"
+	"slices"
+
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/config/internal/configbuilder"
+	"github.com/dogmatiq/enginekit/internal/typename"
+	"golang.org/x/tools/go/packages"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// LoadMode is the minimal [packages.LoadMode] required for packages passed to
+// [Analyze].
+const LoadMode = packages.NeedName |
+	packages.NeedFiles |
+	packages.NeedImports |
+	packages.NeedDeps |
+	packages.NeedTypes |
+	packages.NeedSyntax |
+	packages.NeedTypesInfo |
+	packages.NeedTypesSizes
+
+// Analysis is the result of statically analyzing a set of Go packages.
+type Analysis struct {
+	// Applications is the configuration of each [dogma.Application]
+	// implementation found within the analyzed packages, ordered by type name.
+	Applications []*config.Application
+}
+
+// LoadAndAnalyze loads the Go packages matching the given patterns, relative
+// to dir, and analyzes them.
+//
+// If no patterns are given, it loads all packages within dir, as per the
+// "./..." pattern.
+//
+// It returns an error if any of the packages cannot be loaded.
+func LoadAndAnalyze(dir string, patterns ...string) (Analysis, error) {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	pkgs, err := packages.Load(
+		&packages.Config{
+			Mode: LoadMode,
+			Dir:  dir,
+		},
+		patterns...,
+	)
+	if err != nil {
+		return Analysis{}, err
+	}
+
+	var errs []error
+	packages.Visit(
+		pkgs,
+		nil,
+		func(pkg *packages.Package) {
+			for _, err := range pkg.Errors {
+				errs = append(errs, err)
+			}
+		},
+	)
+
+	if len(errs) != 0 {
+		return Analysis{}, fmt.Errorf("unable to load packages: %w", errors.Join(errs...))
+	}
+
+	return Analyze(pkgs), nil
+}
+
+// Analyze returns the configuration of each [dogma.Application] implementation
+// found within pkgs.
+//
+// The packages must have been loaded with (at least) the information described
+// by [LoadMode]. Implementations within the dependencies of pkgs are not
+// included in the analysis.
+func Analyze(pkgs []*packages.Package) Analysis {
+	prog, ssaPkgs := ssautil.AllPackages(pkgs, ssa.InstantiateGenerics)
+	prog.Build()
+
+	ctx, ok := newContext(prog)
+	if !ok {
+		// If the dogma package is not imported, there can be no
+		// implementations of its interfaces.
+		return Analysis{}
+	}
+
+	var analysis Analysis
+
+	for _, pkg := range ssaPkgs {
+		if pkg == nil {
+			continue
+		}
+
+		for _, m := range pkg.Members {
+			t, ok := m.(*ssa.Type)
+			if !ok {
+				continue
+			}
+
+			if impl, ok := ctx.implementation(t.Type(), ctx.Application); ok {
+				analysis.Applications = append(
+					analysis.Applications,
+					configbuilder.Application(
+						func(b *configbuilder.ApplicationBuilder) {
+							analyzeApplication(ctx, b, impl)
+						},
+					),
+				)
+			}
+		}
+	}
+
+	slices.SortFunc(
+		analysis.Applications,
+		func(a, b *config.Application) int {
+			return cmp.Compare(a.TypeName.Get(), b.TypeName.Get())
+		},
+	)
+
+	return analysis
+}
+
+// implementation returns the type that implements iface, which is either t or
+// a pointer to t.
+//
+// It returns false if neither t nor *t implements iface, or if t is an
+// interface or generic type.
+func (c *context) implementation(t types.Type, iface *types.Interface) (types.Type, bool) {
+	n, ok := t.(*types.Named)
+	if !ok || n.TypeParams().Len() != 0 || types.IsInterface(n) {
+		return nil, false
+	}
+
+	if types.Implements(n, iface) {
+		return n, true
+	}
+
+	if p := types.NewPointer(n); types.Implements(p, iface) {
+		return p, true
+	}
+
+	return nil, false
+}
+
+// setTypeName sets the type name of the entity built by b to the name of t,
+// or marks it as partial if t is not named.
+func setTypeName(b interface {
+	TypeName(string)
+	Partial()
+}, t types.Type) bool {
+	if !isNamed(t) {
+		b.Partial()
+		return false
+	}
+
+	b.TypeName(typename.OfStatic(t))
+	return true
+}
+
+// isNamed returns true if t is a named type, or a pointer to a named type, such
+// that it can be passed to [typename.OfStatic].
+//
+// It returns false if the name of t depends on any type parameters.
+func isNamed(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.Named:
+		args := t.TypeArgs()
+		for i := range args.Len() {
+			if hasTypeParams(args.At(i)) {
+				return false
+			}
+		}
+		return true
+	case *types.Pointer:
+		return isNamed(t.Elem())
+	default:
+		return false
+	}
+}
+
+// hasTypeParams returns true if t is, or is composed of, a type parameter.
+func hasTypeParams(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Named:
+		args := t.TypeArgs()
+		for i := range args.Len() {
+			if hasTypeParams(args.At(i)) {
+				return true
+			}
+		}
+		return false
+	case interface{ Elem() types.Type }:
+		// Covers pointers, slices, arrays, channels and maps (keys are
+		// checked below).
+		if m, ok := t.(*types.Map); ok && hasTypeParams(m.Key()) {
+			return true
+		}
+		return hasTypeParams(t.Elem())
+	default:
+		return false
+	}
+}
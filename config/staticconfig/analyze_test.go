@@ -0,0 +1,142 @@
+package staticconfig_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dogmatiq/enginekit/config"
+	. "github.com/dogmatiq/enginekit/config/staticconfig"
+	. "github.com/dogmatiq/enginekit/internal/test"
+)
+
+func TestLoadAndAnalyze(t *testing.T) {
+	const (
+		apps     = "github.com/dogmatiq/enginekit/config/staticconfig/testdata/apps"
+		messages = "github.com/dogmatiq/enginekit/config/staticconfig/testdata/messages"
+	)
+
+	cases := []struct {
+		Name        string
+		Pattern     string
+		Description string
+	}{
+		{
+			Name:    "simple application",
+			Pattern: "./testdata/apps/simple",
+			Description: multiline(
+				`unvalidated application `+apps+`/simple.App (value unavailable)`,
+				`  - unvalidated identity app/14769f7f-87fe-48dd-916e-5bcab6ba6aca`,
+				`  - unvalidated aggregate *`+apps+`/simple.Aggregate (value unavailable)`,
+				`      - unvalidated identity aggregate/dad3b670-0852-4711-9efb-a5bf8c15a2c2`,
+				`      - unvalidated handles-command route for *`+messages+`.CommandA (type unavailable)`,
+				`      - unvalidated records-event route for *`+messages+`.EventA (type unavailable)`,
+				`  - unvalidated process *`+apps+`/simple.Process (value unavailable)`,
+				`      - unvalidated identity process/1c0dd111-fe12-4dea-a61b-ba2b4d4b1a6e`,
+				`      - unvalidated handles-event route for *`+messages+`.EventA (type unavailable)`,
+				`      - unvalidated executes-command route for *`+messages+`.CommandB (type unavailable)`,
+				`      - unvalidated schedules-deadline route for *`+messages+`.DeadlineA (type unavailable)`,
+				`  - unvalidated integration *`+apps+`/simple.Integration (value unavailable)`,
+				`      - unvalidated identity integration/2b9c4f0e-5c1e-4a8b-9f55-7a3c4f7c6a01`,
+				`      - unvalidated handles-command route for *`+messages+`.CommandB (type unavailable)`,
+				`      - unvalidated disabled flag, set to true`,
+				`      - unvalidated concurrency preference, set to dogma.MinimizeConcurrency`,
+				`  - unvalidated projection *`+apps+`/simple.Projection (value unavailable)`,
+				`      - unvalidated identity projection/7f3b0a52-9b6c-4d0e-8e5d-3c5c5e0a2f44`,
+				`      - unvalidated handles-event route for *`+messages+`.EventA (type unavailable)`,
+			),
+		},
+		{
+			Name:    "conditional configuration",
+			Pattern: "./testdata/apps/conditional",
+			Description: multiline(
+				`unvalidated application `+apps+`/conditional.App (value unavailable)`,
+				`  - unvalidated speculative identity app-dev/3f0bd6b8-2d3e-4d5b-a1c7-3e0e7b52a6d4`,
+				`  - unvalidated speculative identity app/3f0bd6b8-2d3e-4d5b-a1c7-3e0e7b52a6d4`,
+				`  - unvalidated projection *`+apps+`/conditional.Projection (value unavailable)`,
+				`      - unvalidated identity projection/8b2e1f67-4d8c-4b3a-9e2a-5c6f0d1e7a38`,
+				`      - unvalidated speculative handles-event route for *`+messages+`.EventA (type unavailable)`,
+				`      - unvalidated speculative disabled flag, set to true`,
+				`  - unvalidated speculative projection *`+apps+`/conditional.Reporting (value unavailable)`,
+				`      - unvalidated identity reporting/0e5b8c1d-3a6f-4e2b-8d7c-9f1a2b3c4d5e`,
+				`      - unvalidated handles-event route for *`+messages+`.EventA (type unavailable)`,
+			),
+		},
+		{
+			Name:    "configuration that cannot be computed statically",
+			Pattern: "./testdata/apps/partial",
+			Description: multiline(
+				`unvalidated application `+apps+`/partial.App (value unavailable)`,
+				`  - incomplete identity ?/a8c3e2f1-6b4d-4c9e-8f7a-1d2e3f4a5b6c`,
+				`  - incomplete projection *`+apps+`/partial.Projection (value unavailable)`,
+				`      - unvalidated identity projection/5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a`,
+				`      - unvalidated handles-event route for *`+messages+`.UnregisteredEvent (type unavailable)`,
+				`  - incomplete projection`,
+			),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			analysis, err := LoadAndAnalyze(".", c.Pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(analysis.Applications) != 1 {
+				t.Fatalf("unexpected number of applications: got %d, want 1", len(analysis.Applications))
+			}
+
+			Expect(
+				t,
+				"unexpected description",
+				config.Description(analysis.Applications[0]),
+				c.Description,
+			)
+		})
+	}
+
+	t.Run("it finds applications in multiple packages", func(t *testing.T) {
+		analysis, err := LoadAndAnalyze(".", "./testdata/apps/...")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var names []string
+		for _, app := range analysis.Applications {
+			names = append(names, app.TypeName.Get())
+		}
+
+		Expect(
+			t,
+			"unexpected application type names",
+			names,
+			[]string{
+				apps + "/conditional.App",
+				apps + "/partial.App",
+				apps + "/simple.App",
+			},
+		)
+	})
+
+	t.Run("it does not find applications in packages that do not use Dogma", func(t *testing.T) {
+		analysis, err := LoadAndAnalyze(".", "./testdata/nodogma")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(analysis.Applications) != 0 {
+			t.Fatalf("unexpected number of applications: got %d, want 0", len(analysis.Applications))
+		}
+	})
+
+	t.Run("it returns an error if the packages cannot be loaded", func(t *testing.T) {
+		_, err := LoadAndAnalyze(".", "./testdata/invalid")
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func multiline(lines ...string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
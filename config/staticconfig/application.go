@@ -0,0 +1,106 @@
+package staticconfig
+
+import (
+	"go/types"
+
+	"github.com/dogmatiq/enginekit/config/internal/configbuilder"
+	"golang.org/x/tools/go/ssa"
+)
+
+// analyzeApplication analyzes the Configure() method of the [dogma.Application]
+// implemented by t.
+func analyzeApplication(
+	ctx *context,
+	b *configbuilder.ApplicationBuilder,
+	t types.Type,
+) {
+	setTypeName(b, t)
+
+	calls, ok := ctx.configureMethodCalls(t)
+	if !ok {
+		b.Partial()
+	}
+
+	for _, call := range calls {
+		switch call.Method {
+		case "Identity":
+			b.Identity(func(b *configbuilder.IdentityBuilder) {
+				analyzeIdentity(b, call)
+			})
+
+		case "Routes":
+			analyzeHandlerRoutes(ctx, b, call)
+		}
+	}
+}
+
+// analyzeIdentity analyzes a call to the Identity() method of a configurer.
+func analyzeIdentity(b *configbuilder.IdentityBuilder, call configurerCall) {
+	if call.IsSpeculative {
+		b.Speculative()
+	}
+
+	if name, ok := constantString(call.Args[0]); ok {
+		b.Name(name)
+	} else {
+		b.Partial()
+	}
+
+	if key, ok := constantString(call.Args[1]); ok {
+		b.Key(key)
+	} else {
+		b.Partial()
+	}
+}
+
+// analyzeHandlerRoutes analyzes a call to the Routes() method of a
+// [dogma.ApplicationConfigurer].
+func analyzeHandlerRoutes(
+	ctx *context,
+	b *configbuilder.ApplicationBuilder,
+	call configurerCall,
+) {
+	routes, ok := variadicValues(call.Args[0])
+	if !ok {
+		b.Partial()
+		return
+	}
+
+	for _, r := range routes {
+		route, ok := concreteValue(r).(*ssa.Call)
+		if !ok {
+			b.Partial()
+			continue
+		}
+
+		// All of the Via*() functions accept the handler as their first
+		// argument.
+		fn := route.Common().StaticCallee()
+		args := route.Common().Args
+
+		switch dogmaFunc(fn) {
+		case "ViaAggregate":
+			b.Aggregate(func(b *configbuilder.AggregateBuilder) {
+				analyzeHandler(ctx, b, args[0], call.IsSpeculative)
+			})
+
+		case "ViaProcess":
+			b.Process(func(b *configbuilder.ProcessBuilder) {
+				analyzeHandler(ctx, b, args[0], call.IsSpeculative)
+			})
+
+		case "ViaIntegration":
+			b.Integration(func(b *configbuilder.IntegrationBuilder) {
+				analyzeHandler(ctx, b, args[0], call.IsSpeculative)
+			})
+
+		case "ViaProjection":
+			b.Projection(func(b *configbuilder.ProjectionBuilder) {
+				analyzeHandler(ctx, b, args[0], call.IsSpeculative)
+			})
+
+		default:
+			b.Partial()
+		}
+	}
+}
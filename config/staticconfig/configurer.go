@@ -0,0 +1,280 @@
+package staticconfig
+
+import (
+	"go/token"
+	"go/types"
+
+	"golang.org/x/tools/go/ssa"
+)
+
+// configurerCall is a call to a method of a Dogma "configurer" interface, such
+// as [dogma.ApplicationConfigurer] or [dogma.AggregateConfigurer].
+type configurerCall struct {
+	// Method is the name of the method that was called.
+	Method string
+
+	// Args is the list of arguments passed to the method, excluding the
+	// receiver.
+	Args []ssa.Value
+
+	// IsSpeculative indicates that the call is only made under certain
+	// conditions.
+	IsSpeculative bool
+}
+
+// configureMethodCalls returns the calls made to the configurer within the
+// Configure() method of t.
+//
+// The second return value is false if the configurer is used in a way that
+// cannot be analyzed statically, in which case the returned calls may be
+// incomplete.
+func (c *context) configureMethodCalls(t types.Type) ([]configurerCall, bool) {
+	var pkg *types.Package
+	if n, ok := types.Unalias(derefType(t)).(*types.Named); ok {
+		pkg = n.Obj().Pkg()
+	}
+
+	fn := c.Program.LookupMethod(t, pkg, "Configure")
+	if fn == nil || len(fn.Params) != 2 {
+		return nil, false
+	}
+
+	w := &configurerWalker{
+		visited: map[*ssa.Function]struct{}{},
+		ok:      true,
+	}
+
+	w.walk(fn, fn.Params[1], false, false)
+
+	return w.calls, w.ok
+}
+
+// configurerWalker walks the SSA representation of a function to find calls
+// to the methods of a configurer.
+type configurerWalker struct {
+	visited map[*ssa.Function]struct{}
+	calls   []configurerCall
+	ok      bool
+}
+
+// configurerRefs is the set of values within a single function that refer to
+// the configurer.
+type configurerRefs struct {
+	// Values is the set of values that are the configurer itself.
+	Values map[ssa.Value]struct{}
+
+	// Cells is the set of values that are pointers to variables that hold the
+	// configurer, such as variables captured by closures.
+	Cells map[ssa.Value]struct{}
+}
+
+// isValue returns true if v is the configurer.
+func (r configurerRefs) isValue(v ssa.Value) bool {
+	_, ok := r.Values[v]
+	return ok
+}
+
+// isCell returns true if v is a pointer to a variable that holds the
+// configurer.
+func (r configurerRefs) isCell(v ssa.Value) bool {
+	_, ok := r.Cells[v]
+	return ok
+}
+
+// walk finds calls to the methods of configurer within fn, including those
+// made by any function that configurer is passed to.
+//
+// If isCell is true, configurer is a pointer to a variable that holds the
+// configurer, rather than the configurer itself. If speculative is true, all
+// calls found are marked as speculative.
+func (w *configurerWalker) walk(
+	fn *ssa.Function,
+	configurer ssa.Value,
+	isCell bool,
+	speculative bool,
+) {
+	if _, ok := w.visited[fn]; ok {
+		return
+	}
+	w.visited[fn] = struct{}{}
+	defer delete(w.visited, fn)
+
+	if fn.Blocks == nil {
+		// The function has no body available for analysis.
+		w.ok = false
+		return
+	}
+
+	refs := findConfigurerRefs(fn, configurer, isCell)
+
+	for _, b := range fn.Blocks {
+		conditional := speculative || !isUnconditional(fn, b)
+
+		for _, inst := range b.Instrs {
+			switch inst := inst.(type) {
+			case *ssa.MakeClosure:
+				// Closures that capture the configurer are analyzed when
+				// they are called.
+				continue
+
+			case *ssa.Store:
+				if refs.isCell(inst.Addr) && refs.isValue(inst.Val) {
+					continue
+				}
+
+			case *ssa.UnOp:
+				if refs.isValue(inst) {
+					continue
+				}
+
+			case ssa.CallInstruction:
+				w.walkCall(inst.Common(), refs, conditional)
+				continue
+			}
+
+			if usesRef(inst, refs) {
+				// The configurer is stored, or otherwise used in a way
+				// that we can't follow.
+				w.ok = false
+			}
+		}
+	}
+}
+
+// walkCall inspects a call instruction within the function being walked.
+func (w *configurerWalker) walkCall(
+	call *ssa.CallCommon,
+	refs configurerRefs,
+	speculative bool,
+) {
+	if call.IsInvoke() && refs.isValue(call.Value) {
+		w.calls = append(
+			w.calls,
+			configurerCall{
+				Method:        call.Method.Name(),
+				Args:          call.Args,
+				IsSpeculative: speculative,
+			},
+		)
+		return
+	}
+
+	if closure, ok := call.Value.(*ssa.MakeClosure); ok {
+		fn := closure.Fn.(*ssa.Function)
+
+		for i, v := range closure.Bindings {
+			if refs.isValue(v) {
+				w.walk(fn, fn.FreeVars[i], false, speculative)
+			} else if refs.isCell(v) {
+				w.walk(fn, fn.FreeVars[i], true, speculative)
+			}
+		}
+	}
+
+	for i, arg := range call.Args {
+		if refs.isCell(arg) {
+			// A pointer to the configurer is passed to another function,
+			// which may modify it.
+			w.ok = false
+			continue
+		}
+
+		if !refs.isValue(arg) {
+			continue
+		}
+
+		callee := call.StaticCallee()
+		if callee == nil {
+			// The configurer is passed to a function that can't be
+			// determined statically.
+			w.ok = false
+			continue
+		}
+
+		// For static method calls, the receiver is included in both Args and
+		// Params, so the indices always correspond.
+		w.walk(callee, callee.Params[i], false, speculative)
+	}
+}
+
+// findConfigurerRefs returns the values within fn that refer to configurer.
+//
+// A configurer that is captured by a closure is moved to a heap-allocated
+// variable, and subsequently loaded from that variable each time it is used.
+func findConfigurerRefs(
+	fn *ssa.Function,
+	configurer ssa.Value,
+	isCell bool,
+) configurerRefs {
+	refs := configurerRefs{
+		Values: map[ssa.Value]struct{}{},
+		Cells:  map[ssa.Value]struct{}{},
+	}
+
+	if isCell {
+		refs.Cells[configurer] = struct{}{}
+	} else {
+		refs.Values[configurer] = struct{}{}
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, b := range fn.Blocks {
+			for _, inst := range b.Instrs {
+				switch inst := inst.(type) {
+				case *ssa.Store:
+					if _, ok := inst.Addr.(*ssa.Alloc); ok && refs.isValue(inst.Val) && !refs.isCell(inst.Addr) {
+						refs.Cells[inst.Addr] = struct{}{}
+						changed = true
+					}
+
+				case *ssa.UnOp:
+					if inst.Op == token.MUL && refs.isCell(inst.X) && !refs.isValue(inst) {
+						refs.Values[inst] = struct{}{}
+						changed = true
+					}
+				}
+			}
+		}
+	}
+
+	return refs
+}
+
+// isUnconditional returns true if b is always executed when fn returns
+// normally.
+func isUnconditional(fn *ssa.Function, b *ssa.BasicBlock) bool {
+	for _, x := range fn.Blocks {
+		if len(x.Instrs) == 0 {
+			continue
+		}
+
+		if _, ok := x.Instrs[len(x.Instrs)-1].(*ssa.Return); ok {
+			if !b.Dominates(x) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// usesRef returns true if inst uses any of refs as one of its operands.
+func usesRef(inst ssa.Instruction, refs configurerRefs) bool {
+	var operands [8]*ssa.Value
+	for _, op := range inst.Operands(operands[:0]) {
+		if op != nil && (refs.isValue(*op) || refs.isCell(*op)) {
+			return true
+		}
+	}
+	return false
+}
+
+// derefType returns the element type of t if it is a pointer, or t otherwise.
+func derefType(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
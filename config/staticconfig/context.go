@@ -0,0 +1,194 @@
+package staticconfig
+
+import (
+	"go/constant"
+	"go/types"
+
+	"github.com/dogmatiq/enginekit/internal/typename"
+	"golang.org/x/tools/go/ssa"
+	"golang.org/x/tools/go/ssa/ssautil"
+)
+
+// dogmaPackagePath is the import path of the Dogma package.
+const dogmaPackagePath = "github.com/dogmatiq/dogma"
+
+// context holds the state of an analysis of a single SSA program.
+type context struct {
+	Program *ssa.Program
+
+	// Application is the [dogma.Application] interface.
+	Application *types.Interface
+
+	// MessageTypeIDs maps the fully-qualified name of each message type that
+	// is registered with Dogma's message type registry to its type ID.
+	MessageTypeIDs map[string]string
+}
+
+// newContext returns a new context for analyzing prog.
+//
+// It returns false if prog does not include the Dogma package.
+func newContext(prog *ssa.Program) (*context, bool) {
+	pkg := prog.ImportedPackage(dogmaPackagePath)
+	if pkg == nil {
+		return nil, false
+	}
+
+	ctx := &context{
+		Program:        prog,
+		MessageTypeIDs: map[string]string{},
+	}
+
+	ctx.Application = pkg.Pkg.Scope().
+		Lookup("Application").
+		Type().
+		Underlying().(*types.Interface)
+
+	ctx.findMessageTypeIDs()
+
+	return ctx, true
+}
+
+// findMessageTypeIDs populates c.MessageTypeIDs by searching the program for
+// calls to RegisterCommand(), RegisterEvent() and RegisterDeadline().
+func (c *context) findMessageTypeIDs() {
+	for fn := range ssautil.AllFunctions(c.Program) {
+		for _, b := range fn.Blocks {
+			for _, inst := range b.Instrs {
+				call, ok := inst.(*ssa.Call)
+				if !ok {
+					continue
+				}
+
+				callee := call.Common().StaticCallee()
+
+				switch dogmaFunc(callee) {
+				case "RegisterCommand", "RegisterEvent", "RegisterDeadline":
+				default:
+					continue
+				}
+
+				t := callee.TypeArgs()[0]
+				if !isNamed(t) {
+					continue
+				}
+
+				if id, ok := constantString(call.Common().Args[0]); ok {
+					c.MessageTypeIDs[typename.OfStatic(t)] = id
+				}
+			}
+		}
+	}
+}
+
+// dogmaFunc returns the name of the function in the Dogma package that fn
+// refers to, or an empty string if fn is not a Dogma function.
+//
+// If fn is an instantiation of a generic function, it returns the name of the
+// generic function.
+func dogmaFunc(fn *ssa.Function) string {
+	if fn == nil {
+		return ""
+	}
+
+	if o := fn.Origin(); o != nil {
+		fn = o
+	}
+
+	obj := fn.Object()
+	if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != dogmaPackagePath {
+		return ""
+	}
+
+	if sig, ok := obj.Type().(*types.Signature); !ok || sig.Recv() != nil {
+		return ""
+	}
+
+	return obj.Name()
+}
+
+// constantString returns the value of v if it is a string constant.
+func constantString(v ssa.Value) (string, bool) {
+	if c, ok := v.(*ssa.Const); ok && c.Value != nil && c.Value.Kind() == constant.String {
+		return constant.StringVal(c.Value), true
+	}
+	return "", false
+}
+
+// constantInt returns the value of v if it is an integer constant.
+func constantInt(v ssa.Value) (int64, bool) {
+	if c, ok := v.(*ssa.Const); ok && c.Value != nil && c.Value.Kind() == constant.Int {
+		return constant.Int64Val(c.Value)
+	}
+	return 0, false
+}
+
+// concreteValue returns the value that was converted to an interface to
+// produce v, if any.
+func concreteValue(v ssa.Value) ssa.Value {
+	for {
+		switch x := v.(type) {
+		case *ssa.MakeInterface:
+			v = x.X
+		case *ssa.ChangeInterface:
+			v = x.X
+		case *ssa.ChangeType:
+			if !types.IsInterface(x.X.Type()) {
+				return v
+			}
+			v = x.X
+		default:
+			return v
+		}
+	}
+}
+
+// variadicValues returns the values passed as the elements of v, which is a
+// variadic parameter.
+//
+// It returns false if the values cannot be determined statically, such as
+// when an existing slice is passed using the "..." syntax.
+func variadicValues(v ssa.Value) ([]ssa.Value, bool) {
+	switch v := v.(type) {
+	case *ssa.Const:
+		// A nil slice, meaning no values were passed.
+		return nil, v.IsNil()
+
+	case *ssa.Slice:
+		alloc, ok := v.X.(*ssa.Alloc)
+		if !ok {
+			return nil, false
+		}
+
+		n := alloc.Type().(*types.Pointer).Elem().(*types.Array).Len()
+		values := make([]ssa.Value, n)
+
+		for _, ref := range *alloc.Referrers() {
+			addr, ok := ref.(*ssa.IndexAddr)
+			if !ok {
+				continue
+			}
+
+			index, ok := constantInt(addr.Index)
+			if !ok {
+				return nil, false
+			}
+
+			for _, ref := range *addr.Referrers() {
+				if store, ok := ref.(*ssa.Store); ok && store.Addr == addr {
+					values[index] = store.Val
+				}
+			}
+		}
+
+		for _, v := range values {
+			if v == nil {
+				return nil, false
+			}
+		}
+
+		return values, true
+
+	default:
+		return nil, false
+	}
+}
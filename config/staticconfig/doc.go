@@ -0,0 +1,12 @@
+// Package staticconfig builds configuration from Go source code, without
+// executing it.
+//
+// It uses static analysis to find the types that implement
+// [dogma.Application], and inspects the calls made within their Configure()
+// methods, and those of their handlers.
+//
+// Components that are only configured under conditions that cannot be
+// evaluated statically, such as within an "if" statement or loop, are marked as
+// speculative. Components with values that cannot be computed statically, such
+// as identity names that are read from variables, are marked as partial.
+package staticconfig
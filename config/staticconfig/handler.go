@@ -0,0 +1,156 @@
+package staticconfig
+
+import (
+	"go/types"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/config/internal/configbuilder"
+	"github.com/dogmatiq/enginekit/internal/typename"
+	"golang.org/x/tools/go/ssa"
+)
+
+// analyzeHandler analyzes the Configure() method of the handler h, which is
+// the value passed to one of the Via*() functions.
+//
+// If speculative is true, the handler is only added to the application under
+// certain conditions.
+func analyzeHandler[T config.Handler, H any](
+	ctx *context,
+	b configbuilder.HandlerBuilder[T, H],
+	h ssa.Value,
+	speculative bool,
+) {
+	if speculative {
+		b.Speculative()
+	}
+
+	t := concreteValue(h).Type()
+	if types.IsInterface(t) {
+		// The concrete type of the handler can't be determined statically.
+		b.Partial()
+		return
+	}
+
+	setTypeName(b, t)
+
+	calls, ok := ctx.configureMethodCalls(t)
+	if !ok {
+		b.Partial()
+	}
+
+	for _, call := range calls {
+		switch call.Method {
+		case "Identity":
+			b.Identity(func(b *configbuilder.IdentityBuilder) {
+				analyzeIdentity(b, call)
+			})
+
+		case "Routes":
+			analyzeMessageRoutes(ctx, b, call)
+
+		case "Disable":
+			b.Disabled(func(b *configbuilder.FlagBuilder[config.Disabled]) {
+				if call.IsSpeculative {
+					b.Speculative()
+				}
+				b.Value(true)
+			})
+
+		case "ConcurrencyPreference":
+			if b, ok := b.(configbuilder.HandlerBuilderWithConcurrencyPreference[T, H]); ok {
+				b.ConcurrencyPreference(func(b *configbuilder.ConcurrencyPreferenceBuilder) {
+					analyzeConcurrencyPreference(b, call)
+				})
+			}
+		}
+	}
+}
+
+// analyzeMessageRoutes analyzes a call to the Routes() method of a handler's
+// configurer.
+func analyzeMessageRoutes[T config.Handler, H any](
+	ctx *context,
+	b configbuilder.HandlerBuilder[T, H],
+	call configurerCall,
+) {
+	routes, ok := variadicValues(call.Args[0])
+	if !ok {
+		b.Partial()
+		return
+	}
+
+	for _, r := range routes {
+		b.Route(func(b *configbuilder.RouteBuilder) {
+			analyzeMessageRoute(ctx, b, r, call.IsSpeculative)
+		})
+	}
+}
+
+// analyzeMessageRoute analyzes r, which is a value passed to the Routes()
+// method of a handler's configurer.
+func analyzeMessageRoute(
+	ctx *context,
+	b *configbuilder.RouteBuilder,
+	r ssa.Value,
+	speculative bool,
+) {
+	if speculative {
+		b.Speculative()
+	}
+
+	call, ok := concreteValue(r).(*ssa.Call)
+	if !ok {
+		b.Partial()
+		return
+	}
+
+	fn := call.Common().StaticCallee()
+
+	switch dogmaFunc(fn) {
+	case "HandlesCommand":
+		b.RouteType(config.HandlesCommandRouteType)
+	case "ExecutesCommand":
+		b.RouteType(config.ExecutesCommandRouteType)
+	case "HandlesEvent":
+		b.RouteType(config.HandlesEventRouteType)
+	case "RecordsEvent":
+		b.RouteType(config.RecordsEventRouteType)
+	case "SchedulesDeadline":
+		b.RouteType(config.SchedulesDeadlineRouteType)
+	default:
+		b.Partial()
+		return
+	}
+
+	t := fn.TypeArgs()[0]
+	if !isNamed(t) {
+		// The message type is (or depends on) a type parameter.
+		b.Partial()
+		return
+	}
+
+	name := typename.OfStatic(t)
+	b.MessageTypeName(name)
+
+	if id, ok := ctx.MessageTypeIDs[name]; ok {
+		b.MessageTypeID(id)
+	}
+}
+
+// analyzeConcurrencyPreference analyzes a call to the ConcurrencyPreference()
+// method of a handler's configurer.
+func analyzeConcurrencyPreference(
+	b *configbuilder.ConcurrencyPreferenceBuilder,
+	call configurerCall,
+) {
+	if call.IsSpeculative {
+		b.Speculative()
+	}
+
+	if v, ok := constantInt(call.Args[0]); ok {
+		b.Value(dogma.ConcurrencyPreference(v))
+	} else {
+		b.Partial()
+	}
+}
@@ -0,0 +1,66 @@
+// Package conditional contains an application with a configuration that
+// depends on conditions that cannot be evaluated statically.
+package conditional
+
+import (
+	"os"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config/staticconfig/testdata/messages"
+	"github.com/dogmatiq/enginekit/enginetest/stubs"
+)
+
+// App is a test application.
+type App struct{}
+
+// Configure configures the application.
+func (App) Configure(c dogma.ApplicationConfigurer) {
+	if os.Getenv("PRODUCTION") == "" {
+		c.Identity("app-dev", "3f0bd6b8-2d3e-4d5b-a1c7-3e0e7b52a6d4")
+	} else {
+		c.Identity("app", "3f0bd6b8-2d3e-4d5b-a1c7-3e0e7b52a6d4")
+	}
+
+	c.Routes(
+		dogma.ViaProjection(&Projection{}),
+	)
+
+	if os.Getenv("REPORTING") != "" {
+		c.Routes(
+			dogma.ViaProjection(&Reporting{}),
+		)
+	}
+}
+
+// Projection is a test projection.
+type Projection struct {
+	stubs.ProjectionMessageHandlerStub
+}
+
+// Configure configures the handler.
+func (*Projection) Configure(c dogma.ProjectionConfigurer) {
+	c.Identity("projection", "8b2e1f67-4d8c-4b3a-9e2a-5c6f0d1e7a38")
+
+	for range 2 {
+		c.Routes(
+			dogma.HandlesEvent[*messages.EventA](),
+		)
+	}
+
+	if os.Getenv("DISABLE_PROJECTION") != "" {
+		c.Disable()
+	}
+}
+
+// Reporting is a test projection.
+type Reporting struct {
+	stubs.ProjectionMessageHandlerStub
+}
+
+// Configure configures the handler.
+func (*Reporting) Configure(c dogma.ProjectionConfigurer) {
+	c.Identity("reporting", "0e5b8c1d-3a6f-4e2b-8d7c-9f1a2b3c4d5e")
+	c.Routes(
+		dogma.HandlesEvent[*messages.EventA](),
+	)
+}
@@ -0,0 +1,60 @@
+// Package partial contains an application with a configuration that contains
+// values that cannot be computed statically.
+package partial
+
+import (
+	"os"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config/staticconfig/testdata/messages"
+	"github.com/dogmatiq/enginekit/enginetest/stubs"
+)
+
+// App is a test application.
+type App struct{}
+
+// Configure configures the application.
+func (App) Configure(c dogma.ApplicationConfigurer) {
+	c.Identity(os.Getenv("APP_NAME"), "a8c3e2f1-6b4d-4c9e-8f7a-1d2e3f4a5b6c")
+
+	configureRoutes(c)
+}
+
+// configureRoutes configures the application's routes on its behalf.
+func configureRoutes(c dogma.ApplicationConfigurer) {
+	c.Routes(
+		dogma.ViaProjection(&Projection{}),
+		dogma.ViaProjection(newProjection()),
+	)
+}
+
+// newProjection returns a projection with a type that can't be determined
+// statically.
+func newProjection() dogma.ProjectionMessageHandler {
+	return &Projection{}
+}
+
+// Projection is a test projection.
+type Projection struct {
+	stubs.ProjectionMessageHandlerStub
+}
+
+// Configure configures the handler.
+func (*Projection) Configure(c dogma.ProjectionConfigurer) {
+	identity := func() {
+		c.Identity("projection", "5d4c3b2a-1f0e-4d9c-8b7a-6f5e4d3c2b1a")
+	}
+	identity()
+
+	c.Routes(
+		dogma.HandlesEvent[*messages.UnregisteredEvent](),
+	)
+	c.Routes(projectionRoutes()...)
+}
+
+// projectionRoutes returns routes that can't be determined statically.
+func projectionRoutes() []dogma.ProjectionRoute {
+	return []dogma.ProjectionRoute{
+		dogma.HandlesEvent[*messages.EventA](),
+	}
+}
@@ -0,0 +1,80 @@
+// Package simple contains an application with a configuration that can be
+// analyzed completely.
+package simple
+
+import (
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config/staticconfig/testdata/messages"
+	"github.com/dogmatiq/enginekit/enginetest/stubs"
+)
+
+// App is a test application.
+type App struct{}
+
+// Configure configures the application.
+func (App) Configure(c dogma.ApplicationConfigurer) {
+	c.Identity("app", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+	c.Routes(
+		dogma.ViaAggregate(&Aggregate{}),
+		dogma.ViaProcess(&Process{}),
+		dogma.ViaIntegration(&Integration{}),
+		dogma.ViaProjection(&Projection{}),
+	)
+}
+
+// Aggregate is a test aggregate.
+type Aggregate struct {
+	stubs.AggregateMessageHandlerStub[*stubs.AggregateRootStub]
+}
+
+// Configure configures the handler.
+func (*Aggregate) Configure(c dogma.AggregateConfigurer) {
+	c.Identity("aggregate", "dad3b670-0852-4711-9efb-a5bf8c15a2c2")
+	c.Routes(
+		dogma.HandlesCommand[*messages.CommandA](),
+		dogma.RecordsEvent[*messages.EventA](),
+	)
+}
+
+// Process is a test process.
+type Process struct {
+	stubs.ProcessMessageHandlerStub[*stubs.ProcessRootStub]
+}
+
+// Configure configures the handler.
+func (*Process) Configure(c dogma.ProcessConfigurer) {
+	c.Identity("process", "1c0dd111-fe12-4dea-a61b-ba2b4d4b1a6e")
+	c.Routes(
+		dogma.HandlesEvent[*messages.EventA](),
+		dogma.ExecutesCommand[*messages.CommandB](),
+		dogma.SchedulesDeadline[*messages.DeadlineA](),
+	)
+}
+
+// Integration is a test integration.
+type Integration struct {
+	stubs.IntegrationMessageHandlerStub
+}
+
+// Configure configures the handler.
+func (*Integration) Configure(c dogma.IntegrationConfigurer) {
+	c.Identity("integration", "2b9c4f0e-5c1e-4a8b-9f55-7a3c4f7c6a01")
+	c.Routes(
+		dogma.HandlesCommand[*messages.CommandB](),
+	)
+	c.ConcurrencyPreference(dogma.MinimizeConcurrency)
+	c.Disable()
+}
+
+// Projection is a test projection.
+type Projection struct {
+	stubs.ProjectionMessageHandlerStub
+}
+
+// Configure configures the handler.
+func (*Projection) Configure(c dogma.ProjectionConfigurer) {
+	c.Identity("projection", "7f3b0a52-9b6c-4d0e-8e5d-3c5c5e0a2f44")
+	c.Routes(
+		dogma.HandlesEvent[*messages.EventA](),
+	)
+}
@@ -0,0 +1,4 @@
+// Package invalid contains code that does not compile.
+package invalid
+
+var x int = "not an int"
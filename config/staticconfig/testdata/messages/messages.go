@@ -0,0 +1,49 @@
+// Package messages contains message types used by the static analysis test
+// applications.
+package messages
+
+import "github.com/dogmatiq/dogma"
+
+func init() {
+	dogma.RegisterCommand[*CommandA]("c6b1ea0e-9d3c-4bd8-8a3e-4e5b6a5c1b11")
+	dogma.RegisterCommand[*CommandB]("c0a7d9f3-1f0e-4b8e-9f3c-6e2d7b8a4c52")
+	dogma.RegisterEvent[*EventA]("e91c6d02-2c6a-4a47-b1ea-0d4ec6b0f5a2")
+	dogma.RegisterDeadline[*DeadlineA]("d2f0c7a4-7b3a-4e52-9e0e-2b8f1f3f8c93")
+}
+
+// CommandA is a test command.
+type CommandA struct{ message }
+
+// Validate returns nil.
+func (*CommandA) Validate(dogma.CommandValidationScope) error { return nil }
+
+// CommandB is a test command.
+type CommandB struct{ message }
+
+// Validate returns nil.
+func (*CommandB) Validate(dogma.CommandValidationScope) error { return nil }
+
+// EventA is a test event.
+type EventA struct{ message }
+
+// Validate returns nil.
+func (*EventA) Validate(dogma.EventValidationScope) error { return nil }
+
+// DeadlineA is a test deadline.
+type DeadlineA struct{ message }
+
+// Validate returns nil.
+func (*DeadlineA) Validate(dogma.DeadlineValidationScope) error { return nil }
+
+// UnregisteredEvent is a test event that is not registered with Dogma's
+// message type registry.
+type UnregisteredEvent struct{ message }
+
+// Validate returns nil.
+func (*UnregisteredEvent) Validate(dogma.EventValidationScope) error { return nil }
+
+type message struct{}
+
+func (message) MessageDescription() string     { return "test message" }
+func (message) MarshalBinary() ([]byte, error) { return nil, nil }
+func (*message) UnmarshalBinary([]byte) error  { return nil }
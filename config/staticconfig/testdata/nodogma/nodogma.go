@@ -0,0 +1,11 @@
+// Package nodogma contains a type with a Configure() method that is not a
+// Dogma application, within a package that does not import Dogma.
+package nodogma
+
+// App is not a Dogma application.
+type App struct{}
+
+// Configure is not the Configure() method of a Dogma application.
+func (App) Configure(c interface{ Identity(string, string) }) {
+	c.Identity("app", "14769f7f-87fe-48dd-916e-5bcab6ba6aca")
+}
@@ -0,0 +1,65 @@
+package configgrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/protobuf/configpb"
+	"google.golang.org/grpc"
+)
+
+// DialAndListApplications returns the configuration of all applications served
+// by the ConfigAPI at the given target.
+//
+// It creates a new client connection using [grpc.NewClient] with the given
+// target and options, and closes it before returning. Use [ListApplications]
+// to query the ConfigAPI using an existing connection.
+func DialAndListApplications(
+	ctx context.Context,
+	target string,
+	options ...grpc.DialOption,
+) ([]*config.Application, error) {
+	conn, err := grpc.NewClient(target, options...)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	return ListApplications(ctx, NewConfigAPIClient(conn))
+}
+
+// ListApplications returns the configuration of all applications served by the
+// ConfigAPI that c is connected to.
+//
+// Each application is validated using [config.Validate]. Routes that refer to
+// message types that are not linked into the current binary do not have a
+// [message.Type], as per [configpb.ToApplication], and are not considered
+// invalid.
+func ListApplications(
+	ctx context.Context,
+	c ConfigAPIClient,
+	options ...grpc.CallOption,
+) ([]*config.Application, error) {
+	res, err := c.ListApplications(ctx, &ListApplicationsRequest{}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	var apps []*config.Application
+
+	for _, x := range res.GetApplications() {
+		app, err := configpb.ToApplication(x)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load application (%s): %w", x.GetIdentity(), err)
+		}
+
+		if err := config.Validate(app); err != nil {
+			return nil, err
+		}
+
+		apps = append(apps, app)
+	}
+
+	return apps, nil
+}
@@ -0,0 +1,131 @@
+package configgrpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/config/runtimeconfig"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/grpc/configgrpc"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	"github.com/dogmatiq/enginekit/protobuf/configpb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"google.golang.org/grpc"
+)
+
+func TestListApplications(t *testing.T) {
+	const unlinked = "github.com/dogmatiq/enginekit/grpc/configgrpc_test.Unlinked"
+
+	newResponse := func(messages map[string]*configpb.MessageUsage) *ListApplicationsResponse {
+		return NewListApplicationsResponseBuilder().
+			WithApplications([]*configpb.Application{
+				configpb.NewApplicationBuilder().
+					WithIdentity(identitypb.MustParse("app", "bed53df8-bf22-4502-be4b-64d56532d8be")).
+					WithGoType("example.App").
+					WithHandlers([]*configpb.Handler{
+						configpb.NewHandlerBuilder().
+							WithIdentity(identitypb.MustParse("projection", "9c7a8bc6-9b1e-4f4c-8c44-7f3e6e0d9a51")).
+							WithGoType("example.Projection").
+							WithType(configpb.HandlerType_PROJECTION).
+							WithMessages(messages).
+							Build(),
+					}).
+					WithMessages(map[string]configpb.MessageKind{
+						unlinked: configpb.MessageKind_EVENT,
+					}).
+					Build(),
+			}).
+			Build()
+	}
+
+	t.Run("it permits routes for message types that are not linked into the binary", func(t *testing.T) {
+		client := &ConfigAPIClientStub{
+			ListApplicationsFunc: func(context.Context, *ListApplicationsRequest, ...grpc.CallOption) (*ListApplicationsResponse, error) {
+				return newResponse(map[string]*configpb.MessageUsage{
+					unlinked: configpb.NewMessageUsageBuilder().WithIsConsumed(true).Build(),
+				}), nil
+			},
+		}
+
+		apps, err := ListApplications(t.Context(), client)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		r := apps[0].HandlerComponents[0].HandlerProperties().RouteComponents[0]
+		if r.MessageType.IsPresent() {
+			t.Fatal("did not expect the message type to be present")
+		}
+	})
+
+	t.Run("it returns an error if an application is invalid", func(t *testing.T) {
+		client := &ConfigAPIClientStub{
+			ListApplicationsFunc: func(context.Context, *ListApplicationsRequest, ...grpc.CallOption) (*ListApplicationsResponse, error) {
+				// A projection with no routes is invalid.
+				return newResponse(nil), nil
+			},
+		}
+
+		_, err := ListApplications(t.Context(), client)
+
+		var cerr config.InvalidComponentError
+		if !errors.As(err, &cerr) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("it returns an error if the RPC fails", func(t *testing.T) {
+		want := errors.New("<error>")
+
+		client := &ConfigAPIClientStub{
+			ListApplicationsFunc: func(context.Context, *ListApplicationsRequest, ...grpc.CallOption) (*ListApplicationsResponse, error) {
+				return nil, want
+			},
+		}
+
+		if _, err := ListApplications(t.Context(), client); err != want {
+			t.Fatalf("unexpected error: got %v, want %v", err, want)
+		}
+	})
+}
+
+func TestDialAndListApplications(t *testing.T) {
+	t.Run("it returns the applications served at the target", func(t *testing.T) {
+		app := runtimeconfig.FromApplication(&ApplicationStub{
+			ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+				c.Identity("app", "bed53df8-bf22-4502-be4b-64d56532d8be")
+			},
+		})
+
+		server, err := NewServer(app)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		apps, err := DialAndListApplications(t.Context(), bufconnTarget, serve(t, server)...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(apps) != 1 {
+			t.Fatalf("unexpected number of applications: got %d, want 1", len(apps))
+		}
+
+		Expect(
+			t,
+			"unexpected application identity",
+			apps[0].Identity(),
+			app.Identity(),
+		)
+	})
+
+	t.Run("it returns an error if the connection can not be created", func(t *testing.T) {
+		// Without transport credentials, the client can not be created.
+		if _, err := DialAndListApplications(t.Context(), bufconnTarget); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
@@ -0,0 +1,3 @@
+// Package configgrpc defines a gRPC service for inspecting the configuration of
+// the Dogma applications hosted by an engine.
+package configgrpc
@@ -0,0 +1,47 @@
+package configgrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/protobuf/configpb"
+)
+
+// Server is an implementation of [ConfigAPIServer] that serves the
+// configuration of a fixed set of applications.
+type Server struct {
+	UnimplementedConfigAPIServer
+
+	applications []*configpb.Application
+}
+
+// NewServer returns a [Server] that serves the configuration of the given
+// applications.
+//
+// It returns an error if any of the applications cannot be represented by
+// [configpb.FromApplication].
+func NewServer(apps ...*config.Application) (*Server, error) {
+	s := &Server{}
+
+	for _, app := range apps {
+		x, err := configpb.FromApplication(app)
+		if err != nil {
+			return nil, fmt.Errorf("unable to serve %s: %w", app, err)
+		}
+
+		s.applications = append(s.applications, x)
+	}
+
+	return s, nil
+}
+
+// ListApplications returns the full configuration of all applications.
+func (s *Server) ListApplications(
+	context.Context,
+	*ListApplicationsRequest,
+) (*ListApplicationsResponse, error) {
+	return NewListApplicationsResponseBuilder().
+		WithApplications(s.applications).
+		Build(), nil
+}
@@ -0,0 +1,164 @@
+package configgrpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/config/runtimeconfig"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/grpc/configgrpc"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServer(t *testing.T) {
+	app := runtimeconfig.FromApplication(&ApplicationStub{
+		ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+			c.Identity("app", "bed53df8-bf22-4502-be4b-64d56532d8be")
+			c.Routes(
+				dogma.ViaAggregate(&AggregateMessageHandlerStub[*AggregateRootStub]{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("aggregate", "d9d75a75-7839-4b3e-a7e5-c8884b88ea57")
+						c.Routes(
+							dogma.HandlesCommand[*CommandStub[TypeA]](),
+							dogma.RecordsEvent[*EventStub[TypeA]](),
+						)
+					},
+				}),
+				dogma.ViaProjection(&ProjectionMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+						c.Identity("projection", "9c7a8bc6-9b1e-4f4c-8c44-7f3e6e0d9a51")
+						c.Routes(
+							dogma.HandlesEvent[*EventStub[TypeA]](),
+						)
+						c.Disable()
+					},
+				}),
+			)
+		},
+	})
+
+	server, err := NewServer(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := newClient(t, server)
+
+	t.Run("it serves the application configurations", func(t *testing.T) {
+		apps, err := ListApplications(t.Context(), client)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(apps) != 1 {
+			t.Fatalf("unexpected number of applications: got %d, want 1", len(apps))
+		}
+
+		got := apps[0]
+
+		Expect(
+			t,
+			"unexpected application identity",
+			got.Identity(),
+			app.Identity(),
+		)
+
+		Expect(
+			t,
+			"unexpected application type name",
+			got.TypeName,
+			app.TypeName,
+		)
+
+		if n := len(got.HandlerComponents); n != 2 {
+			t.Fatalf("unexpected number of handlers: got %d, want 2", n)
+		}
+
+		for i, h := range got.HandlerComponents {
+			want := app.HandlerComponents[i]
+
+			Expect(
+				t,
+				"unexpected handler type",
+				h.HandlerType(),
+				want.HandlerType(),
+			)
+
+			Expect(
+				t,
+				"unexpected handler identity",
+				h.Identity(),
+				want.Identity(),
+			)
+
+			Expect(
+				t,
+				"unexpected routes",
+				h.HandlerProperties().RouteComponents,
+				want.HandlerProperties().RouteComponents,
+			)
+
+			Expect(
+				t,
+				"unexpected disabled flags",
+				h.HandlerProperties().DisabledFlags,
+				want.HandlerProperties().DisabledFlags,
+			)
+		}
+	})
+
+	t.Run("func NewServer()", func(t *testing.T) {
+		t.Run("it returns an error if an application is invalid", func(t *testing.T) {
+			if _, err := NewServer(&config.Application{}); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	})
+}
+
+// bufconnTarget is the dial target used to connect to servers started by
+// [serve].
+const bufconnTarget = "passthrough:///bufconn"
+
+// serve starts a gRPC server that serves s via an in-memory listener, and
+// returns the dial options necessary to connect to it.
+func serve(t *testing.T, s ConfigAPIServer) []grpc.DialOption {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer()
+	RegisterConfigAPIServer(server, s)
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return []grpc.DialOption{
+		grpc.WithContextDialer(
+			func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			},
+		),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+}
+
+// newClient returns a [ConfigAPIClient] that is connected to s via an
+// in-memory listener.
+func newClient(t *testing.T, s ConfigAPIServer) ConfigAPIClient {
+	t.Helper()
+
+	conn, err := grpc.NewClient(bufconnTarget, serve(t, s)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewConfigAPIClient(conn)
+}
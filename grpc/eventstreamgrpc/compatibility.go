@@ -0,0 +1,125 @@
+package eventstreamgrpc
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+// Compatibility describes how the event streams offered by a ConsumeAPI server
+// relate to the events consumed by a local application.
+type Compatibility struct {
+	// Requests is the set of requests that the local application should make
+	// to consume all of the events that it handles from the server's streams.
+	//
+	// There is at most one request per stream. Each request's offset is zero;
+	// callers should set the offset as appropriate.
+	Requests []*ConsumeEventsRequest
+
+	// UnsatisfiedRoutes is the set of [config.HandlesEventRouteType] routes
+	// for event types that do not appear on any of the server's streams.
+	UnsatisfiedRoutes []UnsatisfiedRoute
+
+	// RedundantStreams is the set of streams that do not need to be consumed,
+	// either because they contain no events that the application handles, or
+	// because those events are available from another stream.
+	RedundantStreams []*Stream
+}
+
+// UnsatisfiedRoute is a route for an event type that is not available from
+// any stream.
+type UnsatisfiedRoute struct {
+	Handler config.Handler
+	Route   *config.Route
+}
+
+// IsCompatible returns true if every event handled by the application is
+// available from at least one stream.
+func (c *Compatibility) IsCompatible() bool {
+	return len(c.UnsatisfiedRoutes) == 0
+}
+
+// CheckCompatibility compares the events handled by app to the streams listed
+// in res.
+//
+// It considers the [config.HandlesEventRouteType] routes of each enabled
+// handler, excluding routes for events that are recorded by app itself. The
+// event type IDs of each stream are resolved to message types via Dogma's
+// message type registry; types that are not registered are ignored.
+//
+// When an event type is available from more than one stream, it is consumed
+// from whichever stream appears first in res.
+//
+// It panics if app is not a valid configuration.
+func CheckCompatibility(app *config.Application, res *ListStreamsResponse) *Compatibility {
+	routes := app.RouteSet()
+
+	recorded := routes.
+		Filter(config.FilterByRouteType(config.RecordsEventRouteType)).
+		MessageTypeSet()
+
+	handled := map[message.Type][]UnsatisfiedRoute{}
+
+	for r, h := range routes.Filter(config.FilterByRouteType(config.HandlesEventRouteType)).Routes() {
+		mt := r.MessageType.Get()
+
+		if h.IsDisabled() || recorded.Has(mt) {
+			continue
+		}
+
+		handled[mt] = append(handled[mt], UnsatisfiedRoute{h, r})
+	}
+
+	result := &Compatibility{}
+
+	for _, s := range res.GetStreams() {
+		var ids []*uuidpb.UUID
+
+		for _, id := range s.GetEventTypeIds() {
+			t, ok := dogma.RegisteredMessageTypeByID(id.AsString())
+			if !ok {
+				continue
+			}
+
+			mt := message.TypeFromReflect(t.GoType())
+
+			if _, ok := handled[mt]; ok {
+				ids = append(ids, id)
+				delete(handled, mt)
+			}
+		}
+
+		if len(ids) == 0 {
+			result.RedundantStreams = append(result.RedundantStreams, s)
+			continue
+		}
+
+		result.Requests = append(
+			result.Requests,
+			NewConsumeEventsRequestBuilder().
+				WithStreamId(s.GetStreamId()).
+				WithEventTypeIds(ids).
+				Build(),
+		)
+	}
+
+	for _, unsatisfied := range handled {
+		result.UnsatisfiedRoutes = append(result.UnsatisfiedRoutes, unsatisfied...)
+	}
+
+	slices.SortFunc(
+		result.UnsatisfiedRoutes,
+		func(a, b UnsatisfiedRoute) int {
+			return cmp.Or(
+				cmp.Compare(a.Route.MessageTypeName.Get(), b.Route.MessageTypeName.Get()),
+				cmp.Compare(a.Handler.Identity().GetName(), b.Handler.Identity().GetName()),
+			)
+		},
+	)
+
+	return result
+}
@@ -0,0 +1,177 @@
+package eventstreamgrpc_test
+
+import (
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/config/runtimeconfig"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/grpc/eventstreamgrpc"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+func TestCheckCompatibility(t *testing.T) {
+	var (
+		typeA = uuidpb.MustParse(MessageTypeID[*EventStub[TypeA]]())
+		typeB = uuidpb.MustParse(MessageTypeID[*EventStub[TypeB]]())
+		typeC = uuidpb.MustParse(MessageTypeID[*EventStub[TypeC]]())
+		typeE = uuidpb.MustParse(MessageTypeID[*EventStub[TypeE]]())
+	)
+
+	app := runtimeconfig.FromApplication(&ApplicationStub{
+		ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+			c.Identity("app", "bed53df8-bf22-4502-be4b-64d56532d8be")
+			c.Routes(
+				dogma.ViaAggregate(&AggregateMessageHandlerStub[*AggregateRootStub]{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("aggregate", "d9d75a75-7839-4b3e-a7e5-c8884b88ea57")
+						c.Routes(
+							dogma.HandlesCommand[*CommandStub[TypeA]](),
+							dogma.RecordsEvent[*EventStub[TypeA]](),
+						)
+					},
+				}),
+				dogma.ViaProcess(&ProcessMessageHandlerStub[*ProcessRootStub]{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("process", "4ff1b1c1-5c64-4d6a-9a5f-3a9b4e0a4a3b")
+						c.Routes(
+							dogma.HandlesEvent[*EventStub[TypeB]](),
+							dogma.HandlesEvent[*EventStub[TypeD]](),
+							dogma.ExecutesCommand[*CommandStub[TypeA]](),
+						)
+					},
+				}),
+				dogma.ViaProjection(&ProjectionMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+						c.Identity("projection", "9c7a8bc6-9b1e-4f4c-8c44-7f3e6e0d9a51")
+						c.Routes(
+							dogma.HandlesEvent[*EventStub[TypeA]](),
+							dogma.HandlesEvent[*EventStub[TypeB]](),
+							dogma.HandlesEvent[*EventStub[TypeC]](),
+						)
+					},
+				}),
+				dogma.ViaProjection(&ProjectionMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+						c.Identity("disabled", "3b0e1d7c-0c1d-4c53-9a2a-7e2f9c5f6d11")
+						c.Routes(
+							dogma.HandlesEvent[*EventStub[TypeF]](),
+						)
+						c.Disable()
+					},
+				}),
+			)
+		},
+	})
+
+	var (
+		streamB = NewStreamBuilder().
+			WithStreamId(uuidpb.Generate()).
+			WithEventTypeIds([]*uuidpb.UUID{typeB}).
+			Build()
+
+		streamBC = NewStreamBuilder().
+				WithStreamId(uuidpb.Generate()).
+				WithEventTypeIds([]*uuidpb.UUID{typeB, uuidpb.Generate(), typeC}).
+				Build()
+
+		streamA = NewStreamBuilder().
+			WithStreamId(uuidpb.Generate()).
+			WithEventTypeIds([]*uuidpb.UUID{typeA}).
+			Build()
+
+		streamE = NewStreamBuilder().
+			WithStreamId(uuidpb.Generate()).
+			WithEventTypeIds([]*uuidpb.UUID{typeE}).
+			Build()
+	)
+
+	got := CheckCompatibility(
+		app,
+		NewListStreamsResponseBuilder().
+			WithStreams([]*Stream{streamB, streamBC, streamA, streamE}).
+			Build(),
+	)
+
+	t.Run("it returns a request for each stream that must be consumed", func(t *testing.T) {
+		Expect(
+			t,
+			"unexpected requests",
+			got.Requests,
+			[]*ConsumeEventsRequest{
+				NewConsumeEventsRequestBuilder().
+					WithStreamId(streamB.GetStreamId()).
+					WithEventTypeIds([]*uuidpb.UUID{typeB}).
+					Build(),
+				NewConsumeEventsRequestBuilder().
+					WithStreamId(streamBC.GetStreamId()).
+					WithEventTypeIds([]*uuidpb.UUID{typeC}).
+					Build(),
+			},
+		)
+	})
+
+	t.Run("it reports redundant streams", func(t *testing.T) {
+		Expect(
+			t,
+			"unexpected redundant streams",
+			got.RedundantStreams,
+			[]*Stream{streamA, streamE},
+		)
+	})
+
+	t.Run("it reports routes for events that are not available from any stream", func(t *testing.T) {
+		if got.IsCompatible() {
+			t.Fatal("did not expect the application to be compatible")
+		}
+
+		process, _ := app.HandlerByName("process")
+
+		if len(got.UnsatisfiedRoutes) != 1 {
+			t.Fatalf("unexpected number of unsatisfied routes: got %d, want 1", len(got.UnsatisfiedRoutes))
+		}
+
+		u := got.UnsatisfiedRoutes[0]
+
+		if u.Handler != process {
+			t.Fatalf("unexpected handler: got %s, want %s", u.Handler, process)
+		}
+
+		Expect(
+			t,
+			"unexpected route type",
+			u.Route.RouteType.Get(),
+			config.HandlesEventRouteType,
+		)
+
+		Expect(
+			t,
+			"unexpected message type ID",
+			u.Route.MessageTypeID.Get(),
+			MessageTypeID[*EventStub[TypeD]](),
+		)
+	})
+
+	t.Run("it reports compatibility when all events are available", func(t *testing.T) {
+		got := CheckCompatibility(
+			app,
+			NewListStreamsResponseBuilder().
+				WithStreams([]*Stream{
+					streamBC,
+					NewStreamBuilder().
+						WithStreamId(uuidpb.Generate()).
+						WithEventTypeIds([]*uuidpb.UUID{
+							uuidpb.MustParse(MessageTypeID[*EventStub[TypeD]]()),
+						}).
+						Build(),
+				}).
+				Build(),
+		)
+
+		if !got.IsCompatible() {
+			t.Fatal("expected the application to be compatible")
+		}
+	})
+}
@@ -1,6 +1,9 @@
 package eventstreamgrpc
 
 import (
+	"fmt"
+	"strings"
+
 	uuidpb "github.com/dogmatiq/enginekit/protobuf/uuidpb"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -41,17 +44,115 @@ func NoEventTypesError() error {
 // UnrecognizedEventTypeError returns an error indicating that the given event
 // type is not recognized by the server.
 func UnrecognizedEventTypeError(id *uuidpb.UUID) error {
-	s, err := status.
-		New(codes.InvalidArgument, "unrecognized event type").
-		WithDetails(
+	return unrecognizedEventTypesError([]*uuidpb.UUID{id})
+}
+
+// unrecognizedEventTypesError returns an error indicating that the given event
+// types are not recognized by the server.
+//
+// The returned error has an [UnrecognizedEventType] detail for each ID.
+func unrecognizedEventTypesError(ids []*uuidpb.UUID) error {
+	s := status.New(codes.InvalidArgument, "unrecognized event type")
+
+	for _, id := range ids {
+		var err error
+		s, err = s.WithDetails(
 			NewUnrecognizedEventTypeBuilder().
 				WithEventTypeId(id).
 				Build(),
 		)
 
-	if err != nil {
-		panic(err)
+		if err != nil {
+			panic(err)
+		}
 	}
 
 	return s.Err()
 }
+
+// UnrecognizedStreamDetailError is the client-side representation of an error
+// returned by [UnrecognizedStreamError].
+type UnrecognizedStreamDetailError struct {
+	StreamID *uuidpb.UUID
+}
+
+func (e UnrecognizedStreamDetailError) Error() string {
+	return fmt.Sprintf("unrecognized stream (%s)", e.StreamID)
+}
+
+// GRPCStatus returns the gRPC status that represents the error.
+func (e UnrecognizedStreamDetailError) GRPCStatus() *status.Status {
+	s, _ := status.FromError(UnrecognizedStreamError(e.StreamID))
+	return s
+}
+
+// NoEventTypesDetailError is the client-side representation of an error
+// returned by [NoEventTypesError].
+type NoEventTypesDetailError struct{}
+
+func (e NoEventTypesDetailError) Error() string {
+	return "no event types specified"
+}
+
+// GRPCStatus returns the gRPC status that represents the error.
+func (e NoEventTypesDetailError) GRPCStatus() *status.Status {
+	s, _ := status.FromError(NoEventTypesError())
+	return s
+}
+
+// UnrecognizedEventTypeDetailError is the client-side representation of an
+// error returned by [UnrecognizedEventTypeError].
+//
+// The server may report several unrecognized event types in a single error.
+type UnrecognizedEventTypeDetailError struct {
+	EventTypeIDs []*uuidpb.UUID
+}
+
+func (e UnrecognizedEventTypeDetailError) Error() string {
+	ids := make([]string, len(e.EventTypeIDs))
+	for i, id := range e.EventTypeIDs {
+		ids[i] = id.AsString()
+	}
+
+	return fmt.Sprintf("unrecognized event type (%s)", strings.Join(ids, ", "))
+}
+
+// GRPCStatus returns the gRPC status that represents the error.
+func (e UnrecognizedEventTypeDetailError) GRPCStatus() *status.Status {
+	s, _ := status.FromError(unrecognizedEventTypesError(e.EventTypeIDs))
+	return s
+}
+
+// DecodeError returns the client-side representation of an error returned by
+// [ConsumeAPIClient.ConsumeEvents].
+//
+// If err is a gRPC status error with an [UnrecognizedStream], [NoEventTypes] or
+// [UnrecognizedEventType] detail, it returns an
+// [UnrecognizedStreamDetailError], [NoEventTypesDetailError] or
+// [UnrecognizedEventTypeDetailError], respectively. Otherwise, it returns err
+// unchanged.
+func DecodeError(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+
+	var unrecognizedTypes UnrecognizedEventTypeDetailError
+
+	for _, d := range s.Details() {
+		switch d := d.(type) {
+		case *UnrecognizedStream:
+			return UnrecognizedStreamDetailError{d.GetStreamId()}
+		case *NoEventTypes:
+			return NoEventTypesDetailError{}
+		case *UnrecognizedEventType:
+			unrecognizedTypes.EventTypeIDs = append(unrecognizedTypes.EventTypeIDs, d.GetEventTypeId())
+		}
+	}
+
+	if len(unrecognizedTypes.EventTypeIDs) != 0 {
+		return unrecognizedTypes
+	}
+
+	return err
+}
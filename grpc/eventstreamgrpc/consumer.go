@@ -0,0 +1,231 @@
+package eventstreamgrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"time"
+
+	"github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Consumer consumes events from a single stream provided by a ConsumeAPI
+// server.
+//
+// If the connection to the server fails with a transient error, the consumer
+// reconnects after a backoff delay and resumes from the offset after the last
+// event it delivered.
+type Consumer struct {
+	// Client is the client used to communicate with the server.
+	Client ConsumeAPIClient
+
+	// StreamID is the ID of the stream to consume.
+	StreamID *uuidpb.UUID
+
+	// EventTypeIDs is the list of type IDs of the events to consume.
+	EventTypeIDs []*uuidpb.UUID
+
+	// Backoff returns the delay to wait before reconnecting after the n'th
+	// consecutive failure, starting at 1. If it is nil, [DefaultBackoff] is
+	// used.
+	Backoff func(n int) time.Duration
+
+	// CallOptions is a set of options to use for each ConsumeEvents call.
+	CallOptions []grpc.CallOption
+}
+
+// ConsumeFunc is a function that handles an event delivered by a [Consumer].
+//
+// offset is the event's offset within the stream. If it returns an error,
+// consumption stops and the error is returned by the consumer.
+type ConsumeFunc func(ctx context.Context, offset uint64, env *envelopepb.Envelope) error
+
+// DefaultBackoff is the default backoff strategy used by [Consumer].
+//
+// It returns an exponentially increasing delay with "full jitter", capped at
+// 30 seconds.
+func DefaultBackoff(n int) time.Duration {
+	const (
+		base  = 100 * time.Millisecond
+		limit = 30 * time.Second
+	)
+
+	d := limit
+	if n < 1 {
+		d = base
+	} else if n <= 16 {
+		d = min(base<<(n-1), limit)
+	}
+
+	return rand.N(d) + 1
+}
+
+// Consume calls fn for each event on the stream, starting at the given offset.
+//
+// It blocks until ctx is canceled, fn returns an error, or the server returns
+// an error that is not transient. Errors that describe a problem with the
+// consume request itself are returned as an [UnrecognizedStreamDetailError],
+// [NoEventTypesDetailError] or [UnrecognizedEventTypeDetailError].
+func (c *Consumer) Consume(ctx context.Context, offset uint64, fn ConsumeFunc) error {
+	failures := 0
+
+	for {
+		next, err := c.consume(ctx, offset, fn)
+
+		if next != offset {
+			offset = next
+			failures = 0
+		}
+
+		var fnErr consumeFuncError
+		if errors.As(err, &fnErr) {
+			return fnErr.Cause
+		}
+
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+
+		if !isTransient(err) {
+			return DecodeError(err)
+		}
+
+		failures++
+
+		backoff := c.Backoff
+		if backoff == nil {
+			backoff = DefaultBackoff
+		}
+
+		timer := time.NewTimer(backoff(failures))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return context.Cause(ctx)
+		case <-timer.C:
+		}
+	}
+}
+
+// Resume calls fn for each event on the stream that occurs after the event at
+// the given position.
+//
+// It is typically used with positions obtained from [Consumer.Position] that
+// have been persisted by the application. If pos is nil, consumption begins at
+// the start of the stream.
+//
+// It returns an error if pos refers to a different stream. Otherwise, it
+// behaves as per [Consumer.Consume].
+func (c *Consumer) Resume(ctx context.Context, pos *envelopepb.EventStreamPosition, fn ConsumeFunc) error {
+	if pos == nil {
+		return c.Consume(ctx, 0, fn)
+	}
+
+	if !pos.GetStreamId().Equal(c.StreamID) {
+		return fmt.Errorf(
+			"cannot resume from a position on stream %s, expected stream %s",
+			pos.GetStreamId(),
+			c.StreamID,
+		)
+	}
+
+	return c.Consume(ctx, pos.GetOffset()+1, fn)
+}
+
+// Position returns the position of the event at the given offset within the
+// consumer's stream.
+func (c *Consumer) Position(offset uint64) *envelopepb.EventStreamPosition {
+	return envelopepb.
+		NewEventStreamPositionBuilder().
+		WithStreamId(c.StreamID).
+		WithOffset(offset).
+		Build()
+}
+
+// consume makes a single ConsumeEvents call, and calls fn for each event
+// received. It returns the offset of the next event to be consumed.
+func (c *Consumer) consume(ctx context.Context, offset uint64, fn ConsumeFunc) (uint64, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	res, err := c.Client.ConsumeEvents(
+		ctx,
+		NewConsumeEventsRequestBuilder().
+			WithStreamId(c.StreamID).
+			WithOffset(offset).
+			WithEventTypeIds(c.EventTypeIDs).
+			Build(),
+		c.CallOptions...,
+	)
+	if err != nil {
+		return offset, err
+	}
+
+	for {
+		r, err := res.Recv()
+		if err != nil {
+			return offset, err
+		}
+
+		d, ok := r.TryGetEventDelivery()
+		if !ok {
+			continue
+		}
+
+		if d.GetOffset() < offset {
+			return offset, fmt.Errorf(
+				"server delivered an event at offset %d, expected offset %d or later",
+				d.GetOffset(),
+				offset,
+			)
+		}
+
+		if err := fn(ctx, d.GetOffset(), d.GetEnvelope()); err != nil {
+			return offset, consumeFuncError{err}
+		}
+
+		offset = d.GetOffset() + 1
+	}
+}
+
+// consumeFuncError wraps an error returned by a [ConsumeFunc] to distinguish
+// it from errors returned by the server.
+type consumeFuncError struct {
+	Cause error
+}
+
+func (e consumeFuncError) Error() string {
+	return e.Cause.Error()
+}
+
+// isTransient returns true if err is an error that may be resolved by
+// retrying the ConsumeEvents call.
+func isTransient(err error) bool {
+	if err == io.EOF {
+		// The server is expected to keep the stream open indefinitely, so
+		// reaching the end of the stream indicates a disconnection.
+		return true
+	}
+
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	switch s.Code() {
+	case codes.Unavailable,
+		codes.ResourceExhausted,
+		codes.Aborted,
+		codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,357 @@
+package eventstreamgrpc_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/grpc/eventstreamgrpc"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	"github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestConsumer(t *testing.T) {
+	var (
+		streamID = uuidpb.Generate()
+		typeA    = uuidpb.MustParse(MessageTypeID[*EventStub[TypeA]]())
+		typeB    = uuidpb.MustParse(MessageTypeID[*EventStub[TypeB]]())
+		typeC    = uuidpb.MustParse(MessageTypeID[*EventStub[TypeC]]())
+	)
+
+	type delivery struct {
+		Offset   uint64
+		Envelope *envelopepb.Envelope
+	}
+
+	setup := func(t *testing.T) (*MemoryServer, *Consumer, []*envelopepb.Envelope) {
+		server := &MemoryServer{}
+		server.AddStream(streamID, typeA, typeB)
+
+		events := packEvents(EventA1, EventB1, EventA2, EventB2)
+		server.Append(streamID, events...)
+
+		consumer := &Consumer{
+			Client:       newClient(t, server),
+			StreamID:     streamID,
+			EventTypeIDs: []*uuidpb.UUID{typeA},
+			Backoff:      func(int) time.Duration { return time.Millisecond },
+		}
+
+		return server, consumer, events
+	}
+
+	// collect returns a [ConsumeFunc] that appends each delivery to *got, and
+	// stops consuming once n deliveries have been made.
+	stop := errors.New("<stop>")
+	collect := func(got *[]delivery, n int) ConsumeFunc {
+		return func(_ context.Context, offset uint64, env *envelopepb.Envelope) error {
+			*got = append(*got, delivery{offset, env})
+			if len(*got) == n {
+				return stop
+			}
+			return nil
+		}
+	}
+
+	t.Run("func Consume()", func(t *testing.T) {
+		t.Run("it delivers events of the requested types, starting at the requested offset", func(t *testing.T) {
+			_, consumer, events := setup(t)
+
+			var got []delivery
+			err := consumer.Consume(t.Context(), 1, collect(&got, 1))
+			if err != stop {
+				t.Fatalf("unexpected error: got %v, want %v", err, stop)
+			}
+
+			Expect(
+				t,
+				"unexpected deliveries",
+				got,
+				[]delivery{{2, events[2]}},
+			)
+		})
+
+		t.Run("it resumes after the last delivered event when the connection fails", func(t *testing.T) {
+			server, consumer, events := setup(t)
+
+			var offsets []uint64
+			client := consumer.Client
+
+			consumer.Client = &ConsumeAPIClientStub{
+				ConsumeEventsFunc: func(
+					ctx context.Context,
+					req *ConsumeEventsRequest,
+					options ...grpc.CallOption,
+				) (ConsumeAPI_ConsumeEventsClient, error) {
+					offsets = append(offsets, req.GetOffset())
+
+					if len(offsets) == 2 {
+						return nil, status.Error(codes.Unavailable, "<unavailable>")
+					}
+
+					res, err := client.ConsumeEvents(ctx, req, options...)
+					if err != nil {
+						return nil, err
+					}
+
+					return &failingStream{res, 1}, nil
+				},
+			}
+
+			more := packEvents(EventA3)
+			server.Append(streamID, more...)
+
+			var got []delivery
+			err := consumer.Consume(t.Context(), 0, collect(&got, 3))
+			if err != stop {
+				t.Fatalf("unexpected error: got %v, want %v", err, stop)
+			}
+
+			Expect(
+				t,
+				"unexpected deliveries",
+				got,
+				[]delivery{
+					{0, events[0]},
+					{2, events[2]},
+					{4, more[0]},
+				},
+			)
+
+			Expect(
+				t,
+				"unexpected request offsets",
+				offsets,
+				[]uint64{0, 1, 1, 3},
+			)
+		})
+
+		t.Run("it returns the cause of the context's cancelation", func(t *testing.T) {
+			_, consumer, _ := setup(t)
+
+			cause := errors.New("<cause>")
+			ctx, cancel := context.WithCancelCause(t.Context())
+
+			err := consumer.Consume(
+				ctx,
+				0,
+				func(context.Context, uint64, *envelopepb.Envelope) error {
+					cancel(cause)
+					return nil
+				},
+			)
+			if err != cause {
+				t.Fatalf("unexpected error: got %v, want %v", err, cause)
+			}
+		})
+
+		t.Run("it returns an error if the server returns a non-transient error", func(t *testing.T) {
+			_, consumer, _ := setup(t)
+
+			consumer.Client = &ConsumeAPIClientStub{}
+
+			err := consumer.Consume(t.Context(), 0, collect(new([]delivery), 1))
+			if status.Code(err) != codes.Unimplemented {
+				t.Fatalf("unexpected error: got %v, want unimplemented", err)
+			}
+		})
+
+		t.Run("it does not retry internal server errors", func(t *testing.T) {
+			_, consumer, _ := setup(t)
+
+			calls := 0
+			consumer.Client = &ConsumeAPIClientStub{
+				ConsumeEventsFunc: func(
+					context.Context,
+					*ConsumeEventsRequest,
+					...grpc.CallOption,
+				) (ConsumeAPI_ConsumeEventsClient, error) {
+					calls++
+					return nil, status.Error(codes.Internal, "<internal>")
+				},
+			}
+
+			err := consumer.Consume(t.Context(), 0, collect(new([]delivery), 1))
+			if status.Code(err) != codes.Internal {
+				t.Fatalf("unexpected error: got %v, want internal", err)
+			}
+
+			Expect(t, "unexpected call count", calls, 1)
+		})
+
+		t.Run("it returns an UnrecognizedStreamDetailError if the stream is not recognized", func(t *testing.T) {
+			_, consumer, _ := setup(t)
+
+			consumer.StreamID = uuidpb.Generate()
+
+			err := consumer.Consume(t.Context(), 0, collect(new([]delivery), 1))
+
+			var got UnrecognizedStreamDetailError
+			if !errors.As(err, &got) {
+				t.Fatalf("unexpected error: got %v, want UnrecognizedStreamDetailError", err)
+			}
+
+			Expect(
+				t,
+				"unexpected stream ID",
+				got.StreamID,
+				consumer.StreamID,
+			)
+		})
+
+		t.Run("it returns a NoEventTypesDetailError if no event types are requested", func(t *testing.T) {
+			_, consumer, _ := setup(t)
+
+			consumer.EventTypeIDs = nil
+
+			err := consumer.Consume(t.Context(), 0, collect(new([]delivery), 1))
+
+			if !errors.As(err, new(NoEventTypesDetailError)) {
+				t.Fatalf("unexpected error: got %v, want NoEventTypesDetailError", err)
+			}
+		})
+
+		t.Run("it returns an UnrecognizedEventTypeDetailError if any event types are not recognized", func(t *testing.T) {
+			_, consumer, _ := setup(t)
+
+			typeD := uuidpb.MustParse(MessageTypeID[*EventStub[TypeD]]())
+			consumer.EventTypeIDs = []*uuidpb.UUID{typeA, typeC, typeD}
+
+			err := consumer.Consume(t.Context(), 0, collect(new([]delivery), 1))
+
+			var got UnrecognizedEventTypeDetailError
+			if !errors.As(err, &got) {
+				t.Fatalf("unexpected error: got %v, want UnrecognizedEventTypeDetailError", err)
+			}
+
+			Expect(
+				t,
+				"unexpected event type IDs",
+				got.EventTypeIDs,
+				[]*uuidpb.UUID{typeC, typeD},
+			)
+		})
+	})
+
+	t.Run("func Resume()", func(t *testing.T) {
+		t.Run("it delivers events that occur after the given position", func(t *testing.T) {
+			_, consumer, events := setup(t)
+
+			var got []delivery
+			err := consumer.Resume(t.Context(), consumer.Position(0), collect(&got, 1))
+			if err != stop {
+				t.Fatalf("unexpected error: got %v, want %v", err, stop)
+			}
+
+			Expect(
+				t,
+				"unexpected deliveries",
+				got,
+				[]delivery{{2, events[2]}},
+			)
+		})
+
+		t.Run("it starts at the beginning of the stream if the position is nil", func(t *testing.T) {
+			_, consumer, events := setup(t)
+
+			var got []delivery
+			err := consumer.Resume(t.Context(), nil, collect(&got, 1))
+			if err != stop {
+				t.Fatalf("unexpected error: got %v, want %v", err, stop)
+			}
+
+			Expect(
+				t,
+				"unexpected deliveries",
+				got,
+				[]delivery{{0, events[0]}},
+			)
+		})
+
+		t.Run("it returns an error if the position refers to a different stream", func(t *testing.T) {
+			_, consumer, _ := setup(t)
+
+			pos := envelopepb.
+				NewEventStreamPositionBuilder().
+				WithStreamId(uuidpb.Generate()).
+				Build()
+
+			if err := consumer.Resume(t.Context(), pos, collect(new([]delivery), 1)); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	})
+}
+
+func TestDecodeError(t *testing.T) {
+	id := uuidpb.Generate()
+
+	cases := []struct {
+		Desc string
+		Err  error
+		Want error
+	}{
+		{
+			"unrecognized stream",
+			UnrecognizedStreamError(id),
+			UnrecognizedStreamDetailError{id},
+		},
+		{
+			"no event types",
+			NoEventTypesError(),
+			NoEventTypesDetailError{},
+		},
+		{
+			"unrecognized event type",
+			UnrecognizedEventTypeError(id),
+			UnrecognizedEventTypeDetailError{[]*uuidpb.UUID{id}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Desc, func(t *testing.T) {
+			got := DecodeError(c.Err)
+
+			if reflect.TypeOf(got) != reflect.TypeOf(c.Want) {
+				t.Fatalf("unexpected error type: got %T, want %T", got, c.Want)
+			}
+
+			Expect(
+				t,
+				"unexpected status",
+				status.Convert(got).Proto(),
+				status.Convert(c.Err).Proto(),
+			)
+		})
+	}
+
+	t.Run("it returns other errors unchanged", func(t *testing.T) {
+		err := status.Error(codes.Unavailable, "<unavailable>")
+
+		if got := DecodeError(err); got != err {
+			t.Fatalf("unexpected error: got %v, want %v", got, err)
+		}
+	})
+}
+
+// failingStream is a [ConsumeAPI_ConsumeEventsClient] that fails with an
+// UNAVAILABLE error after receiving n responses.
+type failingStream struct {
+	ConsumeAPI_ConsumeEventsClient
+	n int
+}
+
+func (s *failingStream) Recv() (*ConsumeEventsResponse, error) {
+	if s.n == 0 {
+		return nil, status.Error(codes.Unavailable, "<unavailable>")
+	}
+	s.n--
+	return s.ConsumeAPI_ConsumeEventsClient.Recv()
+}
@@ -0,0 +1,238 @@
+package eventstreamgrpc
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// MemoryServer is an in-memory implementation of [ConsumeAPIServer].
+//
+// It serves a set of append-only event streams, each identified by a stream
+// ID. It is intended for use as a test double, and as a reference
+// implementation of the ConsumeAPI protocol.
+//
+// The zero-value is a server with no streams.
+type MemoryServer struct {
+	UnimplementedConsumeAPIServer
+
+	m       sync.Mutex
+	streams uuidpb.Map[*memoryStream]
+	order   []*memoryStream
+}
+
+// memoryStream is an append-only event stream within a [MemoryServer].
+type memoryStream struct {
+	ID           *uuidpb.UUID
+	EventTypeIDs []*uuidpb.UUID
+	EventTypes   uuidpb.Set
+
+	// Events is the list of events on the stream, indexed by offset.
+	Events []*envelopepb.Envelope
+
+	// Appended is closed, and replaced with a new channel, when events are
+	// appended to the stream.
+	Appended chan struct{}
+}
+
+// AddStream adds a new, empty stream to the server.
+//
+// Events with any of the given type IDs may be appended to the stream.
+//
+// It panics if the server already has a stream with the same ID.
+func (s *MemoryServer) AddStream(id *uuidpb.UUID, eventTypeIDs ...*uuidpb.UUID) {
+	if err := id.Validate(); err != nil {
+		panic(fmt.Sprintf("invalid stream ID: %s", err))
+	}
+
+	stream := &memoryStream{
+		ID:       id,
+		Appended: make(chan struct{}),
+	}
+
+	for _, t := range eventTypeIDs {
+		if !stream.EventTypes.Has(t) {
+			stream.EventTypes.Add(t)
+			stream.EventTypeIDs = append(stream.EventTypeIDs, t)
+		}
+	}
+
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if s.streams.Has(id) {
+		panic(fmt.Sprintf("stream %s already exists", id))
+	}
+
+	s.streams.Set(id, stream)
+	s.order = append(s.order, stream)
+}
+
+// Append appends events to the stream with the given ID, and returns the
+// offset of the first appended event.
+//
+// It panics if there is no such stream, if any of the envelopes are invalid,
+// or if any of the events have a type ID that is not associated with the
+// stream.
+func (s *MemoryServer) Append(streamID *uuidpb.UUID, events ...*envelopepb.Envelope) uint64 {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	stream, ok := s.streams.Get(streamID)
+	if !ok {
+		panic(fmt.Sprintf("stream %s does not exist", streamID))
+	}
+
+	for _, env := range events {
+		if err := env.Validate(); err != nil {
+			panic(fmt.Sprintf("invalid envelope: %s", err))
+		}
+
+		if t := env.GetBody().GetMessage().GetTypeId(); !stream.EventTypes.Has(t) {
+			panic(fmt.Sprintf(
+				"stream %s does not support events of type %s",
+				streamID,
+				t,
+			))
+		}
+	}
+
+	offset := uint64(len(stream.Events))
+
+	if len(events) != 0 {
+		stream.Events = append(stream.Events, events...)
+		close(stream.Appended)
+		stream.Appended = make(chan struct{})
+	}
+
+	return offset
+}
+
+// ListStreams lists the streams that the server provides.
+func (s *MemoryServer) ListStreams(
+	context.Context,
+	*ListStreamsRequest,
+) (*ListStreamsResponse, error) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	var streams []*Stream
+
+	for _, stream := range s.order {
+		streams = append(
+			streams,
+			NewStreamBuilder().
+				WithStreamId(stream.ID).
+				WithEventTypeIds(stream.EventTypeIDs).
+				Build(),
+		)
+	}
+
+	return NewListStreamsResponseBuilder().
+		WithStreams(streams).
+		Build(), nil
+}
+
+// ConsumeEvents starts consuming from a specific offset within an event
+// stream.
+//
+// It sends each event on the stream at or after the requested offset that has
+// one of the requested event types. Once all such events have been sent, it
+// waits for new events to be appended to the stream, until the client cancels
+// the request.
+func (s *MemoryServer) ConsumeEvents(
+	req *ConsumeEventsRequest,
+	res grpc.ServerStreamingServer[ConsumeEventsResponse],
+) error {
+	stream, filter, err := s.prepareConsume(req)
+	if err != nil {
+		return err
+	}
+
+	ctx := res.Context()
+	offset := req.GetOffset()
+
+	for {
+		events, appended := s.eventsFrom(stream, offset)
+
+		for _, env := range events {
+			if filter.Has(env.GetBody().GetMessage().GetTypeId()) {
+				if err := res.Send(
+					NewConsumeEventsResponseBuilder().
+						WithEventDelivery(
+							NewConsumeEventsResponse_EventDeliveryBuilder().
+								WithOffset(offset).
+								WithEnvelope(env).
+								Build(),
+						).
+						Build(),
+				); err != nil {
+					return err
+				}
+			}
+
+			offset++
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(context.Cause(ctx)).Err()
+		case <-appended:
+		}
+	}
+}
+
+// prepareConsume validates req, and returns the requested stream and the set
+// of requested event types.
+func (s *MemoryServer) prepareConsume(req *ConsumeEventsRequest) (*memoryStream, *uuidpb.Set, error) {
+	s.m.Lock()
+	stream, ok := s.streams.Get(req.GetStreamId())
+	s.m.Unlock()
+
+	if !ok {
+		return nil, nil, UnrecognizedStreamError(req.GetStreamId())
+	}
+
+	if len(req.GetEventTypeIds()) == 0 {
+		return nil, nil, NoEventTypesError()
+	}
+
+	var (
+		filter       uuidpb.Set
+		unrecognized []*uuidpb.UUID
+	)
+
+	for _, t := range req.GetEventTypeIds() {
+		if !stream.EventTypes.Has(t) {
+			unrecognized = append(unrecognized, t)
+		}
+		filter.Add(t)
+	}
+
+	if len(unrecognized) != 0 {
+		return nil, nil, unrecognizedEventTypesError(unrecognized)
+	}
+
+	return stream, &filter, nil
+}
+
+// eventsFrom returns the events on stream starting at the given offset, and a
+// channel that is closed when more events are appended.
+func (s *MemoryServer) eventsFrom(
+	stream *memoryStream,
+	offset uint64,
+) ([]*envelopepb.Envelope, <-chan struct{}) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if offset >= uint64(len(stream.Events)) {
+		return nil, stream.Appended
+	}
+
+	return stream.Events[offset:], stream.Appended
+}
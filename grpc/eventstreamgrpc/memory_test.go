@@ -0,0 +1,366 @@
+package eventstreamgrpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/grpc/eventstreamgrpc"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	"github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+func TestMemoryServer(t *testing.T) {
+	var (
+		streamID = uuidpb.Generate()
+		typeA    = uuidpb.MustParse(MessageTypeID[*EventStub[TypeA]]())
+		typeB    = uuidpb.MustParse(MessageTypeID[*EventStub[TypeB]]())
+		typeC    = uuidpb.MustParse(MessageTypeID[*EventStub[TypeC]]())
+	)
+
+	setup := func(t *testing.T) (*MemoryServer, ConsumeAPIClient) {
+		server := &MemoryServer{}
+		server.AddStream(streamID, typeA, typeB)
+		return server, newClient(t, server)
+	}
+
+	t.Run("func ListStreams()", func(t *testing.T) {
+		t.Run("it returns the streams in the order they were added", func(t *testing.T) {
+			server, client := setup(t)
+
+			otherID := uuidpb.Generate()
+			server.AddStream(otherID, typeC)
+
+			res, err := client.ListStreams(t.Context(), &ListStreamsRequest{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(
+				t,
+				"unexpected streams",
+				res.GetStreams(),
+				[]*Stream{
+					NewStreamBuilder().
+						WithStreamId(streamID).
+						WithEventTypeIds([]*uuidpb.UUID{typeA, typeB}).
+						Build(),
+					NewStreamBuilder().
+						WithStreamId(otherID).
+						WithEventTypeIds([]*uuidpb.UUID{typeC}).
+						Build(),
+				},
+			)
+		})
+	})
+
+	t.Run("func ConsumeEvents()", func(t *testing.T) {
+		t.Run("it delivers events of the requested types, starting at the requested offset", func(t *testing.T) {
+			server, client := setup(t)
+
+			events := packEvents(EventA1, EventB1, EventA2, EventB2)
+			server.Append(streamID, events...)
+
+			ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+			defer cancel()
+
+			res, err := client.ConsumeEvents(
+				ctx,
+				NewConsumeEventsRequestBuilder().
+					WithStreamId(streamID).
+					WithOffset(1).
+					WithEventTypeIds([]*uuidpb.UUID{typeA}).
+					Build(),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			expectDelivery(t, res, 2, events[2])
+		})
+
+		t.Run("it delivers events that are appended after consuming begins", func(t *testing.T) {
+			server, client := setup(t)
+
+			ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+			defer cancel()
+
+			res, err := client.ConsumeEvents(
+				ctx,
+				NewConsumeEventsRequestBuilder().
+					WithStreamId(streamID).
+					WithEventTypeIds([]*uuidpb.UUID{typeA, typeB}).
+					Build(),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			events := packEvents(EventA1, EventB1)
+
+			server.Append(streamID, events[0])
+			expectDelivery(t, res, 0, events[0])
+
+			server.Append(streamID, events[1])
+			expectDelivery(t, res, 1, events[1])
+		})
+
+		t.Run("it returns an error if the stream is not recognized", func(t *testing.T) {
+			_, client := setup(t)
+
+			id := uuidpb.Generate()
+
+			err := consumeError(
+				t,
+				client,
+				NewConsumeEventsRequestBuilder().
+					WithStreamId(id).
+					WithEventTypeIds([]*uuidpb.UUID{typeA}).
+					Build(),
+			)
+
+			expectStatus(
+				t,
+				err,
+				codes.NotFound,
+				NewUnrecognizedStreamBuilder().
+					WithStreamId(id).
+					Build(),
+			)
+		})
+
+		t.Run("it returns an error if no event types are requested", func(t *testing.T) {
+			_, client := setup(t)
+
+			err := consumeError(
+				t,
+				client,
+				NewConsumeEventsRequestBuilder().
+					WithStreamId(streamID).
+					Build(),
+			)
+
+			expectStatus(
+				t,
+				err,
+				codes.InvalidArgument,
+				&NoEventTypes{},
+			)
+		})
+
+		t.Run("it returns an error for each unrecognized event type", func(t *testing.T) {
+			_, client := setup(t)
+
+			typeD := uuidpb.MustParse(MessageTypeID[*EventStub[TypeD]]())
+
+			err := consumeError(
+				t,
+				client,
+				NewConsumeEventsRequestBuilder().
+					WithStreamId(streamID).
+					WithEventTypeIds([]*uuidpb.UUID{typeA, typeC, typeD}).
+					Build(),
+			)
+
+			expectStatus(
+				t,
+				err,
+				codes.InvalidArgument,
+				NewUnrecognizedEventTypeBuilder().
+					WithEventTypeId(typeC).
+					Build(),
+				NewUnrecognizedEventTypeBuilder().
+					WithEventTypeId(typeD).
+					Build(),
+			)
+		})
+	})
+
+	t.Run("func Append()", func(t *testing.T) {
+		t.Run("it returns the offset of the first event", func(t *testing.T) {
+			server, _ := setup(t)
+
+			if got := server.Append(streamID, packEvents(EventA1, EventA2)...); got != 0 {
+				t.Fatalf("unexpected offset: got %d, want 0", got)
+			}
+
+			if got := server.Append(streamID, packEvents(EventB1)...); got != 2 {
+				t.Fatalf("unexpected offset: got %d, want 2", got)
+			}
+		})
+
+		t.Run("it panics if the stream does not exist", func(t *testing.T) {
+			server := &MemoryServer{}
+			id := uuidpb.Generate()
+
+			ExpectPanic(
+				t,
+				"stream "+id.AsString()+" does not exist",
+				func() {
+					server.Append(id)
+				},
+			)
+		})
+
+		t.Run("it panics if the event type is not associated with the stream", func(t *testing.T) {
+			server, _ := setup(t)
+
+			ExpectPanic(
+				t,
+				"stream "+streamID.AsString()+" does not support events of type "+typeC.AsString(),
+				func() {
+					server.Append(streamID, packEvents(EventC1)...)
+				},
+			)
+		})
+	})
+
+	t.Run("func AddStream()", func(t *testing.T) {
+		t.Run("it panics if the stream already exists", func(t *testing.T) {
+			server, _ := setup(t)
+
+			ExpectPanic(
+				t,
+				"stream "+streamID.AsString()+" already exists",
+				func() {
+					server.AddStream(streamID)
+				},
+			)
+		})
+	})
+}
+
+// newClient returns a [ConsumeAPIClient] that is connected to s via an
+// in-memory listener.
+func newClient(t *testing.T, s ConsumeAPIServer) ConsumeAPIClient {
+	t.Helper()
+
+	lis := bufconn.Listen(1024 * 1024)
+
+	server := grpc.NewServer()
+	RegisterConsumeAPIServer(server, s)
+
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(
+			func(ctx context.Context, _ string) (net.Conn, error) {
+				return lis.DialContext(ctx)
+			},
+		),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewConsumeAPIClient(conn)
+}
+
+// packEvents returns envelopes containing the given events.
+func packEvents(events ...dogma.Event) []*envelopepb.Envelope {
+	packer := &envelopepb.Packer{
+		Application: identitypb.New("app", uuidpb.Generate()),
+	}
+
+	cause := packer.PackCommand(CommandA1)
+	effects := packer.PackEffects(cause, identitypb.New("handler", uuidpb.Generate()))
+
+	var envelopes []*envelopepb.Envelope
+	for _, e := range events {
+		envelopes = append(envelopes, effects.PackEvent(e))
+	}
+
+	return envelopes
+}
+
+// expectDelivery asserts that the next response on res is a delivery of env at
+// the given offset.
+func expectDelivery(
+	t *testing.T,
+	res grpc.ServerStreamingClient[ConsumeEventsResponse],
+	offset uint64,
+	env *envelopepb.Envelope,
+) {
+	t.Helper()
+
+	got, err := res.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Expect(
+		t,
+		"unexpected response",
+		got,
+		NewConsumeEventsResponseBuilder().
+			WithEventDelivery(
+				NewConsumeEventsResponse_EventDeliveryBuilder().
+					WithOffset(offset).
+					WithEnvelope(env).
+					Build(),
+			).
+			Build(),
+	)
+}
+
+// consumeError returns the error produced by a ConsumeEvents request.
+func consumeError(t *testing.T, client ConsumeAPIClient, req *ConsumeEventsRequest) error {
+	t.Helper()
+
+	res, err := client.ConsumeEvents(t.Context(), req)
+	if err != nil {
+		return err
+	}
+
+	_, err = res.Recv()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	return err
+}
+
+// expectStatus asserts that err is a gRPC status error with the given code and
+// details.
+func expectStatus(t *testing.T, err error, code codes.Code, details ...protoadapt.MessageV1) {
+	t.Helper()
+
+	s, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("expected a gRPC status error, got %v", err)
+	}
+
+	Expect(
+		t,
+		"unexpected status code",
+		s.Code(),
+		code,
+	)
+
+	var want []any
+	for _, d := range details {
+		want = append(want, d)
+	}
+
+	Expect(
+		t,
+		"unexpected status details",
+		s.Details(),
+		want,
+	)
+}
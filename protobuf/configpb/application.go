@@ -0,0 +1,395 @@
+package configpb
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/internal/typename"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/enginekit/optional"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// FromApplication returns the Protocol Buffers representation of app.
+//
+// It returns an error if app is not a valid configuration, as per
+// [config.Validate].
+func FromApplication(app *config.Application) (*Application, error) {
+	if err := config.Validate(app); err != nil {
+		return nil, err
+	}
+
+	messages := map[string]MessageKind{}
+	var handlers []*Handler
+
+	for _, h := range app.HandlerComponents {
+		x, err := fromHandler(h, messages)
+		if err != nil {
+			return nil, err
+		}
+
+		handlers = append(handlers, x)
+	}
+
+	extensions, err := fromExtensions(app)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewApplicationBuilder().
+		WithIdentity(fromIdentity(app)).
+		WithGoType(goType(app)).
+		WithHandlers(handlers).
+		WithMessages(messages).
+		WithExtensions(extensions).
+		Build(), nil
+}
+
+// ToApplication returns the [config.Application] represented by x.
+//
+// The returned configuration has no source values, as they are not available
+// from the Protocol Buffers representation. Instead, each entity's TypeName is
+// populated.
+//
+// Each [config.Route] refers to a [message.Type] only if a message type with
+// the same name is present in Dogma's message type registry, as is the case
+// when the message type is linked into the current binary. Otherwise, the
+// route has only its route type and message type name. Such routes are valid
+// as per [config.Validate], but not [config.ForExecution], so the result
+// round-trips via [FromApplication] even when its message types are not
+// available.
+//
+// It returns an error if x cannot be represented as a [config.Application].
+// It does not validate the resulting configuration; use [config.Validate].
+func ToApplication(x *Application) (*config.Application, error) {
+	app := &config.Application{}
+
+	if err := toEntity(&app.EntityCommon, x.GetIdentity(), x.GetGoType()); err != nil {
+		return nil, err
+	}
+
+	registry := registeredMessageTypesByName()
+
+	for _, h := range x.GetHandlers() {
+		handler, err := toHandler(h, x.GetMessages(), registry)
+		if err != nil {
+			return nil, err
+		}
+
+		app.HandlerComponents = append(app.HandlerComponents, handler)
+	}
+
+	app.Extensions = toExtensions(x.GetExtensions())
+
+	return app, nil
+}
+
+func fromIdentity(e config.Entity) *identitypb.Identity {
+	id := e.EntityProperties().IdentityComponents[0]
+
+	return identitypb.
+		NewIdentityBuilder().
+		WithName(id.Name.Get()).
+		WithKey(uuidpb.MustParse(id.Key.Get())).
+		Build()
+}
+
+// goType returns the fully-qualified name of the Go type that implements e, or
+// an empty string if it is not known.
+func goType(e config.Entity) string {
+	n, _ := e.EntityProperties().TypeName.TryGet()
+	return n
+}
+
+func fromHandler(h config.Handler, kinds map[string]MessageKind) (*Handler, error) {
+	disabled, options, err := isDisabled(h)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := map[string]*MessageUsage{}
+
+	for _, r := range h.HandlerProperties().RouteComponents {
+		rt := r.RouteType.Get()
+		name := r.MessageTypeName.Get()
+		dir := rt.Direction()
+
+		kinds[name] = fromMessageKind(rt.MessageKind())
+
+		u, ok := usages[name]
+		if !ok {
+			u = &MessageUsage{}
+			usages[name] = u
+		}
+
+		if dir.Has(config.InboundDirection) {
+			u.SetIsConsumed(true)
+		}
+
+		if dir.Has(config.OutboundDirection) {
+			u.SetIsProduced(true)
+		}
+	}
+
+	extensions, err := fromExtensions(h)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewHandlerBuilder().
+		WithIdentity(fromIdentity(h)).
+		WithGoType(goType(h)).
+		WithType(fromHandlerType(h.HandlerType())).
+		WithMessages(usages).
+		WithIsDisabled(disabled).
+		WithExtensions(extensions).
+		WithDisableOptions(options).
+		Build(), nil
+}
+
+// isDisabled returns true if h is disabled, along with the string
+// representation of the options that were supplied when it was disabled.
+//
+// Unlike [config.Handler.IsDisabled], it does not require the configuration to
+// be suitable for execution. It returns an error if the configuration does not
+// specify unambiguously whether the handler is enabled or disabled.
+func isDisabled(h config.Handler) (bool, []string, error) {
+	flags := h.HandlerProperties().DisabledFlags
+
+	n := len(flags)
+	if n == 0 {
+		return false, nil, nil
+	}
+
+	f := flags[n-1]
+
+	v, ok := f.Value.TryGet()
+	if !ok || f.IsSpeculative {
+		return false, nil, fmt.Errorf("%s: unable to determine whether the handler is disabled", h)
+	}
+
+	if !v {
+		return false, nil, nil
+	}
+
+	var options []string
+	for _, opt := range f.Options {
+		options = append(options, fmt.Sprint(opt))
+	}
+
+	return true, options, nil
+}
+
+// fromExtensions returns the extensions attached to e, packed into
+// [anypb.Any] values.
+func fromExtensions(e config.Entity) ([]*anypb.Any, error) {
+	var extensions []*anypb.Any
+
+	for _, x := range e.EntityProperties().Extensions {
+		if x, ok := x.(*anypb.Any); ok {
+			extensions = append(extensions, x)
+			continue
+		}
+
+		v, err := anypb.New(x)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%s: unable to marshal %s extension: %w",
+				e,
+				x.ProtoReflect().Descriptor().FullName(),
+				err,
+			)
+		}
+
+		extensions = append(extensions, v)
+	}
+
+	return extensions, nil
+}
+
+// toExtensions returns the extensions represented by x.
+//
+// Extensions of a type that is not present in the global Protocol Buffers
+// registry, or that cannot be unmarshaled, are retained as [anypb.Any] values.
+func toExtensions(x []*anypb.Any) []proto.Message {
+	var extensions []proto.Message
+
+	for _, v := range x {
+		if m, err := v.UnmarshalNew(); err == nil {
+			extensions = append(extensions, m)
+		} else {
+			extensions = append(extensions, v)
+		}
+	}
+
+	return extensions
+}
+
+func fromHandlerType(t config.HandlerType) HandlerType {
+	return config.MapByHandlerType(
+		t,
+		HandlerType_AGGREGATE,
+		HandlerType_PROCESS,
+		HandlerType_INTEGRATION,
+		HandlerType_PROJECTION,
+	)
+}
+
+func fromMessageKind(k message.Kind) MessageKind {
+	return message.MapByKind(
+		k,
+		MessageKind_COMMAND,
+		MessageKind_EVENT,
+		MessageKind_DEADLINE,
+	)
+}
+
+func toEntity(p *config.EntityCommon, id *identitypb.Identity, goType string) error {
+	if goType != "" {
+		p.TypeName = optional.Some(goType)
+	}
+
+	if id != nil {
+		if !id.HasKey() {
+			return fmt.Errorf("identity %q has no key", id.GetName())
+		}
+
+		p.IdentityComponents = []*config.Identity{
+			{
+				Name: optional.Some(id.GetName()),
+				Key:  optional.Some(id.GetKey().AsString()),
+			},
+		}
+	}
+
+	return nil
+}
+
+func toHandler(
+	x *Handler,
+	kinds map[string]MessageKind,
+	registry map[string]dogma.RegisteredMessageType,
+) (config.Handler, error) {
+	var common config.HandlerCommon
+
+	if err := toEntity(&common.EntityCommon, x.GetIdentity(), x.GetGoType()); err != nil {
+		return nil, fmt.Errorf("invalid handler: %w", err)
+	}
+
+	messages := x.GetMessages()
+
+	for _, name := range slices.Sorted(maps.Keys(messages)) {
+		routeTypes, err := toRouteTypes(kinds[name], messages[name])
+		if err != nil {
+			return nil, fmt.Errorf("invalid handler (%s): %s: %w", x.GetIdentity(), name, err)
+		}
+
+		for _, rt := range routeTypes {
+			common.RouteComponents = append(
+				common.RouteComponents,
+				toRoute(rt, name, registry),
+			)
+		}
+	}
+
+	if x.GetIsDisabled() {
+		var options []any
+		for _, opt := range x.GetDisableOptions() {
+			options = append(options, opt)
+		}
+
+		common.DisabledFlags = []*config.Flag[config.Disabled]{
+			{
+				Value:   optional.Some(true),
+				Options: options,
+			},
+		}
+	}
+
+	common.Extensions = toExtensions(x.GetExtensions())
+
+	switch x.GetType() {
+	case HandlerType_AGGREGATE:
+		return &config.Aggregate{HandlerCommon: common}, nil
+	case HandlerType_PROCESS:
+		return &config.Process{HandlerCommon: common}, nil
+	case HandlerType_INTEGRATION:
+		return &config.Integration{HandlerCommon: common}, nil
+	case HandlerType_PROJECTION:
+		return &config.Projection{HandlerCommon: common}, nil
+	default:
+		return nil, fmt.Errorf("invalid handler (%s): unrecognized handler type (%s)", x.GetIdentity(), x.GetType())
+	}
+}
+
+// toRouteTypes returns the route types implied by a message of kind k being
+// used by a handler as described by u.
+func toRouteTypes(k MessageKind, u *MessageUsage) ([]config.RouteType, error) {
+	var types []config.RouteType
+
+	switch k {
+	case MessageKind_COMMAND:
+		if u.GetIsConsumed() {
+			types = append(types, config.HandlesCommandRouteType)
+		}
+		if u.GetIsProduced() {
+			types = append(types, config.ExecutesCommandRouteType)
+		}
+	case MessageKind_EVENT:
+		if u.GetIsConsumed() {
+			types = append(types, config.HandlesEventRouteType)
+		}
+		if u.GetIsProduced() {
+			types = append(types, config.RecordsEventRouteType)
+		}
+	case MessageKind_DEADLINE:
+		if u.GetIsConsumed() || u.GetIsProduced() {
+			types = append(types, config.SchedulesDeadlineRouteType)
+		}
+	default:
+		return nil, fmt.Errorf("unrecognized message kind (%s)", k)
+	}
+
+	if len(types) == 0 {
+		return nil, errors.New("message is neither produced nor consumed")
+	}
+
+	return types, nil
+}
+
+func toRoute(
+	rt config.RouteType,
+	name string,
+	registry map[string]dogma.RegisteredMessageType,
+) *config.Route {
+	r := &config.Route{
+		RouteType:       optional.Some(rt),
+		MessageTypeName: optional.Some(name),
+	}
+
+	if t, ok := registry[name]; ok {
+		r.MessageTypeID = optional.Some(t.ID())
+		r.MessageType = optional.Some(message.TypeFromReflect(t.GoType()))
+	}
+
+	return r
+}
+
+// registeredMessageTypesByName returns the message types in Dogma's message
+// type registry, keyed by their fully-qualified Go type name.
+func registeredMessageTypesByName() map[string]dogma.RegisteredMessageType {
+	types := map[string]dogma.RegisteredMessageType{}
+
+	for t := range dogma.RegisteredMessageTypes() {
+		types[typename.Get(t.GoType())] = t
+	}
+
+	return types
+}
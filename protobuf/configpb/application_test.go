@@ -0,0 +1,417 @@
+package configpb_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/config"
+	"github.com/dogmatiq/enginekit/config/runtimeconfig"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/enginekit/optional"
+	. "github.com/dogmatiq/enginekit/protobuf/configpb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestApplication(t *testing.T) {
+	app := runtimeconfig.FromApplication(&ApplicationStub{
+		ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+			c.Identity("app", "bed53df8-bf22-4502-be4b-64d56532d8be")
+			c.Routes(
+				dogma.ViaAggregate(&AggregateMessageHandlerStub[*AggregateRootStub]{
+					ConfigureFunc: func(c dogma.AggregateConfigurer) {
+						c.Identity("aggregate", "d9d75a75-7839-4b3e-a7e5-c8884b88ea57")
+						c.Routes(
+							dogma.HandlesCommand[*CommandStub[TypeA]](),
+							dogma.RecordsEvent[*EventStub[TypeA]](),
+						)
+					},
+				}),
+				dogma.ViaProcess(&ProcessMessageHandlerStub[*ProcessRootStub]{
+					ConfigureFunc: func(c dogma.ProcessConfigurer) {
+						c.Identity("process", "4ff1b1c1-5c64-4d6a-9a5f-3a9b4e0a4a3b")
+						c.Routes(
+							dogma.HandlesEvent[*EventStub[TypeA]](),
+							dogma.ExecutesCommand[*CommandStub[TypeB]](),
+							dogma.SchedulesDeadline[*DeadlineStub[TypeA]](),
+						)
+					},
+				}),
+				dogma.ViaIntegration(&IntegrationMessageHandlerStub{
+					ConfigureFunc: func(c dogma.IntegrationConfigurer) {
+						c.Identity("integration", "ff2d6a52-5a55-4e40-9b6c-1f8a7e0b3c2d")
+						c.Routes(
+							dogma.HandlesCommand[*CommandStub[TypeB]](),
+						)
+						c.Disable(disableOptionStub{Reason: "maintenance"})
+					},
+				}),
+				dogma.ViaProjection(&ProjectionMessageHandlerStub{
+					ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+						c.Identity("projection", "9c7a8bc6-9b1e-4f4c-8c44-7f3e6e0d9a51")
+						c.Routes(
+							dogma.HandlesEvent[*EventStub[TypeA]](),
+						)
+					},
+				}),
+			)
+		},
+	})
+
+	config.SetExtension(app.HandlerComponents[2], wrapperspb.String("<partition>"))
+
+	var (
+		commandA  = string(message.NameFor[*CommandStub[TypeA]]())
+		commandB  = string(message.NameFor[*CommandStub[TypeB]]())
+		eventA    = string(message.NameFor[*EventStub[TypeA]]())
+		deadlineA = string(message.NameFor[*DeadlineStub[TypeA]]())
+	)
+
+	want := NewApplicationBuilder().
+		WithIdentity(identitypb.MustParse("app", "bed53df8-bf22-4502-be4b-64d56532d8be")).
+		WithGoType("*github.com/dogmatiq/enginekit/enginetest/stubs.ApplicationStub").
+		WithHandlers([]*Handler{
+			NewHandlerBuilder().
+				WithIdentity(identitypb.MustParse("aggregate", "d9d75a75-7839-4b3e-a7e5-c8884b88ea57")).
+				WithGoType("*github.com/dogmatiq/enginekit/enginetest/stubs.AggregateMessageHandlerStub[*github.com/dogmatiq/enginekit/enginetest/stubs.AggregateRootStub]").
+				WithType(HandlerType_AGGREGATE).
+				WithMessages(map[string]*MessageUsage{
+					commandA: NewMessageUsageBuilder().WithIsConsumed(true).Build(),
+					eventA:   NewMessageUsageBuilder().WithIsProduced(true).Build(),
+				}).
+				Build(),
+			NewHandlerBuilder().
+				WithIdentity(identitypb.MustParse("process", "4ff1b1c1-5c64-4d6a-9a5f-3a9b4e0a4a3b")).
+				WithGoType("*github.com/dogmatiq/enginekit/enginetest/stubs.ProcessMessageHandlerStub[*github.com/dogmatiq/enginekit/enginetest/stubs.ProcessRootStub]").
+				WithType(HandlerType_PROCESS).
+				WithMessages(map[string]*MessageUsage{
+					eventA:    NewMessageUsageBuilder().WithIsConsumed(true).Build(),
+					commandB:  NewMessageUsageBuilder().WithIsProduced(true).Build(),
+					deadlineA: NewMessageUsageBuilder().WithIsConsumed(true).WithIsProduced(true).Build(),
+				}).
+				Build(),
+			NewHandlerBuilder().
+				WithIdentity(identitypb.MustParse("integration", "ff2d6a52-5a55-4e40-9b6c-1f8a7e0b3c2d")).
+				WithGoType("*github.com/dogmatiq/enginekit/enginetest/stubs.IntegrationMessageHandlerStub").
+				WithType(HandlerType_INTEGRATION).
+				WithMessages(map[string]*MessageUsage{
+					commandB: NewMessageUsageBuilder().WithIsConsumed(true).Build(),
+				}).
+				WithIsDisabled(true).
+				WithExtensions([]*anypb.Any{
+					mustMarshalAny(wrapperspb.String("<partition>")),
+				}).
+				WithDisableOptions([]string{"maintenance"}).
+				Build(),
+			NewHandlerBuilder().
+				WithIdentity(identitypb.MustParse("projection", "9c7a8bc6-9b1e-4f4c-8c44-7f3e6e0d9a51")).
+				WithGoType("*github.com/dogmatiq/enginekit/enginetest/stubs.ProjectionMessageHandlerStub").
+				WithType(HandlerType_PROJECTION).
+				WithMessages(map[string]*MessageUsage{
+					eventA: NewMessageUsageBuilder().WithIsConsumed(true).Build(),
+				}).
+				Build(),
+		}).
+		WithMessages(map[string]MessageKind{
+			commandA:  MessageKind_COMMAND,
+			commandB:  MessageKind_COMMAND,
+			eventA:    MessageKind_EVENT,
+			deadlineA: MessageKind_DEADLINE,
+		}).
+		Build()
+
+	t.Run("func FromApplication()", func(t *testing.T) {
+		t.Run("it returns the protocol buffers representation of the application", func(t *testing.T) {
+			got, err := FromApplication(app)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(
+				t,
+				"unexpected application",
+				got,
+				want,
+			)
+		})
+
+		t.Run("it retains extensions of unrecognized types", func(t *testing.T) {
+			a := &anypb.Any{TypeUrl: "type.googleapis.com/pkg.ExtensionA", Value: []byte("<a>")}
+			b := &anypb.Any{TypeUrl: "type.googleapis.com/pkg.ExtensionB", Value: []byte("<b>")}
+
+			x := NewApplicationBuilder().
+				From(want).
+				WithExtensions([]*anypb.Any{a, b}).
+				Build()
+
+			app, err := ToApplication(x)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(
+				t,
+				"unexpected extensions",
+				app.Extensions,
+				[]proto.Message{a, b},
+			)
+
+			replacement := &anypb.Any{TypeUrl: b.GetTypeUrl(), Value: []byte("<replacement>")}
+			config.SetExtension(app, replacement)
+
+			got, err := FromApplication(app)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(
+				t,
+				"unexpected extensions",
+				got.GetExtensions(),
+				[]*anypb.Any{a, replacement},
+			)
+		})
+
+		t.Run("it returns an error if the configuration is invalid", func(t *testing.T) {
+			_, err := FromApplication(&config.Application{})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+
+		t.Run("it returns an error if the disabled flag is ambiguous", func(t *testing.T) {
+			app := runtimeconfig.FromApplication(&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("app", "bed53df8-bf22-4502-be4b-64d56532d8be")
+					c.Routes(
+						dogma.ViaProjection(&ProjectionMessageHandlerStub{
+							ConfigureFunc: func(c dogma.ProjectionConfigurer) {
+								c.Identity("projection", "9c7a8bc6-9b1e-4f4c-8c44-7f3e6e0d9a51")
+								c.Routes(
+									dogma.HandlesEvent[*EventStub[TypeA]](),
+								)
+								c.Disable()
+							},
+						}),
+					)
+				},
+			})
+
+			app.HandlerComponents[0].HandlerProperties().DisabledFlags[0].IsSpeculative = true
+
+			_, err := FromApplication(app)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+		t.Run("it returns an error if an extension cannot be marshaled", func(t *testing.T) {
+			app := runtimeconfig.FromApplication(&ApplicationStub{
+				ConfigureFunc: func(c dogma.ApplicationConfigurer) {
+					c.Identity("app", "bed53df8-bf22-4502-be4b-64d56532d8be")
+				},
+			})
+
+			config.SetExtension(app, wrapperspb.String("\xff"))
+
+			_, err := FromApplication(app)
+			if err == nil || !strings.Contains(err.Error(), "unable to marshal google.protobuf.StringValue extension") {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	})
+
+	t.Run("func ToApplication()", func(t *testing.T) {
+		t.Run("it returns a valid configuration", func(t *testing.T) {
+			got, err := ToApplication(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := config.Validate(got); err != nil {
+				t.Fatal(err)
+			}
+
+			if got.Source.IsPresent() {
+				t.Fatal("did not expect the source to be present")
+			}
+
+			Expect(
+				t,
+				"unexpected type name",
+				got.TypeName,
+				app.TypeName,
+			)
+
+			Expect(
+				t,
+				"unexpected identity",
+				got.Identity(),
+				app.Identity(),
+			)
+
+			Expect(
+				t,
+				"unexpected handler",
+				got.HandlerComponents[2],
+				config.Handler(&config.Integration{
+					HandlerCommon: config.HandlerCommon{
+						EntityCommon: config.EntityCommon{
+							TypeName: optional.Some("*github.com/dogmatiq/enginekit/enginetest/stubs.IntegrationMessageHandlerStub"),
+							IdentityComponents: []*config.Identity{
+								{
+									Name: optional.Some("integration"),
+									Key:  optional.Some("ff2d6a52-5a55-4e40-9b6c-1f8a7e0b3c2d"),
+								},
+							},
+							Extensions: []proto.Message{
+								wrapperspb.String("<partition>"),
+							},
+						},
+						RouteComponents: []*config.Route{
+							{
+								RouteType:       optional.Some(config.HandlesCommandRouteType),
+								MessageTypeID:   optional.Some(MessageTypeID[*CommandStub[TypeB]]()),
+								MessageTypeName: optional.Some(commandB),
+								MessageType:     optional.Some(message.TypeFor[*CommandStub[TypeB]]()),
+							},
+						},
+						DisabledFlags: []*config.Flag[config.Disabled]{
+							{
+								Value:   optional.Some(true),
+								Options: []any{"maintenance"},
+							},
+						},
+					},
+				}),
+			)
+		})
+
+		t.Run("it round-trips via FromApplication()", func(t *testing.T) {
+			app, err := ToApplication(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := FromApplication(app)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(
+				t,
+				"unexpected application",
+				got,
+				want,
+			)
+		})
+
+		t.Run("it omits the message type of routes for unrecognized message types", func(t *testing.T) {
+			const name = "github.com/dogmatiq/enginekit/protobuf/configpb_test.Unregistered"
+
+			x := NewApplicationBuilder().
+				From(want).
+				WithHandlers([]*Handler{
+					NewHandlerBuilder().
+						WithIdentity(identitypb.MustParse("projection", "9c7a8bc6-9b1e-4f4c-8c44-7f3e6e0d9a51")).
+						WithGoType("*github.com/dogmatiq/enginekit/enginetest/stubs.ProjectionMessageHandlerStub").
+						WithType(HandlerType_PROJECTION).
+						WithMessages(map[string]*MessageUsage{
+							name: NewMessageUsageBuilder().WithIsConsumed(true).Build(),
+						}).
+						Build(),
+				}).
+				WithMessages(map[string]MessageKind{
+					name: MessageKind_EVENT,
+				}).
+				Build()
+
+			app, err := ToApplication(x)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(
+				t,
+				"unexpected route",
+				app.HandlerComponents[0].HandlerProperties().RouteComponents,
+				[]*config.Route{
+					{
+						RouteType:       optional.Some(config.HandlesEventRouteType),
+						MessageTypeName: optional.Some(name),
+					},
+				},
+			)
+
+			if err := config.Validate(app); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := config.Validate(app, config.ForExecution()); err == nil {
+				t.Fatal("expected the configuration to be unsuitable for execution")
+			}
+
+			got, err := FromApplication(app)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(
+				t,
+				"unexpected application",
+				got,
+				x,
+			)
+		})
+
+		t.Run("it returns an error if a message kind is unknown", func(t *testing.T) {
+			x := NewApplicationBuilder().
+				From(want).
+				WithMessages(nil).
+				Build()
+
+			if _, err := ToApplication(x); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+
+		t.Run("it returns an error if a handler type is unknown", func(t *testing.T) {
+			x := NewApplicationBuilder().
+				From(want).
+				WithHandlers([]*Handler{
+					NewHandlerBuilder().
+						From(want.GetHandlers()[0]).
+						WithType(HandlerType_UNKNOWN_HANDLER_TYPE).
+						Build(),
+				}).
+				Build()
+
+			if _, err := ToApplication(x); err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	})
+}
+
+func mustMarshalAny(m proto.Message) *anypb.Any {
+	x, err := anypb.New(m)
+	if err != nil {
+		panic(err)
+	}
+	return x
+}
+
+// disableOptionStub is a [dogma.DisableOption] that describes why a handler was
+// disabled.
+type disableOptionStub struct {
+	dogma.DisableOption
+	Reason string
+}
+
+func (o disableOptionStub) String() string {
+	return o.Reason
+}
@@ -10,6 +10,7 @@ import (
 	identitypb "github.com/dogmatiq/enginekit/protobuf/identitypb"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	anypb "google.golang.org/protobuf/types/known/anypb"
 	reflect "reflect"
 	unsafe "unsafe"
 )
@@ -125,13 +126,14 @@ func (x HandlerType) Number() protoreflect.EnumNumber {
 // Application represents a Dogma application hosted by the engine on the
 // server.
 type Application struct {
-	state               protoimpl.MessageState `protogen:"opaque.v1"`
-	xxx_hidden_Identity *identitypb.Identity   `protobuf:"bytes,1,opt,name=identity"`
-	xxx_hidden_GoType   string                 `protobuf:"bytes,2,opt,name=go_type,json=goType"`
-	xxx_hidden_Handlers *[]*Handler            `protobuf:"bytes,3,rep,name=handlers"`
-	xxx_hidden_Messages map[string]MessageKind `protobuf:"bytes,4,rep,name=messages" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value,enum=dogma.protobuf.MessageKind"`
-	unknownFields       protoimpl.UnknownFields
-	sizeCache           protoimpl.SizeCache
+	state                 protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Identity   *identitypb.Identity   `protobuf:"bytes,1,opt,name=identity"`
+	xxx_hidden_GoType     string                 `protobuf:"bytes,2,opt,name=go_type,json=goType"`
+	xxx_hidden_Handlers   *[]*Handler            `protobuf:"bytes,3,rep,name=handlers"`
+	xxx_hidden_Messages   map[string]MessageKind `protobuf:"bytes,4,rep,name=messages" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value,enum=dogma.protobuf.MessageKind"`
+	xxx_hidden_Extensions *[]*anypb.Any          `protobuf:"bytes,5,rep,name=extensions"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
 }
 
 func (x *Application) Reset() {
@@ -189,6 +191,15 @@ func (x *Application) GetMessages() map[string]MessageKind {
 	return nil
 }
 
+func (x *Application) GetExtensions() []*anypb.Any {
+	if x != nil {
+		if x.xxx_hidden_Extensions != nil {
+			return *x.xxx_hidden_Extensions
+		}
+	}
+	return nil
+}
+
 func (x *Application) SetIdentity(v *identitypb.Identity) {
 	x.xxx_hidden_Identity = v
 }
@@ -205,6 +216,10 @@ func (x *Application) SetMessages(v map[string]MessageKind) {
 	x.xxx_hidden_Messages = v
 }
 
+func (x *Application) SetExtensions(v []*anypb.Any) {
+	x.xxx_hidden_Extensions = &v
+}
+
 func (x *Application) HasIdentity() bool {
 	if x == nil {
 		return false
@@ -229,6 +244,9 @@ type Application_builder struct {
 	// MessageKinds is a map of each message type's fully-qualified Go type to its
 	// the kind of message it implemented by that type.
 	Messages map[string]MessageKind
+	// Extensions is a set of engine-specific extensions attached to the
+	// application.
+	Extensions []*anypb.Any
 }
 
 func (b0 Application_builder) Build() *Application {
@@ -239,19 +257,22 @@ func (b0 Application_builder) Build() *Application {
 	x.xxx_hidden_GoType = b.GoType
 	x.xxx_hidden_Handlers = &b.Handlers
 	x.xxx_hidden_Messages = b.Messages
+	x.xxx_hidden_Extensions = &b.Extensions
 	return m0
 }
 
 // Handler is a message handler within an application.
 type Handler struct {
-	state                 protoimpl.MessageState   `protogen:"opaque.v1"`
-	xxx_hidden_Identity   *identitypb.Identity     `protobuf:"bytes,1,opt,name=identity"`
-	xxx_hidden_GoType     string                   `protobuf:"bytes,2,opt,name=go_type,json=goType"`
-	xxx_hidden_Type       HandlerType              `protobuf:"varint,3,opt,name=type,enum=dogma.protobuf.HandlerType"`
-	xxx_hidden_Messages   map[string]*MessageUsage `protobuf:"bytes,4,rep,name=messages" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
-	xxx_hidden_IsDisabled bool                     `protobuf:"varint,5,opt,name=is_disabled,json=isDisabled"`
-	unknownFields         protoimpl.UnknownFields
-	sizeCache             protoimpl.SizeCache
+	state                     protoimpl.MessageState   `protogen:"opaque.v1"`
+	xxx_hidden_Identity       *identitypb.Identity     `protobuf:"bytes,1,opt,name=identity"`
+	xxx_hidden_GoType         string                   `protobuf:"bytes,2,opt,name=go_type,json=goType"`
+	xxx_hidden_Type           HandlerType              `protobuf:"varint,3,opt,name=type,enum=dogma.protobuf.HandlerType"`
+	xxx_hidden_Messages       map[string]*MessageUsage `protobuf:"bytes,4,rep,name=messages" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	xxx_hidden_IsDisabled     bool                     `protobuf:"varint,5,opt,name=is_disabled,json=isDisabled"`
+	xxx_hidden_Extensions     *[]*anypb.Any            `protobuf:"bytes,6,rep,name=extensions"`
+	xxx_hidden_DisableOptions []string                 `protobuf:"bytes,7,rep,name=disable_options,json=disableOptions"`
+	unknownFields             protoimpl.UnknownFields
+	sizeCache                 protoimpl.SizeCache
 }
 
 func (x *Handler) Reset() {
@@ -314,6 +335,22 @@ func (x *Handler) GetIsDisabled() bool {
 	return false
 }
 
+func (x *Handler) GetExtensions() []*anypb.Any {
+	if x != nil {
+		if x.xxx_hidden_Extensions != nil {
+			return *x.xxx_hidden_Extensions
+		}
+	}
+	return nil
+}
+
+func (x *Handler) GetDisableOptions() []string {
+	if x != nil {
+		return x.xxx_hidden_DisableOptions
+	}
+	return nil
+}
+
 func (x *Handler) SetIdentity(v *identitypb.Identity) {
 	x.xxx_hidden_Identity = v
 }
@@ -334,6 +371,14 @@ func (x *Handler) SetIsDisabled(v bool) {
 	x.xxx_hidden_IsDisabled = v
 }
 
+func (x *Handler) SetExtensions(v []*anypb.Any) {
+	x.xxx_hidden_Extensions = &v
+}
+
+func (x *Handler) SetDisableOptions(v []string) {
+	x.xxx_hidden_DisableOptions = v
+}
+
 func (x *Handler) HasIdentity() bool {
 	if x == nil {
 		return false
@@ -361,6 +406,12 @@ type Handler_builder struct {
 	Messages map[string]*MessageUsage
 	// IsDisabled indicates whether the handler is disabled.
 	IsDisabled bool
+	// Extensions is a set of engine-specific extensions attached to the handler.
+	Extensions []*anypb.Any
+	// DisableOptions is the string representation of each option that was
+	// supplied when the handler was disabled. It is empty if the handler is not
+	// disabled, or if it was disabled without any options.
+	DisableOptions []string
 }
 
 func (b0 Handler_builder) Build() *Handler {
@@ -372,6 +423,8 @@ func (b0 Handler_builder) Build() *Handler {
 	x.xxx_hidden_Type = b.Type
 	x.xxx_hidden_Messages = b.Messages
 	x.xxx_hidden_IsDisabled = b.IsDisabled
+	x.xxx_hidden_Extensions = &b.Extensions
+	x.xxx_hidden_DisableOptions = b.DisableOptions
 	return m0
 }
 
@@ -452,22 +505,29 @@ var File_github_com_dogmatiq_enginekit_protobuf_configpb_config_proto protorefle
 
 const file_github_com_dogmatiq_enginekit_protobuf_configpb_config_proto_rawDesc = "" +
 	"\n" +
-	"<github.com/dogmatiq/enginekit/protobuf/configpb/config.proto\x12\x0edogma.protobuf\x1a@github.com/dogmatiq/enginekit/protobuf/identitypb/identity.proto\"\xb9\x02\n" +
+	"<github.com/dogmatiq/enginekit/protobuf/configpb/config.proto\x12\x0edogma.protobuf\x1a\x19google/protobuf/any.proto\x1a@github.com/dogmatiq/enginekit/protobuf/identitypb/identity.proto\"\xef\x02\n" +
 	"\vApplication\x124\n" +
 	"\bidentity\x18\x01 \x01(\v2\x18.dogma.protobuf.IdentityR\bidentity\x12\x1e\n" +
 	"\ago_type\x18\x02 \x01(\tB\x05\xaa\x01\x02\b\x02R\x06goType\x123\n" +
 	"\bhandlers\x18\x03 \x03(\v2\x17.dogma.protobuf.HandlerR\bhandlers\x12E\n" +
-	"\bmessages\x18\x04 \x03(\v2).dogma.protobuf.Application.MessagesEntryR\bmessages\x1aX\n" +
+	"\bmessages\x18\x04 \x03(\v2).dogma.protobuf.Application.MessagesEntryR\bmessages\x124\n" +
+	"\n" +
+	"extensions\x18\x05 \x03(\v2\x14.google.protobuf.AnyR\n" +
+	"extensions\x1aX\n" +
 	"\rMessagesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x121\n" +
-	"\x05value\x18\x02 \x01(\x0e2\x1b.dogma.protobuf.MessageKindR\x05value:\x028\x01\"\xdd\x02\n" +
+	"\x05value\x18\x02 \x01(\x0e2\x1b.dogma.protobuf.MessageKindR\x05value:\x028\x01\"\xbc\x03\n" +
 	"\aHandler\x124\n" +
 	"\bidentity\x18\x01 \x01(\v2\x18.dogma.protobuf.IdentityR\bidentity\x12\x1e\n" +
 	"\ago_type\x18\x02 \x01(\tB\x05\xaa\x01\x02\b\x02R\x06goType\x126\n" +
 	"\x04type\x18\x03 \x01(\x0e2\x1b.dogma.protobuf.HandlerTypeB\x05\xaa\x01\x02\b\x02R\x04type\x12A\n" +
 	"\bmessages\x18\x04 \x03(\v2%.dogma.protobuf.Handler.MessagesEntryR\bmessages\x12&\n" +
 	"\vis_disabled\x18\x05 \x01(\bB\x05\xaa\x01\x02\b\x02R\n" +
-	"isDisabled\x1aY\n" +
+	"isDisabled\x124\n" +
+	"\n" +
+	"extensions\x18\x06 \x03(\v2\x14.google.protobuf.AnyR\n" +
+	"extensions\x12'\n" +
+	"\x0fdisable_options\x18\a \x03(\tR\x0edisableOptions\x1aY\n" +
 	"\rMessagesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x122\n" +
 	"\x05value\x18\x02 \x01(\v2\x1c.dogma.protobuf.MessageUsageR\x05value:\x028\x01\"^\n" +
@@ -500,21 +560,24 @@ var file_github_com_dogmatiq_enginekit_protobuf_configpb_config_proto_goTypes =
 	nil,                         // 5: dogma.protobuf.Application.MessagesEntry
 	nil,                         // 6: dogma.protobuf.Handler.MessagesEntry
 	(*identitypb.Identity)(nil), // 7: dogma.protobuf.Identity
+	(*anypb.Any)(nil),           // 8: google.protobuf.Any
 }
 var file_github_com_dogmatiq_enginekit_protobuf_configpb_config_proto_depIdxs = []int32{
-	7, // 0: dogma.protobuf.Application.identity:type_name -> dogma.protobuf.Identity
-	3, // 1: dogma.protobuf.Application.handlers:type_name -> dogma.protobuf.Handler
-	5, // 2: dogma.protobuf.Application.messages:type_name -> dogma.protobuf.Application.MessagesEntry
-	7, // 3: dogma.protobuf.Handler.identity:type_name -> dogma.protobuf.Identity
-	1, // 4: dogma.protobuf.Handler.type:type_name -> dogma.protobuf.HandlerType
-	6, // 5: dogma.protobuf.Handler.messages:type_name -> dogma.protobuf.Handler.MessagesEntry
-	0, // 6: dogma.protobuf.Application.MessagesEntry.value:type_name -> dogma.protobuf.MessageKind
-	4, // 7: dogma.protobuf.Handler.MessagesEntry.value:type_name -> dogma.protobuf.MessageUsage
-	8, // [8:8] is the sub-list for method output_type
-	8, // [8:8] is the sub-list for method input_type
-	8, // [8:8] is the sub-list for extension type_name
-	8, // [8:8] is the sub-list for extension extendee
-	0, // [0:8] is the sub-list for field type_name
+	7,  // 0: dogma.protobuf.Application.identity:type_name -> dogma.protobuf.Identity
+	3,  // 1: dogma.protobuf.Application.handlers:type_name -> dogma.protobuf.Handler
+	5,  // 2: dogma.protobuf.Application.messages:type_name -> dogma.protobuf.Application.MessagesEntry
+	8,  // 3: dogma.protobuf.Application.extensions:type_name -> google.protobuf.Any
+	7,  // 4: dogma.protobuf.Handler.identity:type_name -> dogma.protobuf.Identity
+	1,  // 5: dogma.protobuf.Handler.type:type_name -> dogma.protobuf.HandlerType
+	6,  // 6: dogma.protobuf.Handler.messages:type_name -> dogma.protobuf.Handler.MessagesEntry
+	8,  // 7: dogma.protobuf.Handler.extensions:type_name -> google.protobuf.Any
+	0,  // 8: dogma.protobuf.Application.MessagesEntry.value:type_name -> dogma.protobuf.MessageKind
+	4,  // 9: dogma.protobuf.Handler.MessagesEntry.value:type_name -> dogma.protobuf.MessageUsage
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
 }
 
 func init() { file_github_com_dogmatiq_enginekit_protobuf_configpb_config_proto_init() }
@@ -10,6 +10,7 @@ import (
 	"fmt"
 	identitypb "github.com/dogmatiq/enginekit/protobuf/identitypb"
 	proto "google.golang.org/protobuf/proto"
+	anypb "google.golang.org/protobuf/types/known/anypb"
 )
 
 type ApplicationBuilder struct {
@@ -34,6 +35,7 @@ func (b *ApplicationBuilder) From(x *Application) *ApplicationBuilder {
 	b.prototype.SetGoType(x.GetGoType())
 	b.prototype.SetHandlers(x.GetHandlers())
 	b.prototype.SetMessages(x.GetMessages())
+	b.prototype.SetExtensions(x.GetExtensions())
 	return b
 }
 
@@ -49,6 +51,7 @@ func (b *ApplicationBuilder) Build() *Application {
 	m.SetGoType(b.prototype.GetGoType())
 	m.SetHandlers(b.prototype.GetHandlers())
 	m.SetMessages(b.prototype.GetMessages())
+	m.SetExtensions(b.prototype.GetExtensions())
 	return m
 }
 
@@ -80,6 +83,13 @@ func (b *ApplicationBuilder) WithMessages(v map[string]MessageKind) *Application
 	return b
 }
 
+// WithExtensions configures the builder to set the Extensions field to v,
+// then returns b.
+func (b *ApplicationBuilder) WithExtensions(v []*anypb.Any) *ApplicationBuilder {
+	b.prototype.SetExtensions(v)
+	return b
+}
+
 type HandlerBuilder struct {
 	prototype Handler
 }
@@ -103,6 +113,8 @@ func (b *HandlerBuilder) From(x *Handler) *HandlerBuilder {
 	b.prototype.SetType(x.GetType())
 	b.prototype.SetMessages(x.GetMessages())
 	b.prototype.SetIsDisabled(x.GetIsDisabled())
+	b.prototype.SetExtensions(x.GetExtensions())
+	b.prototype.SetDisableOptions(x.GetDisableOptions())
 	return b
 }
 
@@ -119,6 +131,8 @@ func (b *HandlerBuilder) Build() *Handler {
 	m.SetType(b.prototype.GetType())
 	m.SetMessages(b.prototype.GetMessages())
 	m.SetIsDisabled(b.prototype.GetIsDisabled())
+	m.SetExtensions(b.prototype.GetExtensions())
+	m.SetDisableOptions(b.prototype.GetDisableOptions())
 	return m
 }
 
@@ -157,6 +171,20 @@ func (b *HandlerBuilder) WithIsDisabled(v bool) *HandlerBuilder {
 	return b
 }
 
+// WithExtensions configures the builder to set the Extensions field to v,
+// then returns b.
+func (b *HandlerBuilder) WithExtensions(v []*anypb.Any) *HandlerBuilder {
+	b.prototype.SetExtensions(v)
+	return b
+}
+
+// WithDisableOptions configures the builder to set the DisableOptions field to v,
+// then returns b.
+func (b *HandlerBuilder) WithDisableOptions(v []string) *HandlerBuilder {
+	b.prototype.SetDisableOptions(v)
+	return b
+}
+
 type MessageUsageBuilder struct {
 	prototype MessageUsage
 }
@@ -0,0 +1,3 @@
+// Package configpb provides Protocol Buffers representations of Dogma
+// application configurations.
+package configpb
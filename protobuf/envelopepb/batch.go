@@ -0,0 +1,198 @@
+package envelopepb
+
+import (
+	"fmt"
+
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// BatchLimits places bounds on the size of each [MultiEnvelope] produced by
+// [EffectPacker.SealBatches] and [Packer.PackEffectBatches].
+//
+// A batch always contains at least one message, even if that message alone
+// exceeds MaxBytes.
+type BatchLimits struct {
+	// MaxBodies is the maximum number of messages in each batch. If it is
+	// zero, the number of messages is unbounded.
+	MaxBodies int
+
+	// MaxBytes is the maximum size of each batch, in bytes, when encoded in
+	// the protocol buffers binary format. If it is zero, the size is
+	// unbounded.
+	MaxBytes int
+}
+
+// PackEffectBatches returns an [EffectPacker] that packs messages produced by
+// h while handling cause, and passes each batch of messages to emit as soon as
+// it is full.
+//
+// Each batch shares the same header. [EffectPacker.Seal] returns a
+// [MultiEnvelope] containing only those messages that have not already been
+// passed to emit.
+//
+// It panics if cause is not well-formed. Use [Packer.TryPackEffectBatches] to
+// handle such failures as errors.
+func (p *Packer) PackEffectBatches(
+	cause *Envelope,
+	h *identitypb.Identity,
+	limits BatchLimits,
+	emit func(*MultiEnvelope),
+	options ...PackEffectsOption,
+) *EffectPacker {
+	e, err := p.TryPackEffectBatches(cause, h, limits, emit, options...)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// TryPackEffectBatches returns an [EffectPacker] that packs messages produced
+// by h while handling cause, and passes each batch of messages to emit as soon
+// as it is full.
+//
+// It returns an [*InvalidEnvelopeError] if cause is not well-formed, or if the
+// options produce an invalid header. It panics if h, cause or emit is nil, or
+// if limits is invalid.
+func (p *Packer) TryPackEffectBatches(
+	cause *Envelope,
+	h *identitypb.Identity,
+	limits BatchLimits,
+	emit func(*MultiEnvelope),
+	options ...PackEffectsOption,
+) (*EffectPacker, error) {
+	if emit == nil {
+		panic("emit function must not be nil")
+	}
+
+	limits.mustBeValid()
+
+	e, err := p.TryPackEffects(cause, h, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	e.limits = limits
+	e.emit = emit
+
+	return e, nil
+}
+
+// SealBatches returns the packed messages split into [MultiEnvelope] values
+// that are each within the given limits, or nil if no messages were packed.
+//
+// Each batch shares the same header, and the messages retain their insertion
+// order across batches.
+func (p *EffectPacker) SealBatches(limits BatchLimits) []*MultiEnvelope {
+	limits.mustBeValid()
+
+	p.mustNotBeSealed()
+	p.sealed = true
+
+	var (
+		batches []*MultiEnvelope
+		batch   = p.newBatch()
+	)
+
+	for _, body := range p.bodies {
+		if !batch.accepts(body, limits) {
+			batches = append(batches, batch.build())
+			batch = p.newBatch()
+		}
+		batch.add(body)
+	}
+
+	if len(batch.bodies) != 0 {
+		batches = append(batches, batch.build())
+	}
+
+	return batches
+}
+
+// add adds body to the packed messages, passing each full batch to p.emit if
+// the packer is producing batches.
+func (p *EffectPacker) add(body *Body) {
+	if p.emit == nil {
+		p.bodies = append(p.bodies, body)
+		return
+	}
+
+	if p.batch == nil {
+		p.batch = p.newBatch()
+	}
+
+	if !p.batch.accepts(body, p.limits) {
+		p.emit(p.batch.build())
+		p.batch = p.newBatch()
+	}
+
+	p.batch.add(body)
+
+	if len(p.batch.bodies) == p.limits.MaxBodies {
+		p.emit(p.batch.build())
+		p.batch = p.newBatch()
+	}
+
+	p.bodies = p.batch.bodies
+}
+
+func (p *EffectPacker) newBatch() *batch {
+	return &batch{
+		header: p.header,
+		size:   proto.Size(NewMultiEnvelopeBuilder().WithHeader(p.header).Build()),
+	}
+}
+
+// batch is a [MultiEnvelope] under construction.
+type batch struct {
+	header *Header
+	bodies []*Body
+	size   int
+}
+
+// accepts returns true if body can be added to the batch without exceeding
+// the given limits.
+func (b *batch) accepts(body *Body, limits BatchLimits) bool {
+	if len(b.bodies) == 0 {
+		return true
+	}
+
+	if limits.MaxBodies != 0 && len(b.bodies) >= limits.MaxBodies {
+		return false
+	}
+
+	if limits.MaxBytes != 0 && b.size+bodySize(body) > limits.MaxBytes {
+		return false
+	}
+
+	return true
+}
+
+func (b *batch) add(body *Body) {
+	b.bodies = append(b.bodies, body)
+	b.size += bodySize(body)
+}
+
+func (b *batch) build() *MultiEnvelope {
+	return NewMultiEnvelopeBuilder().
+		WithHeader(b.header).
+		WithBodies(b.bodies).
+		Build()
+}
+
+// bodySize returns the number of bytes that body adds to the binary encoding
+// of a [MultiEnvelope].
+func bodySize(body *Body) int {
+	return protowire.SizeTag(2) + protowire.SizeBytes(proto.Size(body))
+}
+
+func (l BatchLimits) mustBeValid() {
+	if l.MaxBodies < 0 {
+		panic(fmt.Sprintf("maximum number of bodies must not be negative, got %d", l.MaxBodies))
+	}
+
+	if l.MaxBytes < 0 {
+		panic(fmt.Sprintf("maximum number of bytes must not be negative, got %d", l.MaxBytes))
+	}
+}
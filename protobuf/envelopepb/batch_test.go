@@ -0,0 +1,213 @@
+package envelopepb_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	. "github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestEffectPacker_batches(t *testing.T) {
+	packer := &Packer{
+		Application: identitypb.New("app", uuidpb.Generate()),
+	}
+
+	cause := packer.PackCommand(CommandA1)
+	handler := identitypb.New("handler", uuidpb.Generate())
+
+	events := []dogma.Event{EventA1, EventA2, EventA3, EventB1, EventB2}
+
+	unpackAll := func(t *testing.T, batches []*MultiEnvelope) []dogma.Event {
+		t.Helper()
+
+		var got []dogma.Event
+		for _, b := range batches {
+			Expect(t, "unexpected header", b.GetHeader(), batches[0].GetHeader())
+
+			for env := range b.All() {
+				m, err := Unpack[dogma.Event](env)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, m)
+			}
+		}
+		return got
+	}
+
+	t.Run("func SealBatches()", func(t *testing.T) {
+		t.Run("it limits the number of bodies in each batch", func(t *testing.T) {
+			effects := packer.PackEffects(cause, handler)
+			for _, e := range events {
+				effects.PackEvent(e)
+			}
+
+			batches := effects.SealBatches(BatchLimits{MaxBodies: 2})
+
+			Expect(t, "unexpected batch count", len(batches), 3)
+			Expect(t, "unexpected body count", len(batches[2].GetBodies()), 1)
+			Expect(t, "unexpected messages", unpackAll(t, batches), events)
+		})
+
+		t.Run("it limits the encoded size of each batch", func(t *testing.T) {
+			effects := packer.PackEffects(cause, handler)
+			for _, e := range events {
+				effects.PackEvent(e)
+			}
+
+			single := effects.PackEvent(EventC1)
+			limit := 3 * proto.Size(single)
+
+			batches := effects.SealBatches(BatchLimits{MaxBytes: limit})
+
+			if len(batches) < 2 {
+				t.Fatalf("expected multiple batches, got %d", len(batches))
+			}
+
+			for _, b := range batches {
+				if n := proto.Size(b); n > limit {
+					t.Fatalf("batch is %d bytes, expected at most %d", n, limit)
+				}
+			}
+
+			Expect(t, "unexpected messages", unpackAll(t, batches), append(events, EventC1))
+		})
+
+		t.Run("it includes a body that exceeds the size limit on its own", func(t *testing.T) {
+			effects := packer.PackEffects(cause, handler)
+			effects.PackEvent(EventA1)
+			effects.PackEvent(EventA2)
+
+			batches := effects.SealBatches(BatchLimits{MaxBytes: 1})
+
+			Expect(t, "unexpected batch count", len(batches), 2)
+		})
+
+		t.Run("it returns nil when empty", func(t *testing.T) {
+			effects := packer.PackEffects(cause, handler)
+
+			if batches := effects.SealBatches(BatchLimits{MaxBodies: 1}); batches != nil {
+				t.Fatalf("expected nil, got %v", batches)
+			}
+		})
+
+		t.Run("it panics if a limit is negative", func(t *testing.T) {
+			effects := packer.PackEffects(cause, handler)
+
+			ExpectPanic(
+				t,
+				"maximum number of bodies must not be negative, got -1",
+				func() {
+					effects.SealBatches(BatchLimits{MaxBodies: -1})
+				},
+			)
+		})
+	})
+
+	t.Run("func PackEffectBatches()", func(t *testing.T) {
+		t.Run("it emits batches as they fill", func(t *testing.T) {
+			var batches []*MultiEnvelope
+
+			effects := packer.PackEffectBatches(
+				cause,
+				handler,
+				BatchLimits{MaxBodies: 2},
+				func(b *MultiEnvelope) {
+					batches = append(batches, b)
+				},
+			)
+
+			effects.PackEvent(EventA1)
+			Expect(t, "unexpected batch count", len(batches), 0)
+
+			effects.PackEvent(EventA2)
+			Expect(t, "unexpected batch count", len(batches), 1)
+
+			effects.PackEvent(EventA3)
+			effects.PackEvent(EventB1)
+			effects.PackEvent(EventB2)
+			Expect(t, "unexpected batch count", len(batches), 2)
+
+			final, ok := effects.Seal()
+			if !ok {
+				t.Fatal("expected a final batch")
+			}
+
+			Expect(t, "unexpected messages", unpackAll(t, append(batches, final)), events)
+		})
+
+		t.Run("it emits batches when the size limit would be exceeded", func(t *testing.T) {
+			var batches []*MultiEnvelope
+
+			effects := packer.PackEffectBatches(
+				cause,
+				handler,
+				BatchLimits{MaxBytes: 1},
+				func(b *MultiEnvelope) {
+					batches = append(batches, b)
+				},
+			)
+
+			effects.PackEvent(EventA1)
+			effects.PackEvent(EventA2)
+			Expect(t, "unexpected batch count", len(batches), 1)
+
+			final, ok := effects.Seal()
+			if !ok {
+				t.Fatal("expected a final batch")
+			}
+
+			Expect(t, "unexpected messages", unpackAll(t, append(batches, final)), []dogma.Event{EventA1, EventA2})
+		})
+
+		t.Run("it returns no final envelope if all messages were emitted", func(t *testing.T) {
+			effects := packer.PackEffectBatches(
+				cause,
+				handler,
+				BatchLimits{MaxBodies: 1},
+				func(*MultiEnvelope) {},
+			)
+
+			effects.PackEvent(EventA1)
+
+			if _, ok := effects.Seal(); ok {
+				t.Fatal("did not expect a final batch")
+			}
+		})
+
+		t.Run("it panics if the emit function is nil", func(t *testing.T) {
+			ExpectPanic(
+				t,
+				"emit function must not be nil",
+				func() {
+					packer.PackEffectBatches(cause, handler, BatchLimits{}, nil)
+				},
+			)
+		})
+	})
+
+	t.Run("func TryPackEffectBatches()", func(t *testing.T) {
+		t.Run("it returns an error if the cause is not well-formed", func(t *testing.T) {
+			invalid := proto.CloneOf(cause)
+			invalid.GetBody().ClearMessageId()
+
+			_, err := packer.TryPackEffectBatches(
+				invalid,
+				handler,
+				BatchLimits{MaxBodies: 1},
+				func(*MultiEnvelope) {},
+			)
+
+			var target *InvalidEnvelopeError
+			if !errors.As(err, &target) {
+				t.Fatalf("unexpected error: got %v, want %T", err, target)
+			}
+		})
+	})
+}
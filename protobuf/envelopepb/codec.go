@@ -0,0 +1,345 @@
+package envelopepb
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec encodes and decodes [Envelope] and [MultiEnvelope] values using a
+// specific wire format.
+type Codec interface {
+	// ContentType returns the MIME media type of the encoded data.
+	ContentType() string
+
+	// Marshal returns the encoded representation of m.
+	Marshal(m proto.Message) ([]byte, error)
+
+	// Unmarshal decodes data into m.
+	//
+	// If m is an [Envelope] or [MultiEnvelope], it returns an error if the
+	// decoded value is not well-formed, as per its Validate() method.
+	Unmarshal(data []byte, m proto.Message) error
+}
+
+const (
+	// BinaryContentType is the MIME media type used by [BinaryCodec].
+	BinaryContentType = "application/vnd.google.protobuf"
+
+	// JSONContentType is the MIME media type used by [JSONCodec].
+	JSONContentType = "application/json"
+
+	// TextContentType is the MIME media type used by [TextCodec].
+	TextContentType = "text/plain"
+)
+
+// BinaryCodec is a [Codec] that uses the Protocol Buffers binary wire format.
+type BinaryCodec struct{}
+
+// ContentType returns [BinaryContentType].
+func (BinaryCodec) ContentType() string {
+	return BinaryContentType
+}
+
+// Marshal returns the binary representation of m.
+func (BinaryCodec) Marshal(m proto.Message) ([]byte, error) {
+	return proto.Marshal(m)
+}
+
+// Unmarshal decodes the binary representation in data into m.
+func (BinaryCodec) Unmarshal(data []byte, m proto.Message) error {
+	if err := proto.Unmarshal(data, m); err != nil {
+		return err
+	}
+	return validateDecoded(m)
+}
+
+// JSONCodec is a [Codec] that uses the Protocol Buffers JSON format.
+type JSONCodec struct {
+	// ExpandMessageData, if true, causes the message data within each
+	// envelope to be represented as a JSON value instead of a base64-encoded
+	// string, provided that the message data is itself compact JSON.
+	//
+	// The expanded value is stored in the "jsonData" property of the message,
+	// in place of the "data" property. Unmarshal accepts either form,
+	// regardless of this setting.
+	ExpandMessageData bool
+}
+
+// ContentType returns [JSONContentType].
+func (JSONCodec) ContentType() string {
+	return JSONContentType
+}
+
+// Marshal returns the JSON representation of m.
+func (c JSONCodec) Marshal(m proto.Message) ([]byte, error) {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.ExpandMessageData {
+		return data, nil
+	}
+
+	return transformMessageJSON(data, m, expandMessageData)
+}
+
+// Unmarshal decodes the JSON representation in data into m.
+func (JSONCodec) Unmarshal(data []byte, m proto.Message) error {
+	data, err := transformMessageJSON(data, m, collapseMessageData)
+	if err != nil {
+		return err
+	}
+
+	if err := protojson.Unmarshal(data, m); err != nil {
+		return err
+	}
+
+	return validateDecoded(m)
+}
+
+// TextCodec is a [Codec] that uses the Protocol Buffers text format.
+//
+// The text format is intended for human consumption, such as in logs and
+// debugging tools. It is not guaranteed to be stable across releases of the
+// Protocol Buffers library.
+type TextCodec struct{}
+
+// ContentType returns [TextContentType].
+func (TextCodec) ContentType() string {
+	return TextContentType
+}
+
+// Marshal returns the text representation of m.
+func (TextCodec) Marshal(m proto.Message) ([]byte, error) {
+	return prototext.MarshalOptions{Multiline: true}.Marshal(m)
+}
+
+// Unmarshal decodes the text representation in data into m.
+func (TextCodec) Unmarshal(data []byte, m proto.Message) error {
+	if err := prototext.Unmarshal(data, m); err != nil {
+		return err
+	}
+	return validateDecoded(m)
+}
+
+// CodecRegistry is a collection of [Codec] values, keyed by content type.
+//
+// The zero value is an empty registry. Use [DefaultCodecs] to obtain a
+// registry that contains the built-in codecs.
+type CodecRegistry struct {
+	codecs []Codec
+}
+
+// DefaultCodecs returns a new [CodecRegistry] that contains [BinaryCodec],
+// [JSONCodec] and [TextCodec], in that order.
+func DefaultCodecs() *CodecRegistry {
+	r := &CodecRegistry{}
+	r.Register(BinaryCodec{})
+	r.Register(JSONCodec{})
+	r.Register(TextCodec{})
+	return r
+}
+
+// Register adds c to the registry.
+//
+// It panics if the registry already contains a codec with the same content
+// type.
+func (r *CodecRegistry) Register(c Codec) {
+	if _, ok := r.Lookup(c.ContentType()); ok {
+		panic(fmt.Sprintf("a codec for %q is already registered", c.ContentType()))
+	}
+
+	r.codecs = append(r.codecs, c)
+}
+
+// Lookup returns the codec for the given content type.
+//
+// Media type parameters, such as "charset", are ignored.
+func (r *CodecRegistry) Lookup(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+
+	for _, c := range r.codecs {
+		if c.ContentType() == mediaType {
+			return c, true
+		}
+	}
+
+	return nil, false
+}
+
+// ContentTypes returns the content types of the codecs in the registry, in the
+// order they were registered.
+func (r *CodecRegistry) ContentTypes() []string {
+	types := make([]string, len(r.codecs))
+	for i, c := range r.codecs {
+		types[i] = c.ContentType()
+	}
+	return types
+}
+
+// validateDecoded returns an error if m is an [Envelope] or [MultiEnvelope]
+// that is not well-formed.
+func validateDecoded(m proto.Message) error {
+	if v, ok := m.(interface{ Validate() error }); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+// jsonObject is a JSON object with values that are left in their encoded form.
+type jsonObject = map[string]json.RawMessage
+
+// transformMessageJSON calls fn for each [Message] within the JSON
+// representation of m.
+//
+// It returns data unchanged if m is not an [Envelope] or [MultiEnvelope].
+func transformMessageJSON(
+	data []byte,
+	m proto.Message,
+	fn func(jsonObject) error,
+) ([]byte, error) {
+	var key string
+
+	switch m.(type) {
+	case *Envelope:
+		key = "body"
+	case *MultiEnvelope:
+		key = "bodies"
+	default:
+		return data, nil
+	}
+
+	var doc jsonObject
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	raw, ok := doc[key]
+	if !ok {
+		return data, nil
+	}
+
+	var bodies []jsonObject
+	if key == "body" {
+		bodies = make([]jsonObject, 1)
+		if err := json.Unmarshal(raw, &bodies[0]); err != nil {
+			return nil, err
+		}
+	} else if err := json.Unmarshal(raw, &bodies); err != nil {
+		return nil, err
+	}
+
+	for _, body := range bodies {
+		raw, ok := body["message"]
+		if !ok {
+			continue
+		}
+
+		var message jsonObject
+		if err := json.Unmarshal(raw, &message); err != nil {
+			return nil, err
+		}
+
+		if err := fn(message); err != nil {
+			return nil, err
+		}
+
+		raw, err := marshalJSON(message)
+		if err != nil {
+			return nil, err
+		}
+
+		body["message"] = raw
+	}
+
+	var err error
+	if key == "body" {
+		doc[key], err = marshalJSON(bodies[0])
+	} else {
+		doc[key], err = marshalJSON(bodies)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return marshalJSON(doc)
+}
+
+// expandMessageData replaces the base64-encoded "data" property of a JSON
+// [Message] with a "jsonData" property containing the decoded data, if the data
+// is compact JSON.
+func expandMessageData(message jsonObject) error {
+	raw, ok := message["data"]
+	if !ok {
+		return nil
+	}
+
+	var data []byte
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return err
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil || !bytes.Equal(compact.Bytes(), data) {
+		// Only expand data that can be reproduced exactly by collapsing it
+		// again.
+		return nil
+	}
+
+	delete(message, "data")
+	message["jsonData"] = data
+
+	return nil
+}
+
+// collapseMessageData reverses the transformation performed by
+// [expandMessageData].
+func collapseMessageData(message jsonObject) error {
+	raw, ok := message["jsonData"]
+	if !ok {
+		return nil
+	}
+
+	if _, ok := message["data"]; ok {
+		return errors.New("message must not contain both data and jsonData")
+	}
+
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, raw); err != nil {
+		return err
+	}
+
+	delete(message, "jsonData")
+	message["data"] = json.RawMessage(
+		`"` + base64.StdEncoding.EncodeToString(compact.Bytes()) + `"`,
+	)
+
+	return nil
+}
+
+// marshalJSON returns the JSON representation of v without escaping HTML
+// characters, such that raw values are reproduced exactly.
+func marshalJSON(v any) (json.RawMessage, error) {
+	var w bytes.Buffer
+
+	enc := json.NewEncoder(&w)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+
+	return bytes.TrimSuffix(w.Bytes(), []byte("\n")), nil
+}
@@ -0,0 +1,191 @@
+package envelopepb_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	. "github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+func TestCodec(t *testing.T) {
+	packer := &Packer{
+		Application: identitypb.New("app", uuidpb.Generate()),
+	}
+
+	env := packer.PackCommand(CommandA1)
+
+	multi := NewMultiEnvelopeBuilder().
+		WithHeader(env.GetHeader()).
+		WithBodies([]*Body{
+			env.GetBody(),
+			packer.PackCommand(CommandA2).GetBody(),
+		}).
+		Build()
+
+	codecs := []Codec{
+		BinaryCodec{},
+		JSONCodec{},
+		JSONCodec{ExpandMessageData: true},
+		TextCodec{},
+	}
+
+	for _, c := range codecs {
+		t.Run(c.ContentType(), func(t *testing.T) {
+			t.Run("it round-trips an envelope", func(t *testing.T) {
+				data, err := c.Marshal(env)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got := &Envelope{}
+				if err := c.Unmarshal(data, got); err != nil {
+					t.Fatal(err)
+				}
+
+				Expect(t, "unexpected envelope", got, env)
+			})
+
+			t.Run("it round-trips a multi-envelope", func(t *testing.T) {
+				data, err := c.Marshal(multi)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got := &MultiEnvelope{}
+				if err := c.Unmarshal(data, got); err != nil {
+					t.Fatal(err)
+				}
+
+				Expect(t, "unexpected multi-envelope", got, multi)
+			})
+
+			t.Run("it returns an error if the decoded envelope is invalid", func(t *testing.T) {
+				invalid := NewEnvelopeBuilder().
+					From(env).
+					WithBody(
+						NewBodyBuilder().
+							From(env.GetBody()).
+							WithMessageId(nil).
+							Build(),
+					).
+					Build()
+
+				data, err := c.Marshal(invalid)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				err = c.Unmarshal(data, &Envelope{})
+				if err == nil || !strings.Contains(err.Error(), "invalid body") {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			})
+		})
+	}
+
+	t.Run("type JSONCodec", func(t *testing.T) {
+		t.Run("it expands JSON message data", func(t *testing.T) {
+			data, err := JSONCodec{ExpandMessageData: true}.Marshal(env)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.Contains(string(data), `"jsonData":{"content":"A1"}`) {
+				t.Fatalf("expected expanded message data, got %s", data)
+			}
+
+			if strings.Contains(string(data), `"data":`) {
+				t.Fatalf("did not expect base64 message data, got %s", data)
+			}
+		})
+
+		t.Run("it does not expand non-JSON message data", func(t *testing.T) {
+			x := NewEnvelopeBuilder().
+				From(env).
+				WithBody(
+					NewBodyBuilder().
+						From(env.GetBody()).
+						WithMessage(
+							NewMessageBuilder().
+								From(env.GetBody().GetMessage()).
+								WithData([]byte("<not json>")).
+								Build(),
+						).
+						Build(),
+				).
+				Build()
+
+			data, err := JSONCodec{ExpandMessageData: true}.Marshal(x)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if strings.Contains(string(data), `"jsonData"`) {
+				t.Fatalf("did not expect expanded message data, got %s", data)
+			}
+
+			got := &Envelope{}
+			if err := (JSONCodec{}).Unmarshal(data, got); err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(t, "unexpected envelope", got, x)
+		})
+
+		t.Run("it decodes expanded data regardless of its options", func(t *testing.T) {
+			data, err := JSONCodec{ExpandMessageData: true}.Marshal(env)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := &Envelope{}
+			if err := (JSONCodec{}).Unmarshal(data, got); err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(t, "unexpected envelope", got, env)
+		})
+	})
+}
+
+func TestCodecRegistry(t *testing.T) {
+	t.Run("it looks up codecs by content type", func(t *testing.T) {
+		r := DefaultCodecs()
+
+		Expect(
+			t,
+			"unexpected content types",
+			r.ContentTypes(),
+			[]string{
+				BinaryContentType,
+				JSONContentType,
+				TextContentType,
+			},
+		)
+
+		c, ok := r.Lookup("application/json; charset=utf-8")
+		if !ok {
+			t.Fatal("expected codec to be found")
+		}
+
+		Expect(t, "unexpected codec", c, Codec(JSONCodec{}))
+
+		if _, ok := r.Lookup("application/xml"); ok {
+			t.Fatal("did not expect codec to be found")
+		}
+	})
+
+	t.Run("it panics if a codec is registered twice", func(t *testing.T) {
+		ExpectPanic(
+			t,
+			`a codec for "application/json" is already registered`,
+			func() {
+				DefaultCodecs().Register(JSONCodec{ExpandMessageData: true})
+			},
+		)
+	})
+}
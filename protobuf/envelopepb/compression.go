@@ -0,0 +1,249 @@
+package envelopepb
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// MaxUncompressedSize is the maximum size of message data, in bytes, that is
+// compressed by a [Packer] or decompressed by [Unpack].
+//
+// Larger message data is packed without compression. Envelopes that record an
+// uncompressed size larger than this are rejected without decompressing the
+// data.
+const MaxUncompressedSize = 64 << 20
+
+// compressedPlaceholder is the [Message] data used in place of the original
+// data when the data is compressed.
+//
+// The compressed data is stored in the [Compression] extension. The
+// placeholder begins with a NUL byte, which is not valid in the Protocol
+// Buffers or JSON encodings, so that readers that do not recognize the
+// extension fail to unmarshal the message.
+var compressedPlaceholder = []byte("\x00compressed")
+
+// A Compressor compresses and decompresses message data using a specific
+// [CompressionAlgorithm].
+type Compressor interface {
+	// Algorithm returns the algorithm implemented by the compressor.
+	Algorithm() CompressionAlgorithm
+
+	// Compress returns the compressed representation of data.
+	Compress(data []byte) ([]byte, error)
+
+	// Decompress returns the original data from its compressed
+	// representation.
+	//
+	// size is the size of the original data, in bytes, as recorded in the
+	// [Compression] extension. Implementations must return an error rather
+	// than produce more than size bytes, so that corrupt or malicious data
+	// can not exhaust memory.
+	Decompress(data []byte, size uint64) ([]byte, error)
+}
+
+// GzipCompressor is a [Compressor] that uses [CompressionAlgorithm_GZIP].
+var GzipCompressor Compressor = gzipCompressor{}
+
+var compressors = struct {
+	sync.RWMutex
+	byAlgorithm map[CompressionAlgorithm]Compressor
+}{
+	byAlgorithm: map[CompressionAlgorithm]Compressor{
+		CompressionAlgorithm_GZIP: GzipCompressor,
+	},
+}
+
+// RegisterCompressor makes c available to [Unpack] for decompressing message
+// data that was compressed using c.Algorithm(), replacing any existing
+// compressor for the same algorithm.
+//
+// [GzipCompressor] is registered by default.
+func RegisterCompressor(c Compressor) {
+	a := c.Algorithm()
+	if a == CompressionAlgorithm_UNKNOWN_COMPRESSION_ALGORITHM {
+		panic("compressor must use a known algorithm")
+	}
+
+	compressors.Lock()
+	defer compressors.Unlock()
+
+	compressors.byAlgorithm[a] = c
+}
+
+// lookupCompressor returns the compressor registered for the given algorithm.
+func lookupCompressor(a CompressionAlgorithm) (Compressor, bool) {
+	compressors.RLock()
+	defer compressors.RUnlock()
+
+	c, ok := compressors.byAlgorithm[a]
+	return c, ok
+}
+
+// compress moves the message data in body to a [Compression] extension, in its
+// compressed representation, if the data is at least as large as the packer's
+// compression threshold, and compression reduces its size.
+func (p *Packer) compress(body *Body) error {
+	if p.Compressor == nil {
+		return nil
+	}
+
+	message := body.GetMessage()
+	data := message.GetData()
+
+	if len(data) == 0 || len(data) < p.CompressionThreshold || len(data) > MaxUncompressedSize {
+		return nil
+	}
+
+	compressed, err := p.Compressor.Compress(data)
+	if err != nil {
+		return fmt.Errorf(
+			"unable to compress message data using %s: %w",
+			p.Compressor.Algorithm(),
+			err,
+		)
+	}
+
+	if len(compressed) >= len(data) {
+		return nil
+	}
+
+	message.SetData(compressedPlaceholder)
+
+	SetExtension(
+		body,
+		NewCompressionBuilder().
+			WithAlgorithm(p.Compressor.Algorithm()).
+			WithUncompressedSize(uint64(len(data))).
+			WithData(compressed).
+			Build(),
+	)
+
+	return nil
+}
+
+// storedData returns the message data within body as it is stored in the
+// envelope, which may be compressed and encrypted.
+//
+// Compressed data is stored in the [Compression] extension rather than in the
+// [Message].
+func storedData(body *Body) ([]byte, error) {
+	x, ok, err := GetExtension[*Compression](body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal compression extension: %w", err)
+	}
+	if ok {
+		return x.GetData(), nil
+	}
+	return body.GetMessage().GetData(), nil
+}
+
+// setStoredData replaces the message data within body with data, storing it
+// in the [Compression] extension if the data is compressed.
+func setStoredData(body *Body, data []byte) error {
+	x, ok, err := GetExtension[*Compression](body)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal compression extension: %w", err)
+	}
+	if !ok {
+		body.GetMessage().SetData(data)
+		return nil
+	}
+
+	x.SetData(data)
+	SetExtension(body, x)
+
+	return nil
+}
+
+// messageData returns the decrypted, uncompressed message data within body.
+func messageData(body *Body, keys KeyProvider) ([]byte, error) {
+	if _, ok, _ := GetExtension[*Redaction](body); ok {
+		return nil, ErrRedacted
+	}
+
+	data, _, err := decrypt(body, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	x, ok, err := GetExtension[*Compression](body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unmarshal compression extension: %w", err)
+	}
+	if !ok {
+		return data, nil
+	}
+
+	if x.GetUncompressedSize() > MaxUncompressedSize {
+		return nil, fmt.Errorf(
+			"uncompressed message data is %d bytes, which exceeds the limit of %d bytes",
+			x.GetUncompressedSize(),
+			MaxUncompressedSize,
+		)
+	}
+
+	c, ok := lookupCompressor(x.GetAlgorithm())
+	if !ok {
+		return nil, fmt.Errorf("no compressor is registered for %s", x.GetAlgorithm())
+	}
+
+	data, err = c.Decompress(data, x.GetUncompressedSize())
+	if err != nil {
+		return nil, fmt.Errorf("unable to decompress message data using %s: %w", x.GetAlgorithm(), err)
+	}
+
+	if uint64(len(data)) != x.GetUncompressedSize() {
+		return nil, fmt.Errorf(
+			"decompressed message data is %d bytes, expected %d",
+			len(data),
+			x.GetUncompressedSize(),
+		)
+	}
+
+	return data, nil
+}
+
+type gzipCompressor struct{}
+
+func (gzipCompressor) Algorithm() CompressionAlgorithm {
+	return CompressionAlgorithm_GZIP
+}
+
+func (gzipCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (gzipCompressor) Decompress(data []byte, size uint64) ([]byte, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	// Read at most one byte more than the expected size, so that data that
+	// decompresses to a larger size is detected without inflating it in full.
+	data, err = io.ReadAll(io.LimitReader(r, int64(min(size, MaxUncompressedSize))+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if uint64(len(data)) > size {
+		return nil, fmt.Errorf("decompressed data exceeds %d bytes", size)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,201 @@
+package envelopepb_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	. "github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestPacker_compression(t *testing.T) {
+	large := &CommandStub[TypeA]{
+		Content: TypeA(strings.Repeat("<content>", 100)),
+	}
+
+	for _, c := range []Compressor{GzipCompressor} {
+		t.Run(c.Algorithm().String(), func(t *testing.T) {
+			packer := &Packer{
+				Application:          identitypb.New("app", uuidpb.Generate()),
+				Compressor:           c,
+				CompressionThreshold: 100,
+			}
+
+			t.Run("it compresses data above the threshold", func(t *testing.T) {
+				env := packer.PackCommand(large)
+
+				x, ok, err := GetExtension[*Compression](env.GetBody())
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !ok {
+					t.Fatal("expected compression extension to be present")
+				}
+
+				Expect(t, "unexpected algorithm", x.GetAlgorithm(), c.Algorithm())
+
+				data, err := large.MarshalBinary()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				Expect(t, "unexpected uncompressed size", x.GetUncompressedSize(), uint64(len(data)))
+
+				if n := len(x.GetData()); n >= len(data) {
+					t.Fatalf("expected compressed data to be smaller than %d bytes, got %d", len(data), n)
+				}
+
+				got, err := Unpack[*CommandStub[TypeA]](env)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				Expect(t, "unexpected message", got, large)
+			})
+
+			t.Run("it prevents readers that do not recognize the extension from unmarshaling the data", func(t *testing.T) {
+				env := packer.PackCommand(large)
+				data := env.GetBody().GetMessage().GetData()
+
+				if err := new(CommandStub[TypeA]).UnmarshalBinary(data); err == nil {
+					t.Fatal("expected the message data to be rejected by the JSON decoder")
+				}
+
+				if err := proto.Unmarshal(data, &wrapperspb.StringValue{}); err == nil {
+					t.Fatal("expected the message data to be rejected by the Protocol Buffers decoder")
+				}
+			})
+
+			t.Run("it does not compress data below the threshold", func(t *testing.T) {
+				env := packer.PackCommand(CommandA1)
+
+				if _, ok, _ := GetExtension[*Compression](env.GetBody()); ok {
+					t.Fatal("did not expect compression extension to be present")
+				}
+
+				got, err := Unpack[*CommandStub[TypeA]](env)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				Expect(t, "unexpected message", got, CommandA1)
+			})
+
+			t.Run("it compresses effects", func(t *testing.T) {
+				cause := packer.PackCommand(CommandA1)
+				effects := packer.PackEffects(cause, identitypb.New("handler", uuidpb.Generate()))
+
+				env := effects.PackEvent(&EventStub[TypeA]{Content: large.Content})
+
+				if _, ok, _ := GetExtension[*Compression](env.GetBody()); !ok {
+					t.Fatal("expected compression extension to be present")
+				}
+
+				got, err := Unpack[dogma.Event](env)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				Expect(t, "unexpected message", got, dogma.Event(&EventStub[TypeA]{Content: large.Content}))
+			})
+		})
+	}
+
+	t.Run("it returns an error if the data decompresses to more than the recorded size", func(t *testing.T) {
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Compressor:  GzipCompressor,
+		}
+
+		env := packer.PackCommand(large)
+		x, _, _ := GetExtension[*Compression](env.GetBody())
+
+		SetExtension(
+			env.GetBody(),
+			NewCompressionBuilder().
+				From(x).
+				WithUncompressedSize(10).
+				Build(),
+		)
+
+		_, err := Unpack[*CommandStub[TypeA]](env)
+
+		Expect(
+			t,
+			"unexpected error message",
+			fmt.Sprint(err),
+			"unable to unpack envelope as *stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]: unable to decompress message data using GZIP: decompressed data exceeds 10 bytes",
+		)
+	})
+
+	t.Run("it returns an error if the recorded size exceeds the limit", func(t *testing.T) {
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Compressor:  GzipCompressor,
+		}
+
+		env := packer.PackCommand(large)
+		x, _, _ := GetExtension[*Compression](env.GetBody())
+
+		SetExtension(
+			env.GetBody(),
+			NewCompressionBuilder().
+				From(x).
+				WithUncompressedSize(MaxUncompressedSize+1).
+				Build(),
+		)
+
+		_, err := Unpack[*CommandStub[TypeA]](env)
+
+		Expect(
+			t,
+			"unexpected error message",
+			fmt.Sprint(err),
+			fmt.Sprintf(
+				"unable to unpack envelope as *stubs.CommandStub[github.com/dogmatiq/enginekit/enginetest/stubs.TypeA]: uncompressed message data is %d bytes, which exceeds the limit of %d bytes",
+				MaxUncompressedSize+1,
+				MaxUncompressedSize,
+			),
+		)
+	})
+
+	t.Run("it uses registered compressors", func(t *testing.T) {
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Compressor:  GzipCompressor,
+		}
+
+		env := packer.PackCommand(large)
+
+		c := &countingCompressor{Compressor: GzipCompressor}
+		RegisterCompressor(c)
+		t.Cleanup(func() { RegisterCompressor(GzipCompressor) })
+
+		got, err := Unpack[*CommandStub[TypeA]](env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected message", got, large)
+		Expect(t, "unexpected decompression count", c.Count, 1)
+	})
+}
+
+// countingCompressor is a [Compressor] that counts the number of times it
+// decompresses data.
+type countingCompressor struct {
+	Compressor
+	Count int
+}
+
+func (c *countingCompressor) Decompress(data []byte, size uint64) ([]byte, error) {
+	c.Count++
+	return c.Compressor.Decompress(data, size)
+}
@@ -2,6 +2,7 @@ package envelopepb
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/dogmatiq/dogma"
 	"github.com/dogmatiq/enginekit/protobuf/identitypb"
@@ -14,18 +15,42 @@ import (
 type EffectPacker struct {
 	generateID func() *uuidpb.UUID
 	now        func() *timestamppb.Timestamp
+	transform  func(*Header, *Body) error
 	header     *Header
 	bodies     []*Body
 	sealed     bool
+	limits     BatchLimits
+	emit       func(*MultiEnvelope)
+	batch      *batch
 }
 
 // PackEffects returns an [EffectPacker] that packs messages produced by h while
 // handling cause.
+//
+// It panics if cause is not well-formed. Use [Packer.TryPackEffects] to handle
+// such failures as errors.
 func (p *Packer) PackEffects(
 	cause *Envelope,
 	h *identitypb.Identity,
 	options ...PackEffectsOption,
 ) *EffectPacker {
+	e, err := p.TryPackEffects(cause, h, options...)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// TryPackEffects returns an [EffectPacker] that packs messages produced by h
+// while handling cause.
+//
+// It returns an [*InvalidEnvelopeError] if cause is not well-formed, or if the
+// options produce an invalid header. It panics if h or cause is nil.
+func (p *Packer) TryPackEffects(
+	cause *Envelope,
+	h *identitypb.Identity,
+	options ...PackEffectsOption,
+) (*EffectPacker, error) {
 	if h == nil {
 		panic("handler must not be nil")
 	}
@@ -35,7 +60,10 @@ func (p *Packer) PackEffects(
 	}
 
 	if err := cause.Validate(); err != nil {
-		panic(fmt.Errorf("invalid cause envelope: %w", err))
+		return nil, &InvalidEnvelopeError{
+			TypeID: cause.GetBody().GetMessage().GetTypeId(),
+			Err:    fmt.Errorf("invalid cause envelope: %w", err),
+		}
 	}
 
 	generateID := p.GenerateID
@@ -70,28 +98,67 @@ func (p *Packer) PackEffects(
 	}
 
 	if err := header.validate(); err != nil {
-		panic(fmt.Errorf("invalid header: %w", err))
+		return nil, &InvalidEnvelopeError{
+			Err: fmt.Errorf("invalid header: %w", err),
+		}
 	}
 
 	return &EffectPacker{
 		generateID: generateID,
 		now:        now,
+		transform:  p.transform,
 		header:     header,
-	}
+	}, nil
 }
 
 // PackCommand appends m to the multi-envelope under construction.
+//
+// It panics if m can not be packed. Use [EffectPacker.TryPackCommand] to handle
+// such failures as errors.
 func (p *EffectPacker) PackCommand(m dogma.Command, options ...PackEffectCommandOption) *Envelope {
-	return packEffectBody(p, m, PackEffectCommandOption.applyPackEffectCommandOption, options...)
+	return mustPack(p.TryPackCommand(m, options...))
 }
 
 // PackEvent appends m to the multi-envelope under construction.
+//
+// It panics if m can not be packed. Use [EffectPacker.TryPackEvent] to handle
+// such failures as errors.
 func (p *EffectPacker) PackEvent(m dogma.Event, options ...PackEffectEventOption) *Envelope {
-	return packEffectBody(p, m, PackEffectEventOption.applyPackEffectEventOption, options...)
+	return mustPack(p.TryPackEvent(m, options...))
 }
 
 // PackDeadline appends m to the multi-envelope under construction.
+//
+// It panics if m can not be packed. Use [EffectPacker.TryPackDeadline] to
+// handle such failures as errors.
 func (p *EffectPacker) PackDeadline(m dogma.Deadline, options ...PackEffectDeadlineOption) *Envelope {
+	return mustPack(p.TryPackDeadline(m, options...))
+}
+
+// TryPackCommand appends m to the multi-envelope under construction.
+//
+// It returns an [*UnregisteredMessageTypeError], [*MarshalError],
+// [*TransformError] or [*InvalidEnvelopeError] if m can not be packed, in which
+// case m is not appended.
+func (p *EffectPacker) TryPackCommand(m dogma.Command, options ...PackEffectCommandOption) (*Envelope, error) {
+	return packEffectBody(p, m, PackEffectCommandOption.applyPackEffectCommandOption, options...)
+}
+
+// TryPackEvent appends m to the multi-envelope under construction.
+//
+// It returns an [*UnregisteredMessageTypeError], [*MarshalError],
+// [*TransformError] or [*InvalidEnvelopeError] if m can not be packed, in which
+// case m is not appended.
+func (p *EffectPacker) TryPackEvent(m dogma.Event, options ...PackEffectEventOption) (*Envelope, error) {
+	return packEffectBody(p, m, PackEffectEventOption.applyPackEffectEventOption, options...)
+}
+
+// TryPackDeadline appends m to the multi-envelope under construction.
+//
+// It returns an [*UnregisteredMessageTypeError], [*MarshalError],
+// [*TransformError] or [*InvalidEnvelopeError] if m can not be packed, in which
+// case m is not appended.
+func (p *EffectPacker) TryPackDeadline(m dogma.Deadline, options ...PackEffectDeadlineOption) (*Envelope, error) {
 	return packEffectBody(p, m, PackEffectDeadlineOption.applyPackEffectDeadlineOption, options...)
 }
 
@@ -100,35 +167,17 @@ func packEffectBody[T any](
 	m dogma.Message,
 	apply func(T, *Body),
 	options ...T,
-) *Envelope {
+) (*Envelope, error) {
 	p.mustNotBeSealed()
 
-	mt, ok := dogma.RegisteredMessageTypeOf(m)
-	if !ok {
-		panic(fmt.Sprintf(
-			"%T is not a registered message type",
-			m,
-		))
-	}
-
-	data, err := m.MarshalBinary()
+	message, err := marshalMessage(m)
 	if err != nil {
-		panic(fmt.Sprintf(
-			"unable to marshal %T: %s",
-			m,
-			err,
-		))
+		return nil, err
 	}
 
 	body := NewBodyBuilder().
 		WithMessageId(p.generateID()).
-		WithMessage(
-			NewMessageBuilder().
-				WithTypeId(uuidpb.MustParse(mt.ID())).
-				WithDescription(m.MessageDescription()).
-				WithData(data).
-				Build(),
-		).
+		WithMessage(message).
 		Build()
 
 	for _, opt := range options {
@@ -139,20 +188,39 @@ func packEffectBody[T any](
 		body.SetCreatedAt(p.now())
 	}
 
+	if err := p.transform(p.header, body); err != nil {
+		return nil, &TransformError{reflect.TypeOf(m), message.GetTypeId(), err}
+	}
+
 	if err := body.validate(p.header); err != nil {
-		panic(fmt.Errorf("invalid body: %w", err))
+		return nil, &InvalidEnvelopeError{
+			reflect.TypeOf(m),
+			message.GetTypeId(),
+			fmt.Errorf("invalid body: %w", err),
+		}
 	}
 
-	p.bodies = append(p.bodies, body)
+	p.add(body)
 
 	return NewEnvelopeBuilder().
 		WithHeader(p.header).
 		WithBody(body).
-		Build()
+		Build(), nil
+}
+
+// mustPack panics if err is non-nil, otherwise it returns env.
+func mustPack(env *Envelope, err error) *Envelope {
+	if err != nil {
+		panic(err)
+	}
+	return env
 }
 
 // Seal returns a [MultiEnvelope] containing all packed messages, or false if no
 // messages were packed.
+//
+// If p was obtained from [Packer.PackEffectBatches], the envelope contains only
+// those messages that have not already been emitted as part of a batch.
 func (p *EffectPacker) Seal() (*MultiEnvelope, bool) {
 	p.mustNotBeSealed()
 	p.sealed = true
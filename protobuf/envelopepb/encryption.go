@@ -0,0 +1,378 @@
+package envelopepb
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// EncryptedDescription is the description used in place of a message's
+// original description when the description is encrypted.
+const EncryptedDescription = "(encrypted)"
+
+// ErrKeyUnavailable indicates that the key used to encrypt message data is not
+// available, for example because it has been destroyed.
+var ErrKeyUnavailable = errors.New("key is unavailable")
+
+// A KeyProvider provides the keys used to encrypt and decrypt message data.
+//
+// Keys must be 16, 24 or 32 bytes long, to select AES-128, AES-192 or AES-256,
+// respectively.
+type KeyProvider interface {
+	// EncryptionKey returns the key with the given ID, creating it if it does
+	// not already exist.
+	EncryptionKey(keyID string) ([]byte, error)
+
+	// DecryptionKey returns the key with the given ID.
+	//
+	// ok is false if the key does not exist, such as when it has been
+	// destroyed.
+	DecryptionKey(keyID string) (key []byte, ok bool, err error)
+}
+
+// A KeySelector returns the ID of the key used to encrypt the message in body.
+//
+// If ok is false the message is not encrypted.
+type KeySelector func(header *Header, body *Body) (keyID string, ok bool)
+
+// KeyPerCorrelation returns a [KeySelector] that uses a separate key for each
+// correlation ID, such that all messages in the same causal chain are
+// encrypted using the same key.
+func KeyPerCorrelation() KeySelector {
+	return func(header *Header, _ *Body) (string, bool) {
+		return header.GetCorrelationId().AsString(), true
+	}
+}
+
+// KeyPerBaggage returns a [KeySelector] that uses the key returned by keyID
+// for the baggage value of type T.
+//
+// Messages that do not carry baggage of type T are not encrypted. Because
+// baggage is inherited by downstream messages, all messages in the same
+// causal chain are encrypted using the same key.
+func KeyPerBaggage[
+	T interface {
+		*E
+		proto.Message
+	},
+	E any,
+](keyID func(T) string) KeySelector {
+	return func(header *Header, body *Body) (string, bool) {
+		for _, values := range [][]*anypb.Any{body.GetBaggage(), header.GetBaggage()} {
+			for _, v := range values {
+				var x T = new(E)
+				if v.MessageIs(x) && v.UnmarshalTo(x) == nil {
+					return keyID(x), true
+				}
+			}
+		}
+
+		return "", false
+	}
+}
+
+// An Encrypter encrypts the message data within envelopes using AES-GCM.
+//
+// The ID of the key used to encrypt the data is recorded in an [Encryption]
+// extension, which [Unpack] and [Decrypt] use to obtain the key needed to
+// decrypt the data. Destroying the key makes the message data permanently
+// unreadable, while the remainder of the envelope remains valid.
+type Encrypter struct {
+	// Keys provides the keys used to encrypt message data.
+	Keys KeyProvider
+
+	// SelectKey returns the ID of the key used to encrypt each message. If it
+	// is nil, [KeyPerCorrelation] is used.
+	SelectKey KeySelector
+
+	// EncryptDescription enables encryption of the message's description, in
+	// addition to its data. The original description is replaced with
+	// [EncryptedDescription].
+	EncryptDescription bool
+}
+
+// Encrypt encrypts the message data within env.
+func (e *Encrypter) Encrypt(env *Envelope) error {
+	return e.encrypt(env.GetHeader(), env.GetBody())
+}
+
+// EncryptMulti encrypts the message data within each body of env.
+func (e *Encrypter) EncryptMulti(env *MultiEnvelope) error {
+	for i, b := range env.GetBodies() {
+		if err := e.encrypt(env.GetHeader(), b); err != nil {
+			return fmt.Errorf("unable to encrypt body at index %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// encrypt encrypts the message within body, if a key is selected for it.
+func (e *Encrypter) encrypt(header *Header, body *Body) error {
+	selectKey := e.SelectKey
+	if selectKey == nil {
+		selectKey = KeyPerCorrelation()
+	}
+
+	keyID, ok := selectKey(header, body)
+	if !ok {
+		return nil
+	}
+
+	if _, ok, _ := GetExtension[*Encryption](body); ok {
+		return errors.New("message data is already encrypted")
+	}
+
+	key, err := e.Keys.EncryptionKey(keyID)
+	if err != nil {
+		return fmt.Errorf("unable to obtain key %q: %w", keyID, err)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return fmt.Errorf("unable to use key %q: %w", keyID, err)
+	}
+
+	message := body.GetMessage()
+	ad := additionalData(body)
+
+	plaintext, err := storedData(body)
+	if err != nil {
+		return err
+	}
+
+	nonce := newNonce(aead)
+
+	x := NewEncryptionBuilder().
+		WithAlgorithm(EncryptionAlgorithm_AES_GCM).
+		WithKeyId(keyID).
+		WithNonce(nonce)
+
+	data := aead.Seal(nil, nonce, plaintext, ad)
+
+	if e.EncryptDescription {
+		nonce := newNonce(aead)
+		x.WithDescriptionNonce(nonce).
+			WithEncryptedDescription(aead.Seal(nil, nonce, []byte(message.GetDescription()), ad))
+		message.SetDescription(EncryptedDescription)
+	}
+
+	if err := setStoredData(body, data); err != nil {
+		return err
+	}
+
+	SetExtension(body, x.Build())
+
+	return nil
+}
+
+// Decrypt returns a copy of env with its message data and description
+// decrypted using the keys in k.
+//
+// If the message data is not encrypted, env is returned unchanged. The
+// returned error wraps [ErrKeyUnavailable] if the key used to encrypt the data
+// can not be obtained from k.
+func Decrypt(env *Envelope, k KeyProvider) (*Envelope, error) {
+	body := env.GetBody()
+
+	if _, ok, _ := GetExtension[*Encryption](body); !ok {
+		return env, nil
+	}
+
+	data, desc, err := decrypt(body, k)
+	if err != nil {
+		return nil, err
+	}
+
+	var extensions []*anypb.Any
+	for _, v := range body.GetExtensions() {
+		if !v.MessageIs((*Encryption)(nil)) {
+			extensions = append(extensions, v)
+		}
+	}
+
+	decrypted := NewBodyBuilder().
+		From(body).
+		WithMessage(
+			NewMessageBuilder().
+				From(body.GetMessage()).
+				WithDescription(desc).
+				Build(),
+		).
+		WithExtensions(extensions).
+		Build()
+
+	if err := setStoredData(decrypted, data); err != nil {
+		return nil, err
+	}
+
+	return NewEnvelopeBuilder().
+		From(env).
+		WithBody(decrypted).
+		Build(), nil
+}
+
+// decrypt returns the decrypted message data and description within body.
+func decrypt(body *Body, k KeyProvider) ([]byte, string, error) {
+	message := body.GetMessage()
+
+	x, ok, err := GetExtension[*Encryption](body)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to unmarshal encryption extension: %w", err)
+	}
+	data, err := storedData(body)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !ok {
+		return data, message.GetDescription(), nil
+	}
+
+	if x.GetAlgorithm() != EncryptionAlgorithm_AES_GCM {
+		return nil, "", fmt.Errorf("unsupported encryption algorithm: %s", x.GetAlgorithm())
+	}
+
+	if k == nil {
+		return nil, "", errors.New("message data is encrypted, but no key provider was given")
+	}
+
+	key, ok, err := k.DecryptionKey(x.GetKeyId())
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to obtain key %q: %w", x.GetKeyId(), err)
+	}
+	if !ok {
+		return nil, "", fmt.Errorf("unable to decrypt message data using key %q: %w", x.GetKeyId(), ErrKeyUnavailable)
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to use key %q: %w", x.GetKeyId(), err)
+	}
+
+	ad := additionalData(body)
+
+	data, err = aead.Open(nil, x.GetNonce(), data, ad)
+	if err != nil {
+		return nil, "", fmt.Errorf("unable to decrypt message data using key %q: %w", x.GetKeyId(), err)
+	}
+
+	desc := message.GetDescription()
+	if len(x.GetEncryptedDescription()) != 0 {
+		d, err := aead.Open(nil, x.GetDescriptionNonce(), x.GetEncryptedDescription(), ad)
+		if err != nil {
+			return nil, "", fmt.Errorf("unable to decrypt message description using key %q: %w", x.GetKeyId(), err)
+		}
+		desc = string(d)
+	}
+
+	return data, desc, nil
+}
+
+// encrypt encrypts the message within body if the packer has an encrypter.
+func (p *Packer) encrypt(header *Header, body *Body) error {
+	if p.Encrypter == nil {
+		return nil
+	}
+
+	if err := p.Encrypter.encrypt(header, body); err != nil {
+		return fmt.Errorf("unable to encrypt message data: %w", err)
+	}
+
+	return nil
+}
+
+// newAEAD returns an AES-GCM cipher that uses the given key.
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// newNonce returns a new random nonce for use with aead.
+func newNonce(aead cipher.AEAD) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		panic(err)
+	}
+	return nonce
+}
+
+// additionalData returns the additional authenticated data used when
+// encrypting the message within body, which binds the ciphertext to the
+// message's ID and type.
+func additionalData(body *Body) []byte {
+	return []byte(
+		body.GetMessageId().AsString() + "/" + body.GetMessage().GetTypeId().AsString(),
+	)
+}
+
+// MemoryKeyProvider is an in-memory implementation of [KeyProvider] that
+// generates random AES-256 keys.
+//
+// It is safe for concurrent use. The zero-value is ready to use.
+type MemoryKeyProvider struct {
+	m         sync.Mutex
+	keys      map[string][]byte
+	destroyed map[string]struct{}
+}
+
+// EncryptionKey returns the key with the given ID, creating it if it does not
+// already exist. It returns an error wrapping [ErrKeyUnavailable] if the key
+// has been destroyed.
+func (p *MemoryKeyProvider) EncryptionKey(keyID string) ([]byte, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	if _, ok := p.destroyed[keyID]; ok {
+		return nil, fmt.Errorf("key %q has been destroyed: %w", keyID, ErrKeyUnavailable)
+	}
+
+	if key, ok := p.keys[keyID]; ok {
+		return key, nil
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	if p.keys == nil {
+		p.keys = map[string][]byte{}
+	}
+	p.keys[keyID] = key
+
+	return key, nil
+}
+
+// DecryptionKey returns the key with the given ID.
+func (p *MemoryKeyProvider) DecryptionKey(keyID string) ([]byte, bool, error) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	key, ok := p.keys[keyID]
+	return key, ok, nil
+}
+
+// Destroy permanently destroys the key with the given ID, such that messages
+// encrypted using that key can no longer be decrypted.
+func (p *MemoryKeyProvider) Destroy(keyID string) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	delete(p.keys, keyID)
+
+	if p.destroyed == nil {
+		p.destroyed = map[string]struct{}{}
+	}
+	p.destroyed[keyID] = struct{}{}
+}
@@ -0,0 +1,206 @@
+package envelopepb_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	. "github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+func TestEncrypter(t *testing.T) {
+	t.Run("it encrypts message data using a key per correlation ID", func(t *testing.T) {
+		keys := &MemoryKeyProvider{}
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Encrypter:   &Encrypter{Keys: keys},
+		}
+
+		env := packer.PackCommand(CommandA1)
+
+		x, ok, err := GetExtension[*Encryption](env.GetBody())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected encryption extension to be present")
+		}
+
+		Expect(t, "unexpected key ID", x.GetKeyId(), env.GetHeader().GetCorrelationId().AsString())
+		Expect(t, "unexpected description", env.GetBody().GetMessage().GetDescription(), CommandA1.MessageDescription())
+
+		if strings.Contains(string(env.GetBody().GetMessage().GetData()), "A1") {
+			t.Fatal("expected message data to be encrypted")
+		}
+
+		if _, err := Unpack[*CommandStub[TypeA]](env); err == nil {
+			t.Fatal("expected an error when unpacking without a key provider")
+		}
+
+		got, err := Unpack[*CommandStub[TypeA]](env, WithKeyProvider(keys))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected message", got, CommandA1)
+	})
+
+	t.Run("it encrypts the description if configured to do so", func(t *testing.T) {
+		keys := &MemoryKeyProvider{}
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Encrypter: &Encrypter{
+				Keys:               keys,
+				EncryptDescription: true,
+			},
+		}
+
+		env := packer.PackCommand(CommandA1)
+
+		if err := env.Validate(); err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected description", env.GetBody().GetMessage().GetDescription(), EncryptedDescription)
+
+		decrypted, err := Decrypt(env, keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected description", decrypted.GetBody().GetMessage().GetDescription(), CommandA1.MessageDescription())
+
+		if _, ok, _ := GetExtension[*Encryption](decrypted.GetBody()); ok {
+			t.Fatal("did not expect encryption extension to be present")
+		}
+
+		got, err := Unpack[*CommandStub[TypeA]](decrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected message", got, CommandA1)
+	})
+
+	t.Run("it encrypts message data using a key per baggage value", func(t *testing.T) {
+		keys := &MemoryKeyProvider{}
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Encrypter: &Encrypter{
+				Keys: keys,
+				SelectKey: KeyPerBaggage(func(x *identitypb.Identity) string {
+					return "customer/" + x.GetName()
+				}),
+			},
+		}
+
+		customer := identitypb.New("customer-1", uuidpb.Generate())
+
+		t.Run("it encrypts messages with the baggage", func(t *testing.T) {
+			cause := packer.PackCommand(CommandA1, WithBaggage(customer))
+			effects := packer.PackEffects(cause, identitypb.New("handler", uuidpb.Generate()))
+			env := effects.PackEvent(EventA1)
+
+			x, ok, _ := GetExtension[*Encryption](env.GetBody())
+			if !ok {
+				t.Fatal("expected encryption extension to be present")
+			}
+
+			Expect(t, "unexpected key ID", x.GetKeyId(), "customer/customer-1")
+
+			got, err := Unpack[dogma.Event](env, WithKeyProvider(keys))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(t, "unexpected message", got, dogma.Event(EventA1))
+		})
+
+		t.Run("it does not encrypt messages without the baggage", func(t *testing.T) {
+			env := packer.PackCommand(CommandA1)
+
+			if _, ok, _ := GetExtension[*Encryption](env.GetBody()); ok {
+				t.Fatal("did not expect encryption extension to be present")
+			}
+		})
+	})
+
+	t.Run("it can not decrypt message data after the key is destroyed", func(t *testing.T) {
+		keys := &MemoryKeyProvider{}
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Compressor:  GzipCompressor,
+			Encrypter:   &Encrypter{Keys: keys},
+		}
+
+		env := packer.PackCommand(CommandA1)
+		keys.Destroy(env.GetHeader().GetCorrelationId().AsString())
+
+		if err := env.Validate(); err != nil {
+			t.Fatal(err)
+		}
+
+		_, err := Unpack[*CommandStub[TypeA]](env, WithKeyProvider(keys))
+		if !errors.Is(err, ErrKeyUnavailable) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, err = Decrypt(env, keys)
+		if !errors.Is(err, ErrKeyUnavailable) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("it binds the encrypted data to the message ID", func(t *testing.T) {
+		keys := &MemoryKeyProvider{}
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Encrypter:   &Encrypter{Keys: keys},
+		}
+
+		env := packer.PackCommand(CommandA1)
+		env.GetBody().SetMessageId(uuidpb.Generate())
+
+		if _, err := Unpack[*CommandStub[TypeA]](env, WithKeyProvider(keys)); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("it encrypts each body in a multi-envelope", func(t *testing.T) {
+		keys := &MemoryKeyProvider{}
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+		}
+
+		cause := packer.PackCommand(CommandA1)
+		effects := packer.PackEffects(cause, identitypb.New("handler", uuidpb.Generate()))
+		effects.PackEvent(EventA1)
+		effects.PackEvent(EventA2)
+		multi, _ := effects.Seal()
+
+		e := &Encrypter{Keys: keys}
+		if err := e.EncryptMulti(multi); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := e.EncryptMulti(multi); err == nil || err.Error() != "unable to encrypt body at index 0: message data is already encrypted" {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var got []dogma.Event
+		for env := range multi.All() {
+			m, err := Unpack[dogma.Event](env, WithKeyProvider(keys))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, m)
+		}
+
+		Expect(t, "unexpected messages", got, []dogma.Event{EventA1, EventA2})
+	})
+}
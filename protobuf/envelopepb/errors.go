@@ -0,0 +1,83 @@
+package envelopepb
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+// UnregisteredMessageTypeError is returned when packing a message whose type
+// is not registered with Dogma's message type registry.
+type UnregisteredMessageTypeError struct {
+	// GoType is the Go type of the message.
+	GoType reflect.Type
+}
+
+func (e *UnregisteredMessageTypeError) Error() string {
+	return fmt.Sprintf("%s is not a registered message type", e.GoType)
+}
+
+// MarshalError is returned when a message's MarshalBinary method fails.
+type MarshalError struct {
+	// GoType is the Go type of the message.
+	GoType reflect.Type
+
+	// TypeID is the message type's registered ID.
+	TypeID *uuidpb.UUID
+
+	// Err is the error returned by the message's MarshalBinary method.
+	Err error
+}
+
+func (e *MarshalError) Error() string {
+	return fmt.Sprintf("unable to marshal %s: %s", e.GoType, e.Err)
+}
+
+func (e *MarshalError) Unwrap() error {
+	return e.Err
+}
+
+// TransformError is returned when a message's data can not be compressed or
+// encrypted, or its envelope can not be signed.
+type TransformError struct {
+	// GoType is the Go type of the message.
+	GoType reflect.Type
+
+	// TypeID is the message type's registered ID.
+	TypeID *uuidpb.UUID
+
+	// Err is the error that occurred while transforming the envelope.
+	Err error
+}
+
+func (e *TransformError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *TransformError) Unwrap() error {
+	return e.Err
+}
+
+// InvalidEnvelopeError is returned when packing produces an envelope that is
+// not well-formed, or when the envelope of a causal message is not
+// well-formed.
+type InvalidEnvelopeError struct {
+	// GoType is the Go type of the message, or nil if it is not known, such
+	// as when the causal message's envelope is invalid.
+	GoType reflect.Type
+
+	// TypeID is the message type's registered ID, or nil if it is not known.
+	TypeID *uuidpb.UUID
+
+	// Err is the validation error.
+	Err error
+}
+
+func (e *InvalidEnvelopeError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *InvalidEnvelopeError) Unwrap() error {
+	return e.Err
+}
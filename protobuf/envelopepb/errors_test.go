@@ -0,0 +1,129 @@
+package envelopepb_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	. "github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+type unmarshalableEvent struct{ EventStub[func()] }
+
+const unmarshalableEventTypeID = "1b8d6b5a-3a4f-4f0e-9d55-2d3f8d5b2a61"
+
+func init() {
+	dogma.RegisterEvent[*unmarshalableEvent](unmarshalableEventTypeID)
+}
+
+func TestPacker_TryPack(t *testing.T) {
+	packer := &Packer{
+		Application: identitypb.New("app", uuidpb.Generate()),
+	}
+
+	cause := packer.PackCommand(CommandA1)
+	handler := identitypb.New("handler", uuidpb.Generate())
+
+	t.Run("it returns an error if the message type is not registered", func(t *testing.T) {
+		type T struct{ dogma.Command }
+
+		_, err := packer.TryPackCommand(&T{})
+
+		var target *UnregisteredMessageTypeError
+		if !errors.As(err, &target) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expectGoType(t, target.GoType, reflect.TypeFor[*T]())
+	})
+
+	t.Run("it returns an error if the message can not be marshaled", func(t *testing.T) {
+		effects := packer.PackEffects(cause, handler)
+
+		_, err := effects.TryPackEvent(&unmarshalableEvent{})
+
+		var target *MarshalError
+		if !errors.As(err, &target) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expectGoType(t, target.GoType, reflect.TypeFor[*unmarshalableEvent]())
+		Expect(t, "unexpected type ID", target.TypeID, uuidpb.MustParse(unmarshalableEventTypeID))
+		Expect(t, "unexpected error message", err.Error(), "unable to marshal *envelopepb_test.unmarshalableEvent: json: unsupported type: func()")
+
+		if _, ok := effects.Seal(); ok {
+			t.Fatal("did not expect the failed message to be packed")
+		}
+	})
+
+	t.Run("it returns an error if the message data can not be transformed", func(t *testing.T) {
+		p := &Packer{
+			Application: packer.Application,
+			Encrypter: &Encrypter{
+				Keys: &MemoryKeyProvider{},
+				SelectKey: func(*Header, *Body) (string, bool) {
+					return "<destroyed>", true
+				},
+			},
+		}
+		p.Encrypter.Keys.(*MemoryKeyProvider).Destroy("<destroyed>")
+
+		_, err := p.TryPackCommand(CommandA1)
+
+		var target *TransformError
+		if !errors.As(err, &target) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expectGoType(t, target.GoType, reflect.TypeOf(CommandA1))
+
+		if !errors.Is(err, ErrKeyUnavailable) {
+			t.Fatalf("expected error to wrap ErrKeyUnavailable: %v", err)
+		}
+	})
+
+	t.Run("it returns an error if the envelope is invalid", func(t *testing.T) {
+		effects := packer.PackEffects(cause, handler)
+
+		_, err := effects.TryPackDeadline(
+			DeadlineA1,
+			WithScheduledFor(cause.GetBody().GetCreatedAt().AsTime()),
+		)
+
+		var target *InvalidEnvelopeError
+		if !errors.As(err, &target) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		expectGoType(t, target.GoType, reflect.TypeOf(DeadlineA1))
+		Expect(t, "unexpected error message", err.Error(), "invalid body: invalid scheduled-for time: must not be specified without a source handler and instance ID")
+	})
+
+	t.Run("it returns an error if the cause is invalid", func(t *testing.T) {
+		_, err := packer.TryPackEffects(&Envelope{}, handler)
+
+		var target *InvalidEnvelopeError
+		if !errors.As(err, &target) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if target.GoType != nil {
+			t.Fatalf("did not expect a Go type, got %s", target.GoType)
+		}
+
+		Expect(t, "unexpected error message", err.Error(), "invalid cause envelope: invalid header: must not be nil")
+	})
+}
+
+func expectGoType(t *testing.T, got, want reflect.Type) {
+	t.Helper()
+
+	if got != want {
+		t.Fatalf("unexpected Go type: got %s, want %s", got, want)
+	}
+}
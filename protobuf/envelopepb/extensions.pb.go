@@ -21,6 +21,138 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// CompressionAlgorithm is an enumeration of the algorithms that may be used to
+// compress message data.
+type CompressionAlgorithm int32
+
+const (
+	CompressionAlgorithm_UNKNOWN_COMPRESSION_ALGORITHM CompressionAlgorithm = 0
+	CompressionAlgorithm_GZIP                          CompressionAlgorithm = 1
+)
+
+// Enum value maps for CompressionAlgorithm.
+var (
+	CompressionAlgorithm_name = map[int32]string{
+		0: "UNKNOWN_COMPRESSION_ALGORITHM",
+		1: "GZIP",
+	}
+	CompressionAlgorithm_value = map[string]int32{
+		"UNKNOWN_COMPRESSION_ALGORITHM": 0,
+		"GZIP":                          1,
+	}
+)
+
+func (x CompressionAlgorithm) Enum() *CompressionAlgorithm {
+	p := new(CompressionAlgorithm)
+	*p = x
+	return p
+}
+
+func (x CompressionAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CompressionAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_enumTypes[0].Descriptor()
+}
+
+func (CompressionAlgorithm) Type() protoreflect.EnumType {
+	return &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_enumTypes[0]
+}
+
+func (x CompressionAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// SignatureAlgorithm is an enumeration of the algorithms that may be used to
+// sign an envelope.
+type SignatureAlgorithm int32
+
+const (
+	SignatureAlgorithm_UNKNOWN_SIGNATURE_ALGORITHM SignatureAlgorithm = 0
+	SignatureAlgorithm_ED25519                     SignatureAlgorithm = 1
+	SignatureAlgorithm_HMAC_SHA256                 SignatureAlgorithm = 2
+)
+
+// Enum value maps for SignatureAlgorithm.
+var (
+	SignatureAlgorithm_name = map[int32]string{
+		0: "UNKNOWN_SIGNATURE_ALGORITHM",
+		1: "ED25519",
+		2: "HMAC_SHA256",
+	}
+	SignatureAlgorithm_value = map[string]int32{
+		"UNKNOWN_SIGNATURE_ALGORITHM": 0,
+		"ED25519":                     1,
+		"HMAC_SHA256":                 2,
+	}
+)
+
+func (x SignatureAlgorithm) Enum() *SignatureAlgorithm {
+	p := new(SignatureAlgorithm)
+	*p = x
+	return p
+}
+
+func (x SignatureAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SignatureAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_enumTypes[1].Descriptor()
+}
+
+func (SignatureAlgorithm) Type() protoreflect.EnumType {
+	return &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_enumTypes[1]
+}
+
+func (x SignatureAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// EncryptionAlgorithm is an enumeration of the algorithms that may be used to
+// encrypt message data.
+type EncryptionAlgorithm int32
+
+const (
+	EncryptionAlgorithm_UNKNOWN_ENCRYPTION_ALGORITHM EncryptionAlgorithm = 0
+	EncryptionAlgorithm_AES_GCM                      EncryptionAlgorithm = 1
+)
+
+// Enum value maps for EncryptionAlgorithm.
+var (
+	EncryptionAlgorithm_name = map[int32]string{
+		0: "UNKNOWN_ENCRYPTION_ALGORITHM",
+		1: "AES_GCM",
+	}
+	EncryptionAlgorithm_value = map[string]int32{
+		"UNKNOWN_ENCRYPTION_ALGORITHM": 0,
+		"AES_GCM":                      1,
+	}
+)
+
+func (x EncryptionAlgorithm) Enum() *EncryptionAlgorithm {
+	p := new(EncryptionAlgorithm)
+	*p = x
+	return p
+}
+
+func (x EncryptionAlgorithm) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EncryptionAlgorithm) Descriptor() protoreflect.EnumDescriptor {
+	return file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_enumTypes[2].Descriptor()
+}
+
+func (EncryptionAlgorithm) Type() protoreflect.EnumType {
+	return &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_enumTypes[2]
+}
+
+func (x EncryptionAlgorithm) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
 // EventStreamPosition is an extension value for an [Envelope] that identifies
 // the stream from which an event was obtained, and the offset of the event
 // within that stream.
@@ -110,6 +242,491 @@ func (b0 EventStreamPosition_builder) Build() *EventStreamPosition {
 	return m0
 }
 
+// Compression is an extension value for an [Envelope] that indicates that the
+// data within the envelope's [Message] has been compressed.
+//
+// The compressed data is stored in this extension, rather than in the
+// [Message]. The [Message] data is replaced with a placeholder that is not
+// valid in the Protocol Buffers or JSON encodings, so that readers that do not
+// recognize this extension fail to unmarshal the message, rather than
+// misinterpreting the compressed data.
+type Compression struct {
+	state                       protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Algorithm        CompressionAlgorithm   `protobuf:"varint,1,opt,name=algorithm,enum=dogma.protobuf.CompressionAlgorithm"`
+	xxx_hidden_UncompressedSize uint64                 `protobuf:"varint,2,opt,name=uncompressed_size,json=uncompressedSize"`
+	xxx_hidden_Data             []byte                 `protobuf:"bytes,3,opt,name=data"`
+	unknownFields               protoimpl.UnknownFields
+	sizeCache                   protoimpl.SizeCache
+}
+
+func (x *Compression) Reset() {
+	*x = Compression{}
+	mi := &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Compression) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Compression) ProtoMessage() {}
+
+func (x *Compression) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *Compression) GetAlgorithm() CompressionAlgorithm {
+	if x != nil {
+		return x.xxx_hidden_Algorithm
+	}
+	return CompressionAlgorithm_UNKNOWN_COMPRESSION_ALGORITHM
+}
+
+func (x *Compression) GetUncompressedSize() uint64 {
+	if x != nil {
+		return x.xxx_hidden_UncompressedSize
+	}
+	return 0
+}
+
+func (x *Compression) GetData() []byte {
+	if x != nil {
+		return x.xxx_hidden_Data
+	}
+	return nil
+}
+
+func (x *Compression) SetAlgorithm(v CompressionAlgorithm) {
+	x.xxx_hidden_Algorithm = v
+}
+
+func (x *Compression) SetUncompressedSize(v uint64) {
+	x.xxx_hidden_UncompressedSize = v
+}
+
+func (x *Compression) SetData(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Data = v
+}
+
+type Compression_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// Algorithm is the algorithm used to compress the message data.
+	Algorithm CompressionAlgorithm
+	// UncompressedSize is the size of the message data before compression, in
+	// bytes.
+	UncompressedSize uint64
+	// Data is the compressed message data. If the message data is also
+	// encrypted, this field contains the encrypted form of the compressed data.
+	Data []byte
+}
+
+func (b0 Compression_builder) Build() *Compression {
+	m0 := &Compression{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Algorithm = b.Algorithm
+	x.xxx_hidden_UncompressedSize = b.UncompressedSize
+	x.xxx_hidden_Data = b.Data
+	return m0
+}
+
+// Signature is an extension value for an [Envelope] that contains a
+// cryptographic signature of the envelope's header and body.
+//
+// The signature is computed over the canonical encoding of the header and the
+// body, excluding the signature extension itself.
+type Signature struct {
+	state                protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Algorithm SignatureAlgorithm     `protobuf:"varint,1,opt,name=algorithm,enum=dogma.protobuf.SignatureAlgorithm"`
+	xxx_hidden_KeyId     string                 `protobuf:"bytes,2,opt,name=key_id,json=keyId"`
+	xxx_hidden_Signature []byte                 `protobuf:"bytes,3,opt,name=signature"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *Signature) Reset() {
+	*x = Signature{}
+	mi := &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Signature) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Signature) ProtoMessage() {}
+
+func (x *Signature) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *Signature) GetAlgorithm() SignatureAlgorithm {
+	if x != nil {
+		return x.xxx_hidden_Algorithm
+	}
+	return SignatureAlgorithm_UNKNOWN_SIGNATURE_ALGORITHM
+}
+
+func (x *Signature) GetKeyId() string {
+	if x != nil {
+		return x.xxx_hidden_KeyId
+	}
+	return ""
+}
+
+func (x *Signature) GetSignature() []byte {
+	if x != nil {
+		return x.xxx_hidden_Signature
+	}
+	return nil
+}
+
+func (x *Signature) SetAlgorithm(v SignatureAlgorithm) {
+	x.xxx_hidden_Algorithm = v
+}
+
+func (x *Signature) SetKeyId(v string) {
+	x.xxx_hidden_KeyId = v
+}
+
+func (x *Signature) SetSignature(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Signature = v
+}
+
+type Signature_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// Algorithm is the algorithm used to produce the signature.
+	Algorithm SignatureAlgorithm
+	// KeyId is an identifier for the key used to produce the signature. It is
+	// used to select the key used to verify the signature, which allows keys to
+	// be rotated without invalidating existing signatures.
+	KeyId string
+	// Signature is the signature itself.
+	Signature []byte
+}
+
+func (b0 Signature_builder) Build() *Signature {
+	m0 := &Signature{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Algorithm = b.Algorithm
+	x.xxx_hidden_KeyId = b.KeyId
+	x.xxx_hidden_Signature = b.Signature
+	return m0
+}
+
+// Encryption is an extension value for an [Envelope] that indicates that the
+// data within the envelope's [Message] has been encrypted.
+//
+// Readers that do not recognize this extension must not attempt to unmarshal
+// the message data.
+type Encryption struct {
+	state                           protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Algorithm            EncryptionAlgorithm    `protobuf:"varint,1,opt,name=algorithm,enum=dogma.protobuf.EncryptionAlgorithm"`
+	xxx_hidden_KeyId                string                 `protobuf:"bytes,2,opt,name=key_id,json=keyId"`
+	xxx_hidden_Nonce                []byte                 `protobuf:"bytes,3,opt,name=nonce"`
+	xxx_hidden_EncryptedDescription []byte                 `protobuf:"bytes,4,opt,name=encrypted_description,json=encryptedDescription"`
+	xxx_hidden_DescriptionNonce     []byte                 `protobuf:"bytes,5,opt,name=description_nonce,json=descriptionNonce"`
+	unknownFields                   protoimpl.UnknownFields
+	sizeCache                       protoimpl.SizeCache
+}
+
+func (x *Encryption) Reset() {
+	*x = Encryption{}
+	mi := &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Encryption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Encryption) ProtoMessage() {}
+
+func (x *Encryption) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *Encryption) GetAlgorithm() EncryptionAlgorithm {
+	if x != nil {
+		return x.xxx_hidden_Algorithm
+	}
+	return EncryptionAlgorithm_UNKNOWN_ENCRYPTION_ALGORITHM
+}
+
+func (x *Encryption) GetKeyId() string {
+	if x != nil {
+		return x.xxx_hidden_KeyId
+	}
+	return ""
+}
+
+func (x *Encryption) GetNonce() []byte {
+	if x != nil {
+		return x.xxx_hidden_Nonce
+	}
+	return nil
+}
+
+func (x *Encryption) GetEncryptedDescription() []byte {
+	if x != nil {
+		return x.xxx_hidden_EncryptedDescription
+	}
+	return nil
+}
+
+func (x *Encryption) GetDescriptionNonce() []byte {
+	if x != nil {
+		return x.xxx_hidden_DescriptionNonce
+	}
+	return nil
+}
+
+func (x *Encryption) SetAlgorithm(v EncryptionAlgorithm) {
+	x.xxx_hidden_Algorithm = v
+}
+
+func (x *Encryption) SetKeyId(v string) {
+	x.xxx_hidden_KeyId = v
+}
+
+func (x *Encryption) SetNonce(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_Nonce = v
+}
+
+func (x *Encryption) SetEncryptedDescription(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_EncryptedDescription = v
+}
+
+func (x *Encryption) SetDescriptionNonce(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_DescriptionNonce = v
+}
+
+type Encryption_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// Algorithm is the algorithm used to encrypt the message data.
+	Algorithm EncryptionAlgorithm
+	// KeyId is an identifier for the key used to encrypt the message data.
+	KeyId string
+	// Nonce is the nonce used to encrypt the message data.
+	Nonce []byte
+	// EncryptedDescription is the encrypted form of the message's description.
+	//
+	// It is empty if the description was not encrypted, in which case the
+	// [Message] contains the original description.
+	EncryptedDescription []byte
+	// DescriptionNonce is the nonce used to encrypt the message's description.
+	DescriptionNonce []byte
+}
+
+func (b0 Encryption_builder) Build() *Encryption {
+	m0 := &Encryption{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Algorithm = b.Algorithm
+	x.xxx_hidden_KeyId = b.KeyId
+	x.xxx_hidden_Nonce = b.Nonce
+	x.xxx_hidden_EncryptedDescription = b.EncryptedDescription
+	x.xxx_hidden_DescriptionNonce = b.DescriptionNonce
+	return m0
+}
+
+// SchemaVersion is an extension value for an [Envelope] that identifies the
+// version of the schema used to encode the data within the envelope's
+// [Message].
+//
+// It allows a message type to evolve without changing its type ID. Readers use
+// it to select an upcaster that converts the data to the current schema.
+type SchemaVersion struct {
+	state              protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_Version uint32                 `protobuf:"varint,1,opt,name=version"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *SchemaVersion) Reset() {
+	*x = SchemaVersion{}
+	mi := &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SchemaVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SchemaVersion) ProtoMessage() {}
+
+func (x *SchemaVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *SchemaVersion) GetVersion() uint32 {
+	if x != nil {
+		return x.xxx_hidden_Version
+	}
+	return 0
+}
+
+func (x *SchemaVersion) SetVersion(v uint32) {
+	x.xxx_hidden_Version = v
+}
+
+type SchemaVersion_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// Version is the schema version. Messages without this extension are
+	// considered to be at version zero.
+	Version uint32
+}
+
+func (b0 SchemaVersion_builder) Build() *SchemaVersion {
+	m0 := &SchemaVersion{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_Version = b.Version
+	return m0
+}
+
+// Redaction is an extension value for an [Envelope] that indicates that the
+// envelope has been redacted, such that its [Message] no longer contains the
+// original data or description.
+//
+// Readers must not attempt to unmarshal the message data.
+type Redaction struct {
+	state                 protoimpl.MessageState `protogen:"opaque.v1"`
+	xxx_hidden_DataSize   uint64                 `protobuf:"varint,1,opt,name=data_size,json=dataSize"`
+	xxx_hidden_DataSha256 []byte                 `protobuf:"bytes,2,opt,name=data_sha256,json=dataSha256"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *Redaction) Reset() {
+	*x = Redaction{}
+	mi := &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Redaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Redaction) ProtoMessage() {}
+
+func (x *Redaction) ProtoReflect() protoreflect.Message {
+	mi := &file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+func (x *Redaction) GetDataSize() uint64 {
+	if x != nil {
+		return x.xxx_hidden_DataSize
+	}
+	return 0
+}
+
+func (x *Redaction) GetDataSha256() []byte {
+	if x != nil {
+		return x.xxx_hidden_DataSha256
+	}
+	return nil
+}
+
+func (x *Redaction) SetDataSize(v uint64) {
+	x.xxx_hidden_DataSize = v
+}
+
+func (x *Redaction) SetDataSha256(v []byte) {
+	if v == nil {
+		v = []byte{}
+	}
+	x.xxx_hidden_DataSha256 = v
+}
+
+type Redaction_builder struct {
+	_ [0]func() // Prevents comparability and use of unkeyed literals for the builder.
+
+	// DataSize is the size of the original message data, in bytes, as stored in
+	// the envelope. If the data is compressed or encrypted, it is the size of the
+	// compressed or encrypted data.
+	DataSize uint64
+	// DataSha256 is the SHA-256 hash of the original message data, as stored in
+	// the envelope. If the data is compressed, it is the hash of the compressed
+	// data. It is empty if the data was removed without being hashed, which is
+	// always the case for encrypted data.
+	DataSha256 []byte
+}
+
+func (b0 Redaction_builder) Build() *Redaction {
+	m0 := &Redaction{}
+	b, x := &b0, m0
+	_, _ = b, x
+	x.xxx_hidden_DataSize = b.DataSize
+	x.xxx_hidden_DataSha256 = b.DataSha256
+	return m0
+}
+
 var File_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto protoreflect.FileDescriptor
 
 const file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_rawDesc = "" +
@@ -117,20 +734,63 @@ const file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_ra
 	"Bgithub.com/dogmatiq/enginekit/protobuf/envelopepb/extensions.proto\x12\x0edogma.protobuf\x1a8github.com/dogmatiq/enginekit/protobuf/uuidpb/uuid.proto\"g\n" +
 	"\x13EventStreamPosition\x121\n" +
 	"\tstream_id\x18\x01 \x01(\v2\x14.dogma.protobuf.UUIDR\bstreamId\x12\x1d\n" +
-	"\x06offset\x18\x02 \x01(\x04B\x05\xaa\x01\x02\b\x02R\x06offsetB3Z1github.com/dogmatiq/enginekit/protobuf/envelopepbb\beditionsp\xe9\a"
+	"\x06offset\x18\x02 \x01(\x04B\x05\xaa\x01\x02\b\x02R\x06offset\"\xa7\x01\n" +
+	"\vCompression\x12I\n" +
+	"\talgorithm\x18\x01 \x01(\x0e2$.dogma.protobuf.CompressionAlgorithmB\x05\xaa\x01\x02\b\x02R\talgorithm\x122\n" +
+	"\x11uncompressed_size\x18\x02 \x01(\x04B\x05\xaa\x01\x02\b\x02R\x10uncompressedSize\x12\x19\n" +
+	"\x04data\x18\x03 \x01(\fB\x05\xaa\x01\x02\b\x02R\x04data\"\x97\x01\n" +
+	"\tSignature\x12G\n" +
+	"\talgorithm\x18\x01 \x01(\x0e2\".dogma.protobuf.SignatureAlgorithmB\x05\xaa\x01\x02\b\x02R\talgorithm\x12\x1c\n" +
+	"\x06key_id\x18\x02 \x01(\tB\x05\xaa\x01\x02\b\x02R\x05keyId\x12#\n" +
+	"\tsignature\x18\x03 \x01(\fB\x05\xaa\x01\x02\b\x02R\tsignature\"\x81\x02\n" +
+	"\n" +
+	"Encryption\x12H\n" +
+	"\talgorithm\x18\x01 \x01(\x0e2#.dogma.protobuf.EncryptionAlgorithmB\x05\xaa\x01\x02\b\x02R\talgorithm\x12\x1c\n" +
+	"\x06key_id\x18\x02 \x01(\tB\x05\xaa\x01\x02\b\x02R\x05keyId\x12\x1b\n" +
+	"\x05nonce\x18\x03 \x01(\fB\x05\xaa\x01\x02\b\x02R\x05nonce\x12:\n" +
+	"\x15encrypted_description\x18\x04 \x01(\fB\x05\xaa\x01\x02\b\x02R\x14encryptedDescription\x122\n" +
+	"\x11description_nonce\x18\x05 \x01(\fB\x05\xaa\x01\x02\b\x02R\x10descriptionNonce\"0\n" +
+	"\rSchemaVersion\x12\x1f\n" +
+	"\aversion\x18\x01 \x01(\rB\x05\xaa\x01\x02\b\x02R\aversion\"W\n" +
+	"\tRedaction\x12\"\n" +
+	"\tdata_size\x18\x01 \x01(\x04B\x05\xaa\x01\x02\b\x02R\bdataSize\x12&\n" +
+	"\vdata_sha256\x18\x02 \x01(\fB\x05\xaa\x01\x02\b\x02R\n" +
+	"dataSha256*C\n" +
+	"\x14CompressionAlgorithm\x12!\n" +
+	"\x1dUNKNOWN_COMPRESSION_ALGORITHM\x10\x00\x12\b\n" +
+	"\x04GZIP\x10\x01*S\n" +
+	"\x12SignatureAlgorithm\x12\x1f\n" +
+	"\x1bUNKNOWN_SIGNATURE_ALGORITHM\x10\x00\x12\v\n" +
+	"\aED25519\x10\x01\x12\x0f\n" +
+	"\vHMAC_SHA256\x10\x02*D\n" +
+	"\x13EncryptionAlgorithm\x12 \n" +
+	"\x1cUNKNOWN_ENCRYPTION_ALGORITHM\x10\x00\x12\v\n" +
+	"\aAES_GCM\x10\x01B3Z1github.com/dogmatiq/enginekit/protobuf/envelopepbb\beditionsp\xe9\a"
 
-var file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
 var file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_goTypes = []any{
-	(*EventStreamPosition)(nil), // 0: dogma.protobuf.EventStreamPosition
-	(*uuidpb.UUID)(nil),         // 1: dogma.protobuf.UUID
+	(CompressionAlgorithm)(0),   // 0: dogma.protobuf.CompressionAlgorithm
+	(SignatureAlgorithm)(0),     // 1: dogma.protobuf.SignatureAlgorithm
+	(EncryptionAlgorithm)(0),    // 2: dogma.protobuf.EncryptionAlgorithm
+	(*EventStreamPosition)(nil), // 3: dogma.protobuf.EventStreamPosition
+	(*Compression)(nil),         // 4: dogma.protobuf.Compression
+	(*Signature)(nil),           // 5: dogma.protobuf.Signature
+	(*Encryption)(nil),          // 6: dogma.protobuf.Encryption
+	(*SchemaVersion)(nil),       // 7: dogma.protobuf.SchemaVersion
+	(*Redaction)(nil),           // 8: dogma.protobuf.Redaction
+	(*uuidpb.UUID)(nil),         // 9: dogma.protobuf.UUID
 }
 var file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_depIdxs = []int32{
-	1, // 0: dogma.protobuf.EventStreamPosition.stream_id:type_name -> dogma.protobuf.UUID
-	1, // [1:1] is the sub-list for method output_type
-	1, // [1:1] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	9, // 0: dogma.protobuf.EventStreamPosition.stream_id:type_name -> dogma.protobuf.UUID
+	0, // 1: dogma.protobuf.Compression.algorithm:type_name -> dogma.protobuf.CompressionAlgorithm
+	1, // 2: dogma.protobuf.Signature.algorithm:type_name -> dogma.protobuf.SignatureAlgorithm
+	2, // 3: dogma.protobuf.Encryption.algorithm:type_name -> dogma.protobuf.EncryptionAlgorithm
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_init() }
@@ -143,13 +803,14 @@ func file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_ini
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_rawDesc), len(file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   1,
+			NumEnums:      3,
+			NumMessages:   6,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_goTypes,
 		DependencyIndexes: file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_depIdxs,
+		EnumInfos:         file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_enumTypes,
 		MessageInfos:      file_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto_msgTypes,
 	}.Build()
 	File_github_com_dogmatiq_enginekit_protobuf_envelopepb_extensions_proto = out.File
@@ -7,6 +7,7 @@
 package envelopepb
 
 import (
+	"fmt"
 	uuidpb "github.com/dogmatiq/enginekit/protobuf/uuidpb"
 	proto "google.golang.org/protobuf/proto"
 )
@@ -76,3 +77,502 @@ func (x *EventStreamPosition) MarshalBinary() ([]byte, error) {
 func (x *EventStreamPosition) UnmarshalBinary(data []byte) error {
 	return proto.Unmarshal(data, x)
 }
+
+type CompressionBuilder struct {
+	prototype Compression
+}
+
+// NewCompressionBuilder returns a builder that constructs [Compression] messages.
+func NewCompressionBuilder() *CompressionBuilder {
+	return &CompressionBuilder{}
+}
+
+// From configures the builder to use x as the prototype for new messages,
+// then returns b.
+//
+// It performs a shallow copy of x, such that any changes made via the builder
+// do not modify x. It does not make a copy of the field values themselves.
+func (b *CompressionBuilder) From(x *Compression) *CompressionBuilder {
+	proto.Reset(&b.prototype)
+	b.prototype.SetAlgorithm(x.GetAlgorithm())
+	b.prototype.SetUncompressedSize(x.GetUncompressedSize())
+	b.prototype.SetData(x.GetData())
+	return b
+}
+
+// Build returns a new [Compression] containing the values configured via the builder.
+//
+// Each call returns a new message, such that future changes to the builder do
+// not modify previously constructed messages.
+func (b *CompressionBuilder) Build() *Compression {
+	m := &Compression{}
+	m.SetAlgorithm(b.prototype.GetAlgorithm())
+	m.SetUncompressedSize(b.prototype.GetUncompressedSize())
+	m.SetData(b.prototype.GetData())
+	return m
+}
+
+// WithAlgorithm configures the builder to set the Algorithm field to v,
+// then returns b.
+func (b *CompressionBuilder) WithAlgorithm(v CompressionAlgorithm) *CompressionBuilder {
+	b.prototype.SetAlgorithm(v)
+	return b
+}
+
+// WithUncompressedSize configures the builder to set the UncompressedSize field to v,
+// then returns b.
+func (b *CompressionBuilder) WithUncompressedSize(v uint64) *CompressionBuilder {
+	b.prototype.SetUncompressedSize(v)
+	return b
+}
+
+// WithData configures the builder to set the Data field to v,
+// then returns b.
+func (b *CompressionBuilder) WithData(v []byte) *CompressionBuilder {
+	b.prototype.SetData(v)
+	return b
+}
+
+// MarshalBinary returns the binary representation of the message, equivalent to
+// calling proto.Marshal(x).
+//
+// It allows [*Compression] to implement [encoding.BinaryMarshaler].
+func (x *Compression) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(x)
+}
+
+// UnmarshalBinary populates x from its binary representation, equivalent to
+// calling proto.Unmarshal(data, x).
+//
+// It allows [*Compression] to implement [encoding.BinaryUnmarshaler].
+func (x *Compression) UnmarshalBinary(data []byte) error {
+	return proto.Unmarshal(data, x)
+}
+
+type SignatureBuilder struct {
+	prototype Signature
+}
+
+// NewSignatureBuilder returns a builder that constructs [Signature] messages.
+func NewSignatureBuilder() *SignatureBuilder {
+	return &SignatureBuilder{}
+}
+
+// From configures the builder to use x as the prototype for new messages,
+// then returns b.
+//
+// It performs a shallow copy of x, such that any changes made via the builder
+// do not modify x. It does not make a copy of the field values themselves.
+func (b *SignatureBuilder) From(x *Signature) *SignatureBuilder {
+	proto.Reset(&b.prototype)
+	b.prototype.SetAlgorithm(x.GetAlgorithm())
+	b.prototype.SetKeyId(x.GetKeyId())
+	b.prototype.SetSignature(x.GetSignature())
+	return b
+}
+
+// Build returns a new [Signature] containing the values configured via the builder.
+//
+// Each call returns a new message, such that future changes to the builder do
+// not modify previously constructed messages.
+func (b *SignatureBuilder) Build() *Signature {
+	m := &Signature{}
+	m.SetAlgorithm(b.prototype.GetAlgorithm())
+	m.SetKeyId(b.prototype.GetKeyId())
+	m.SetSignature(b.prototype.GetSignature())
+	return m
+}
+
+// WithAlgorithm configures the builder to set the Algorithm field to v,
+// then returns b.
+func (b *SignatureBuilder) WithAlgorithm(v SignatureAlgorithm) *SignatureBuilder {
+	b.prototype.SetAlgorithm(v)
+	return b
+}
+
+// WithKeyId configures the builder to set the KeyId field to v,
+// then returns b.
+func (b *SignatureBuilder) WithKeyId(v string) *SignatureBuilder {
+	b.prototype.SetKeyId(v)
+	return b
+}
+
+// WithSignature configures the builder to set the Signature field to v,
+// then returns b.
+func (b *SignatureBuilder) WithSignature(v []byte) *SignatureBuilder {
+	b.prototype.SetSignature(v)
+	return b
+}
+
+// MarshalBinary returns the binary representation of the message, equivalent to
+// calling proto.Marshal(x).
+//
+// It allows [*Signature] to implement [encoding.BinaryMarshaler].
+func (x *Signature) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(x)
+}
+
+// UnmarshalBinary populates x from its binary representation, equivalent to
+// calling proto.Unmarshal(data, x).
+//
+// It allows [*Signature] to implement [encoding.BinaryUnmarshaler].
+func (x *Signature) UnmarshalBinary(data []byte) error {
+	return proto.Unmarshal(data, x)
+}
+
+type EncryptionBuilder struct {
+	prototype Encryption
+}
+
+// NewEncryptionBuilder returns a builder that constructs [Encryption] messages.
+func NewEncryptionBuilder() *EncryptionBuilder {
+	return &EncryptionBuilder{}
+}
+
+// From configures the builder to use x as the prototype for new messages,
+// then returns b.
+//
+// It performs a shallow copy of x, such that any changes made via the builder
+// do not modify x. It does not make a copy of the field values themselves.
+func (b *EncryptionBuilder) From(x *Encryption) *EncryptionBuilder {
+	proto.Reset(&b.prototype)
+	b.prototype.SetAlgorithm(x.GetAlgorithm())
+	b.prototype.SetKeyId(x.GetKeyId())
+	b.prototype.SetNonce(x.GetNonce())
+	b.prototype.SetEncryptedDescription(x.GetEncryptedDescription())
+	b.prototype.SetDescriptionNonce(x.GetDescriptionNonce())
+	return b
+}
+
+// Build returns a new [Encryption] containing the values configured via the builder.
+//
+// Each call returns a new message, such that future changes to the builder do
+// not modify previously constructed messages.
+func (b *EncryptionBuilder) Build() *Encryption {
+	m := &Encryption{}
+	m.SetAlgorithm(b.prototype.GetAlgorithm())
+	m.SetKeyId(b.prototype.GetKeyId())
+	m.SetNonce(b.prototype.GetNonce())
+	m.SetEncryptedDescription(b.prototype.GetEncryptedDescription())
+	m.SetDescriptionNonce(b.prototype.GetDescriptionNonce())
+	return m
+}
+
+// WithAlgorithm configures the builder to set the Algorithm field to v,
+// then returns b.
+func (b *EncryptionBuilder) WithAlgorithm(v EncryptionAlgorithm) *EncryptionBuilder {
+	b.prototype.SetAlgorithm(v)
+	return b
+}
+
+// WithKeyId configures the builder to set the KeyId field to v,
+// then returns b.
+func (b *EncryptionBuilder) WithKeyId(v string) *EncryptionBuilder {
+	b.prototype.SetKeyId(v)
+	return b
+}
+
+// WithNonce configures the builder to set the Nonce field to v,
+// then returns b.
+func (b *EncryptionBuilder) WithNonce(v []byte) *EncryptionBuilder {
+	b.prototype.SetNonce(v)
+	return b
+}
+
+// WithEncryptedDescription configures the builder to set the EncryptedDescription field to v,
+// then returns b.
+func (b *EncryptionBuilder) WithEncryptedDescription(v []byte) *EncryptionBuilder {
+	b.prototype.SetEncryptedDescription(v)
+	return b
+}
+
+// WithDescriptionNonce configures the builder to set the DescriptionNonce field to v,
+// then returns b.
+func (b *EncryptionBuilder) WithDescriptionNonce(v []byte) *EncryptionBuilder {
+	b.prototype.SetDescriptionNonce(v)
+	return b
+}
+
+// MarshalBinary returns the binary representation of the message, equivalent to
+// calling proto.Marshal(x).
+//
+// It allows [*Encryption] to implement [encoding.BinaryMarshaler].
+func (x *Encryption) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(x)
+}
+
+// UnmarshalBinary populates x from its binary representation, equivalent to
+// calling proto.Unmarshal(data, x).
+//
+// It allows [*Encryption] to implement [encoding.BinaryUnmarshaler].
+func (x *Encryption) UnmarshalBinary(data []byte) error {
+	return proto.Unmarshal(data, x)
+}
+
+type SchemaVersionBuilder struct {
+	prototype SchemaVersion
+}
+
+// NewSchemaVersionBuilder returns a builder that constructs [SchemaVersion] messages.
+func NewSchemaVersionBuilder() *SchemaVersionBuilder {
+	return &SchemaVersionBuilder{}
+}
+
+// From configures the builder to use x as the prototype for new messages,
+// then returns b.
+//
+// It performs a shallow copy of x, such that any changes made via the builder
+// do not modify x. It does not make a copy of the field values themselves.
+func (b *SchemaVersionBuilder) From(x *SchemaVersion) *SchemaVersionBuilder {
+	proto.Reset(&b.prototype)
+	b.prototype.SetVersion(x.GetVersion())
+	return b
+}
+
+// Build returns a new [SchemaVersion] containing the values configured via the builder.
+//
+// Each call returns a new message, such that future changes to the builder do
+// not modify previously constructed messages.
+func (b *SchemaVersionBuilder) Build() *SchemaVersion {
+	m := &SchemaVersion{}
+	m.SetVersion(b.prototype.GetVersion())
+	return m
+}
+
+// WithVersion configures the builder to set the Version field to v,
+// then returns b.
+func (b *SchemaVersionBuilder) WithVersion(v uint32) *SchemaVersionBuilder {
+	b.prototype.SetVersion(v)
+	return b
+}
+
+// MarshalBinary returns the binary representation of the message, equivalent to
+// calling proto.Marshal(x).
+//
+// It allows [*SchemaVersion] to implement [encoding.BinaryMarshaler].
+func (x *SchemaVersion) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(x)
+}
+
+// UnmarshalBinary populates x from its binary representation, equivalent to
+// calling proto.Unmarshal(data, x).
+//
+// It allows [*SchemaVersion] to implement [encoding.BinaryUnmarshaler].
+func (x *SchemaVersion) UnmarshalBinary(data []byte) error {
+	return proto.Unmarshal(data, x)
+}
+
+type RedactionBuilder struct {
+	prototype Redaction
+}
+
+// NewRedactionBuilder returns a builder that constructs [Redaction] messages.
+func NewRedactionBuilder() *RedactionBuilder {
+	return &RedactionBuilder{}
+}
+
+// From configures the builder to use x as the prototype for new messages,
+// then returns b.
+//
+// It performs a shallow copy of x, such that any changes made via the builder
+// do not modify x. It does not make a copy of the field values themselves.
+func (b *RedactionBuilder) From(x *Redaction) *RedactionBuilder {
+	proto.Reset(&b.prototype)
+	b.prototype.SetDataSize(x.GetDataSize())
+	b.prototype.SetDataSha256(x.GetDataSha256())
+	return b
+}
+
+// Build returns a new [Redaction] containing the values configured via the builder.
+//
+// Each call returns a new message, such that future changes to the builder do
+// not modify previously constructed messages.
+func (b *RedactionBuilder) Build() *Redaction {
+	m := &Redaction{}
+	m.SetDataSize(b.prototype.GetDataSize())
+	m.SetDataSha256(b.prototype.GetDataSha256())
+	return m
+}
+
+// WithDataSize configures the builder to set the DataSize field to v,
+// then returns b.
+func (b *RedactionBuilder) WithDataSize(v uint64) *RedactionBuilder {
+	b.prototype.SetDataSize(v)
+	return b
+}
+
+// WithDataSha256 configures the builder to set the DataSha256 field to v,
+// then returns b.
+func (b *RedactionBuilder) WithDataSha256(v []byte) *RedactionBuilder {
+	b.prototype.SetDataSha256(v)
+	return b
+}
+
+// MarshalBinary returns the binary representation of the message, equivalent to
+// calling proto.Marshal(x).
+//
+// It allows [*Redaction] to implement [encoding.BinaryMarshaler].
+func (x *Redaction) MarshalBinary() ([]byte, error) {
+	return proto.Marshal(x)
+}
+
+// UnmarshalBinary populates x from its binary representation, equivalent to
+// calling proto.Unmarshal(data, x).
+//
+// It allows [*Redaction] to implement [encoding.BinaryUnmarshaler].
+func (x *Redaction) UnmarshalBinary(data []byte) error {
+	return proto.Unmarshal(data, x)
+}
+
+type (
+	// CompressionAlgorithm_UNKNOWN_COMPRESSION_ALGORITHM_Case is a type that statically associates a function
+	// with a [CompressionAlgorithm_UNKNOWN_COMPRESSION_ALGORITHM] value.
+	CompressionAlgorithm_UNKNOWN_COMPRESSION_ALGORITHM_Case struct{}
+	// CompressionAlgorithm_GZIP_Case is a type that statically associates a function
+	// with a [CompressionAlgorithm_GZIP] value.
+	CompressionAlgorithm_GZIP_Case struct{}
+)
+
+// Switch_CompressionAlgorithm dispatches to a function based on the value of v.
+//
+// It invokes the function that corresponds to v. It panics if v is not a
+// recognized [CompressionAlgorithm] value.
+func Switch_CompressionAlgorithm(
+	v CompressionAlgorithm,
+	caseUNKNOWN_COMPRESSION_ALGORITHM func(CompressionAlgorithm_UNKNOWN_COMPRESSION_ALGORITHM_Case),
+	caseGZIP func(CompressionAlgorithm_GZIP_Case),
+) {
+	switch v {
+	case CompressionAlgorithm_UNKNOWN_COMPRESSION_ALGORITHM:
+		caseUNKNOWN_COMPRESSION_ALGORITHM(CompressionAlgorithm_UNKNOWN_COMPRESSION_ALGORITHM_Case{})
+	case CompressionAlgorithm_GZIP:
+		caseGZIP(CompressionAlgorithm_GZIP_Case{})
+	default:
+		panic(fmt.Sprintf("Switch_CompressionAlgorithm: %d is not a valid CompressionAlgorithm", v))
+	}
+}
+
+// Map_CompressionAlgorithm maps a member of the [CompressionAlgorithm] enumeration to a
+// value of type T.
+//
+// It invokes the function that corresponds to v, and returns that function's
+// result. It panics if v is not a recognized [CompressionAlgorithm] value.
+func Map_CompressionAlgorithm[T any](
+	v CompressionAlgorithm,
+	caseUNKNOWN_COMPRESSION_ALGORITHM func(CompressionAlgorithm_UNKNOWN_COMPRESSION_ALGORITHM_Case) T,
+	caseGZIP func(CompressionAlgorithm_GZIP_Case) T,
+) T {
+	switch v {
+	case CompressionAlgorithm_UNKNOWN_COMPRESSION_ALGORITHM:
+		return caseUNKNOWN_COMPRESSION_ALGORITHM(CompressionAlgorithm_UNKNOWN_COMPRESSION_ALGORITHM_Case{})
+	case CompressionAlgorithm_GZIP:
+		return caseGZIP(CompressionAlgorithm_GZIP_Case{})
+	default:
+		panic(fmt.Sprintf("Map_CompressionAlgorithm: %d is not a valid CompressionAlgorithm", v))
+	}
+}
+
+type (
+	// SignatureAlgorithm_UNKNOWN_SIGNATURE_ALGORITHM_Case is a type that statically associates a function
+	// with a [SignatureAlgorithm_UNKNOWN_SIGNATURE_ALGORITHM] value.
+	SignatureAlgorithm_UNKNOWN_SIGNATURE_ALGORITHM_Case struct{}
+	// SignatureAlgorithm_ED25519_Case is a type that statically associates a function
+	// with a [SignatureAlgorithm_ED25519] value.
+	SignatureAlgorithm_ED25519_Case struct{}
+	// SignatureAlgorithm_HMAC_SHA256_Case is a type that statically associates a function
+	// with a [SignatureAlgorithm_HMAC_SHA256] value.
+	SignatureAlgorithm_HMAC_SHA256_Case struct{}
+)
+
+// Switch_SignatureAlgorithm dispatches to a function based on the value of v.
+//
+// It invokes the function that corresponds to v. It panics if v is not a
+// recognized [SignatureAlgorithm] value.
+func Switch_SignatureAlgorithm(
+	v SignatureAlgorithm,
+	caseUNKNOWN_SIGNATURE_ALGORITHM func(SignatureAlgorithm_UNKNOWN_SIGNATURE_ALGORITHM_Case),
+	caseED25519 func(SignatureAlgorithm_ED25519_Case),
+	caseHMAC_SHA256 func(SignatureAlgorithm_HMAC_SHA256_Case),
+) {
+	switch v {
+	case SignatureAlgorithm_UNKNOWN_SIGNATURE_ALGORITHM:
+		caseUNKNOWN_SIGNATURE_ALGORITHM(SignatureAlgorithm_UNKNOWN_SIGNATURE_ALGORITHM_Case{})
+	case SignatureAlgorithm_ED25519:
+		caseED25519(SignatureAlgorithm_ED25519_Case{})
+	case SignatureAlgorithm_HMAC_SHA256:
+		caseHMAC_SHA256(SignatureAlgorithm_HMAC_SHA256_Case{})
+	default:
+		panic(fmt.Sprintf("Switch_SignatureAlgorithm: %d is not a valid SignatureAlgorithm", v))
+	}
+}
+
+// Map_SignatureAlgorithm maps a member of the [SignatureAlgorithm] enumeration to a
+// value of type T.
+//
+// It invokes the function that corresponds to v, and returns that function's
+// result. It panics if v is not a recognized [SignatureAlgorithm] value.
+func Map_SignatureAlgorithm[T any](
+	v SignatureAlgorithm,
+	caseUNKNOWN_SIGNATURE_ALGORITHM func(SignatureAlgorithm_UNKNOWN_SIGNATURE_ALGORITHM_Case) T,
+	caseED25519 func(SignatureAlgorithm_ED25519_Case) T,
+	caseHMAC_SHA256 func(SignatureAlgorithm_HMAC_SHA256_Case) T,
+) T {
+	switch v {
+	case SignatureAlgorithm_UNKNOWN_SIGNATURE_ALGORITHM:
+		return caseUNKNOWN_SIGNATURE_ALGORITHM(SignatureAlgorithm_UNKNOWN_SIGNATURE_ALGORITHM_Case{})
+	case SignatureAlgorithm_ED25519:
+		return caseED25519(SignatureAlgorithm_ED25519_Case{})
+	case SignatureAlgorithm_HMAC_SHA256:
+		return caseHMAC_SHA256(SignatureAlgorithm_HMAC_SHA256_Case{})
+	default:
+		panic(fmt.Sprintf("Map_SignatureAlgorithm: %d is not a valid SignatureAlgorithm", v))
+	}
+}
+
+type (
+	// EncryptionAlgorithm_UNKNOWN_ENCRYPTION_ALGORITHM_Case is a type that statically associates a function
+	// with a [EncryptionAlgorithm_UNKNOWN_ENCRYPTION_ALGORITHM] value.
+	EncryptionAlgorithm_UNKNOWN_ENCRYPTION_ALGORITHM_Case struct{}
+	// EncryptionAlgorithm_AES_GCM_Case is a type that statically associates a function
+	// with a [EncryptionAlgorithm_AES_GCM] value.
+	EncryptionAlgorithm_AES_GCM_Case struct{}
+)
+
+// Switch_EncryptionAlgorithm dispatches to a function based on the value of v.
+//
+// It invokes the function that corresponds to v. It panics if v is not a
+// recognized [EncryptionAlgorithm] value.
+func Switch_EncryptionAlgorithm(
+	v EncryptionAlgorithm,
+	caseUNKNOWN_ENCRYPTION_ALGORITHM func(EncryptionAlgorithm_UNKNOWN_ENCRYPTION_ALGORITHM_Case),
+	caseAES_GCM func(EncryptionAlgorithm_AES_GCM_Case),
+) {
+	switch v {
+	case EncryptionAlgorithm_UNKNOWN_ENCRYPTION_ALGORITHM:
+		caseUNKNOWN_ENCRYPTION_ALGORITHM(EncryptionAlgorithm_UNKNOWN_ENCRYPTION_ALGORITHM_Case{})
+	case EncryptionAlgorithm_AES_GCM:
+		caseAES_GCM(EncryptionAlgorithm_AES_GCM_Case{})
+	default:
+		panic(fmt.Sprintf("Switch_EncryptionAlgorithm: %d is not a valid EncryptionAlgorithm", v))
+	}
+}
+
+// Map_EncryptionAlgorithm maps a member of the [EncryptionAlgorithm] enumeration to a
+// value of type T.
+//
+// It invokes the function that corresponds to v, and returns that function's
+// result. It panics if v is not a recognized [EncryptionAlgorithm] value.
+func Map_EncryptionAlgorithm[T any](
+	v EncryptionAlgorithm,
+	caseUNKNOWN_ENCRYPTION_ALGORITHM func(EncryptionAlgorithm_UNKNOWN_ENCRYPTION_ALGORITHM_Case) T,
+	caseAES_GCM func(EncryptionAlgorithm_AES_GCM_Case) T,
+) T {
+	switch v {
+	case EncryptionAlgorithm_UNKNOWN_ENCRYPTION_ALGORITHM:
+		return caseUNKNOWN_ENCRYPTION_ALGORITHM(EncryptionAlgorithm_UNKNOWN_ENCRYPTION_ALGORITHM_Case{})
+	case EncryptionAlgorithm_AES_GCM:
+		return caseAES_GCM(EncryptionAlgorithm_AES_GCM_Case{})
+	default:
+		panic(fmt.Sprintf("Map_EncryptionAlgorithm: %d is not a valid EncryptionAlgorithm", v))
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/enginekit/optional"
 	"github.com/dogmatiq/enginekit/protobuf/identitypb"
 	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
 	"google.golang.org/protobuf/proto"
@@ -34,25 +36,66 @@ type Packer struct {
 	// Now is a function used to get the current time. If it is nil, time.Now()
 	// is used.
 	Now func() time.Time
+
+	// Compressor is the (optional) compressor used to compress message data.
+	//
+	// If it is non-nil, message data that is at least CompressionThreshold
+	// bytes (and no more than [MaxUncompressedSize] bytes) is compressed,
+	// provided that doing so reduces its size. The compressed data and the
+	// algorithm are recorded in a [Compression] extension, which [Unpack] uses
+	// to decompress the data.
+	//
+	// The [Message] data of a compressed message is replaced with a
+	// placeholder that can not be unmarshaled, such that readers that predate
+	// the [Compression] extension fail to unpack the message.
+	Compressor Compressor
+
+	// CompressionThreshold is the minimum size of message data, in bytes, that
+	// is compressed when Compressor is non-nil.
+	CompressionThreshold int
+
+	// Encrypter is the (optional) encrypter used to encrypt message data.
+	//
+	// If it is non-nil, message data is encrypted after it is compressed. The
+	// key used to encrypt the data is recorded in an [Encryption] extension.
+	// Use [WithKeyProvider] to supply the keys needed to [Unpack] the message.
+	Encrypter *Encrypter
+
+	// Upcasters is the (optional) registry used to determine the current
+	// schema version of each message type.
+	//
+	// If it is non-nil, messages of a type with a non-zero current schema
+	// version are packed with a [SchemaVersion] extension that records that
+	// version, unless the message already has a [SchemaVersion] extension.
+	// See [Upcasters.SetCurrentVersion].
+	Upcasters *Upcasters
+
+	// Signer is the (optional) signer used to sign envelopes.
+	//
+	// If it is non-nil, each envelope is signed after its message data is
+	// compressed and encrypted. The signature is recorded in a [Signature]
+	// extension, which can be checked using [Envelope.Verify] or
+	// [MultiEnvelope.Verify].
+	Signer Signer
 }
 
 // PackCommand returns an envelope containing the given command.
+//
+// It panics if the command can not be packed. Use [Packer.TryPackCommand] to
+// handle such failures as errors.
 func (p *Packer) PackCommand(m dogma.Command, options ...PackCommandOption) *Envelope {
-	mt, ok := dogma.RegisteredMessageTypeOf(m)
-	if !ok {
-		panic(fmt.Sprintf(
-			"%T is not a registered message type",
-			m,
-		))
-	}
+	return mustPack(p.TryPackCommand(m, options...))
+}
 
-	data, err := m.MarshalBinary()
+// TryPackCommand returns an envelope containing the given command.
+//
+// It returns an [*UnregisteredMessageTypeError], [*MarshalError],
+// [*TransformError] or [*InvalidEnvelopeError] if the command can not be
+// packed.
+func (p *Packer) TryPackCommand(m dogma.Command, options ...PackCommandOption) (*Envelope, error) {
+	message, err := marshalMessage(m)
 	if err != nil {
-		panic(fmt.Sprintf(
-			"unable to marshal %T: %s",
-			m,
-			err,
-		))
+		return nil, err
 	}
 
 	id := p.generateID()
@@ -71,11 +114,7 @@ func (p *Packer) PackCommand(m dogma.Command, options ...PackCommandOption) *Env
 		WithBody(
 			NewBodyBuilder().
 				WithMessageId(id).
-				WithMessage(NewMessageBuilder().
-					WithTypeId(uuidpb.MustParse(mt.ID())).
-					WithDescription(m.MessageDescription()).
-					WithData(data).
-					Build()).
+				WithMessage(message).
 				Build(),
 		).
 		Build()
@@ -94,23 +133,92 @@ func (p *Packer) PackCommand(m dogma.Command, options ...PackCommandOption) *Env
 	env.GetHeader().SetExtensions(nil)
 	env.GetHeader().SetBaggage(nil)
 
+	if err := p.transform(env.GetHeader(), env.GetBody()); err != nil {
+		return nil, &TransformError{reflect.TypeOf(m), message.GetTypeId(), err}
+	}
+
 	if err := env.Validate(); err != nil {
-		panic(err)
+		return nil, &InvalidEnvelopeError{reflect.TypeOf(m), message.GetTypeId(), err}
+	}
+
+	return env, nil
+}
+
+// marshalMessage returns a [Message] containing the binary representation of
+// m.
+func marshalMessage(m dogma.Message) (*Message, error) {
+	mt, ok := dogma.RegisteredMessageTypeOf(m)
+	if !ok {
+		return nil, &UnregisteredMessageTypeError{reflect.TypeOf(m)}
+	}
+
+	typeID := uuidpb.MustParse(mt.ID())
+
+	data, err := m.MarshalBinary()
+	if err != nil {
+		return nil, &MarshalError{reflect.TypeOf(m), typeID, err}
+	}
+
+	return NewMessageBuilder().
+		WithTypeId(typeID).
+		WithDescription(m.MessageDescription()).
+		WithData(data).
+		Build(), nil
+}
+
+// transform records the schema version of, compresses, encrypts and signs the
+// message within body, as configured by the packer.
+func (p *Packer) transform(header *Header, body *Body) error {
+	p.setSchemaVersion(body)
+
+	if err := p.compress(body); err != nil {
+		return err
+	}
+
+	if err := p.encrypt(header, body); err != nil {
+		return err
 	}
 
-	return env
+	return p.sign(header, body)
+}
+
+// setSchemaVersion adds a [SchemaVersion] extension to body if the current
+// schema version of its message type is non-zero.
+func (p *Packer) setSchemaVersion(body *Body) {
+	v := p.Upcasters.currentVersion(body.GetMessage().GetTypeId())
+	if v == 0 {
+		return
+	}
+
+	if _, ok, _ := GetExtension[*SchemaVersion](body); ok {
+		return
+	}
+
+	SetExtension(body, NewSchemaVersionBuilder().WithVersion(v).Build())
 }
 
 // Unpack returns the message contained within an envelope.
 //
 // T may be a message interface such as [dogma.Command] or a concrete message
 // type.
-func Unpack[T dogma.Message](env *Envelope) (T, error) {
+//
+// If the message data has been compressed, as indicated by a [Compression]
+// extension, it is decompressed using the [Compressor] registered for the
+// algorithm. See [RegisterCompressor].
+//
+// If the message data has been encrypted, as indicated by an [Encryption]
+// extension, it is decrypted using the key obtained from the [KeyProvider]
+// given by the [WithKeyProvider] option.
+//
+// If the message type has been retired or its schema has changed, the message
+// data is converted using the [Upcasters] given by the [WithUpcasters] option.
+func Unpack[T dogma.Message](env *Envelope, options ...UnpackOption) (T, error) {
 	var zero T
 
-	message := env.GetBody().GetMessage()
+	opts := newUnpackOptions(options)
 
-	if err := message.validate(); err != nil {
+	typeID, data, err := resolveMessage(env.GetBody(), opts)
+	if err != nil {
 		return zero, fmt.Errorf(
 			"unable to unpack envelope as %s: %w",
 			reflect.TypeFor[T](),
@@ -118,32 +226,29 @@ func Unpack[T dogma.Message](env *Envelope) (T, error) {
 		)
 	}
 
-	mt, ok := dogma.RegisteredMessageTypeByID(message.GetTypeId().AsString())
-	if !ok {
+	m, _, err := newMessage(typeID, opts)
+	if err != nil {
 		return zero, fmt.Errorf(
-			"unable to unpack envelope as %s: %s is not a registered message type ID",
+			"unable to unpack envelope as %s: %w",
 			reflect.TypeFor[T](),
-			message.GetTypeId(),
+			err,
 		)
 	}
 
-	m := mt.New()
-
-	v, ok := any(m).(T)
+	v, ok := m.(T)
 	if !ok {
 		return zero, fmt.Errorf(
 			"unable to unpack envelope as %s: message type %s is registered as %T",
 			reflect.TypeFor[T](),
-			message.GetTypeId(),
+			typeID,
 			m,
 		)
 	}
 
-	if err := m.UnmarshalBinary(message.GetData()); err != nil {
+	if err := unmarshalMessage(m, data); err != nil {
 		return zero, fmt.Errorf(
-			"unable to unpack envelope as %s: unable to unmarshal %T: %w",
+			"unable to unpack envelope as %s: %w",
 			reflect.TypeFor[T](),
-			m,
 			err,
 		)
 	}
@@ -169,6 +274,25 @@ func (p *Packer) generateID() *uuidpb.UUID {
 	return uuidpb.Generate()
 }
 
+// UnpackOption is an option that modifies the behavior of [Unpack].
+type UnpackOption interface {
+	applyUnpackOption(*unpackOptions)
+}
+
+type unpackOptions struct {
+	keys      KeyProvider
+	kind      optional.Optional[message.Kind]
+	upcasters *Upcasters
+}
+
+func newUnpackOptions(options []UnpackOption) unpackOptions {
+	var opts unpackOptions
+	for _, opt := range options {
+		opt.applyUnpackOption(&opts)
+	}
+	return opts
+}
+
 // PackCommandOption is an option that modifies the behavior of
 // [Packer.PackCommand].
 type PackCommandOption interface {
@@ -200,6 +324,7 @@ type PackEffectDeadlineOption interface {
 }
 
 type (
+	unpackOptionFunc             func(*unpackOptions)
 	packEffectsOption            func(*Header)
 	packCommandOptionFunc        func(*Body)
 	packEffectDeadlineOptionFunc func(*Body)
@@ -209,6 +334,7 @@ type (
 	}
 )
 
+func (o unpackOptionFunc) applyUnpackOption(opts *unpackOptions)                { o(opts) }
 func (o packEffectsOption) applyPackEffectsOption(header *Header)               { o(header) }
 func (o packCommandOptionFunc) applyPackCommandOption(env *Envelope)            { o(env.GetBody()) }
 func (o packEffectDeadlineOptionFunc) applyPackEffectDeadlineOption(body *Body) { o(body) }
@@ -218,6 +344,38 @@ func (o universalOption) applyPackEffectEventOption(body *Body)
 func (o universalOption) applyPackEffectDeadlineOption(body *Body)              { o.applyToBodyFunc(body) }
 func (o universalOption) applyPackEffectsOption(header *Header)                 { o.applyToHeaderFunc(header) }
 
+// WithKeyProvider sets the [KeyProvider] used by [Unpack] to obtain the keys
+// needed to decrypt message data.
+func WithKeyProvider(k KeyProvider) UnpackOption {
+	return unpackOptionFunc(
+		func(opts *unpackOptions) {
+			opts.keys = k
+		},
+	)
+}
+
+// WithKind configures [Unpack], [UnpackAny] and [UnpackAll] to reject
+// messages that are not of the given kind, such as a deadline where a command
+// is expected.
+func WithKind(k message.Kind) UnpackOption {
+	return unpackOptionFunc(
+		func(opts *unpackOptions) {
+			opts.kind = optional.Some(k)
+		},
+	)
+}
+
+// WithUpcasters sets the [Upcasters] used by [Unpack], [UnpackAny] and
+// [UnpackAll] to convert messages with retired type IDs or older schema
+// versions.
+func WithUpcasters(r *Upcasters) UnpackOption {
+	return unpackOptionFunc(
+		func(opts *unpackOptions) {
+			opts.upcasters = r
+		},
+	)
+}
+
 // WithIdempotencyKey sets the idempotency key of a command packed via
 // [Packer.PackCommand].
 func WithIdempotencyKey(key string) PackCommandOption {
@@ -0,0 +1,165 @@
+package envelopepb
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"slices"
+
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// RedactedDescription is the description used in place of a message's
+// original description when an envelope is redacted.
+const RedactedDescription = "(redacted)"
+
+// ErrRedacted indicates that an envelope's message data can not be read
+// because the envelope has been redacted.
+var ErrRedacted = errors.New("message data has been redacted")
+
+// Redactor produces copies of envelopes with potentially sensitive information
+// removed, such that they can be included in logs and support exports.
+//
+// Each redacted envelope has its message data removed, its description
+// replaced with [RedactedDescription], and a [Redaction] extension added.
+// Extensions and baggage values are removed unless their type URL is in the
+// relevant allow-list.
+type Redactor struct {
+	// HashData, if true, records the SHA-256 hash of the original message data
+	// in the [Redaction] extension, so that redacted envelopes can be matched
+	// against known data.
+	//
+	// The hash covers the message data as stored in the envelope. If the data
+	// is compressed, the hash is of the compressed data, not of the marshaled
+	// message. Encrypted data is not hashed, as the hash of the ciphertext can
+	// not be matched against known data.
+	//
+	// Note that the hash of low-entropy data, such as a message containing
+	// only an email address, can be reversed by brute force.
+	HashData bool
+
+	// AllowedExtensions is the set of type URLs of the extension values that
+	// are retained, such as "type.googleapis.com/dogma.protobuf.EventStreamPosition".
+	AllowedExtensions []string
+
+	// AllowedBaggage is the set of type URLs of the baggage values that are
+	// retained.
+	AllowedBaggage []string
+}
+
+// Redact returns a redacted copy of env.
+//
+// It returns an error if the redacted envelope is not well-formed, which is
+// only the case if env itself is not well-formed.
+func (r *Redactor) Redact(env *Envelope) (*Envelope, error) {
+	x := NewEnvelopeBuilder().
+		WithHeader(r.redactHeader(env.GetHeader())).
+		WithBody(r.redactBody(env.GetBody())).
+		Build()
+
+	if err := x.Validate(); err != nil {
+		return nil, fmt.Errorf("unable to redact envelope: %w", err)
+	}
+
+	return x, nil
+}
+
+// RedactMulti returns a redacted copy of env.
+//
+// It returns an error if the redacted envelope is not well-formed, which is
+// only the case if env itself is not well-formed.
+func (r *Redactor) RedactMulti(env *MultiEnvelope) (*MultiEnvelope, error) {
+	bodies := make([]*Body, len(env.GetBodies()))
+	for i, body := range env.GetBodies() {
+		bodies[i] = r.redactBody(body)
+	}
+
+	x := NewMultiEnvelopeBuilder().
+		WithHeader(r.redactHeader(env.GetHeader())).
+		WithBodies(bodies).
+		Build()
+
+	if err := x.Validate(); err != nil {
+		return nil, fmt.Errorf("unable to redact envelope: %w", err)
+	}
+
+	return x, nil
+}
+
+func (r *Redactor) redactHeader(header *Header) *Header {
+	if header == nil {
+		return nil
+	}
+
+	return NewHeaderBuilder().
+		From(header).
+		WithExtensions(allowed(header.GetExtensions(), r.AllowedExtensions)).
+		WithBaggage(allowed(header.GetBaggage(), r.AllowedBaggage)).
+		Build()
+}
+
+func (r *Redactor) redactBody(body *Body) *Body {
+	if body == nil {
+		return nil
+	}
+
+	message := body.GetMessage()
+
+	x, ok, _ := GetExtension[*Redaction](body)
+	if !ok {
+		data, _ := storedData(body)
+
+		b := NewRedactionBuilder().
+			WithDataSize(uint64(len(data)))
+
+		if r.HashData && !isEncrypted(body) {
+			hash := sha256.Sum256(data)
+			b.WithDataSha256(hash[:])
+		}
+
+		x = b.Build()
+	}
+
+	if message != nil {
+		message = NewMessageBuilder().
+			From(message).
+			WithDescription(RedactedDescription).
+			WithData(nil).
+			Build()
+	}
+
+	redacted := NewBodyBuilder().
+		From(body).
+		WithMessage(message).
+		WithExtensions(allowed(body.GetExtensions(), r.AllowedExtensions)).
+		WithBaggage(allowed(body.GetBaggage(), r.AllowedBaggage)).
+		Build()
+
+	// Compressed data is stored in the compression extension, which must not
+	// retain the data even if the extension itself is allowed.
+	if c, ok, _ := GetExtension[*Compression](redacted); ok {
+		c.SetData(nil)
+		SetExtension(redacted, c)
+	}
+
+	SetExtension(redacted, x)
+
+	return redacted
+}
+
+// isEncrypted returns true if the message data within body is encrypted.
+func isEncrypted(body *Body) bool {
+	_, ok, _ := GetExtension[*Encryption](body)
+	return ok
+}
+
+// allowed returns the values whose type URL is in typeURLs.
+func allowed(values []*anypb.Any, typeURLs []string) []*anypb.Any {
+	var result []*anypb.Any
+	for _, v := range values {
+		if slices.Contains(typeURLs, v.GetTypeUrl()) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
@@ -0,0 +1,245 @@
+package envelopepb_test
+
+import (
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	. "github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestRedactor(t *testing.T) {
+	packer := &Packer{
+		Application: identitypb.New("app", uuidpb.Generate()),
+	}
+
+	position := NewEventStreamPositionBuilder().
+		WithStreamId(uuidpb.Generate()).
+		WithOffset(123).
+		Build()
+
+	env := packer.PackCommand(
+		CommandA1,
+		WithExtension(position),
+		WithExtension(wrapperspb.String("<extension>")),
+		WithBaggage(wrapperspb.String("<baggage>")),
+		WithBaggage(wrapperspb.Int64(42)),
+	)
+
+	typeURL := func(v *anypb.Any) string {
+		return v.GetTypeUrl()
+	}
+
+	t.Run("it replaces the message data and description", func(t *testing.T) {
+		r := &Redactor{}
+
+		x, err := r.Redact(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected description", x.GetBody().GetMessage().GetDescription(), RedactedDescription)
+		Expect(t, "unexpected data", len(x.GetBody().GetMessage().GetData()), 0)
+		Expect(t, "unexpected message ID", x.GetBody().GetMessageId(), env.GetBody().GetMessageId())
+
+		redaction, ok, err := GetExtension[*Redaction](x.GetBody())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected a redaction extension")
+		}
+
+		Expect(t, "unexpected data size", redaction.GetDataSize(), uint64(len(env.GetBody().GetMessage().GetData())))
+		Expect(t, "unexpected data hash", len(redaction.GetDataSha256()), 0)
+	})
+
+	t.Run("it does not modify the original envelope", func(t *testing.T) {
+		r := &Redactor{}
+
+		if _, err := r.Redact(env); err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected description", env.GetBody().GetMessage().GetDescription(), CommandA1.MessageDescription())
+	})
+
+	t.Run("it hashes the message data", func(t *testing.T) {
+		r := &Redactor{HashData: true}
+
+		x, err := r.Redact(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		redaction, _, err := GetExtension[*Redaction](x.GetBody())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hash := sha256.Sum256(env.GetBody().GetMessage().GetData())
+		Expect(t, "unexpected data hash", redaction.GetDataSha256(), hash[:])
+	})
+
+	t.Run("it does not hash encrypted message data", func(t *testing.T) {
+		r := &Redactor{HashData: true}
+
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Encrypter:   &Encrypter{Keys: &MemoryKeyProvider{}},
+		}
+
+		env := packer.PackCommand(CommandA1)
+
+		x, err := r.Redact(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		redaction, _, err := GetExtension[*Redaction](x.GetBody())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected data size", redaction.GetDataSize(), uint64(len(env.GetBody().GetMessage().GetData())))
+		Expect(t, "unexpected data hash", len(redaction.GetDataSha256()), 0)
+	})
+
+	t.Run("it removes compressed message data", func(t *testing.T) {
+		r := &Redactor{
+			HashData:          true,
+			AllowedExtensions: []string{"type.googleapis.com/dogma.protobuf.Compression"},
+		}
+
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Compressor:  GzipCompressor,
+		}
+
+		env := packer.PackCommand(&CommandStub[TypeA]{
+			Content: TypeA(strings.Repeat("<content>", 100)),
+		})
+
+		original, ok, err := GetExtension[*Compression](env.GetBody())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected a compression extension")
+		}
+
+		x, err := r.Redact(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		compression, _, err := GetExtension[*Compression](x.GetBody())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected compressed data", len(compression.GetData()), 0)
+
+		redaction, _, err := GetExtension[*Redaction](x.GetBody())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		hash := sha256.Sum256(original.GetData())
+		Expect(t, "unexpected data size", redaction.GetDataSize(), uint64(len(original.GetData())))
+		Expect(t, "unexpected data hash", redaction.GetDataSha256(), hash[:])
+	})
+
+	t.Run("it retains allowed extensions and baggage", func(t *testing.T) {
+		r := &Redactor{
+			AllowedExtensions: []string{typeURL(env.GetBody().GetExtensions()[0])},
+			AllowedBaggage:    []string{typeURL(env.GetBody().GetBaggage()[1])},
+		}
+
+		x, err := r.Redact(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, ok, _ := GetExtension[*EventStreamPosition](x.GetBody()); !ok {
+			t.Fatal("expected the event stream position extension to be retained")
+		}
+
+		if _, ok, _ := GetExtension[*wrapperspb.StringValue](x.GetBody()); ok {
+			t.Fatal("expected the string extension to be removed")
+		}
+
+		if _, ok, _ := GetBaggage[*wrapperspb.StringValue](x.GetBody()); ok {
+			t.Fatal("expected the string baggage to be removed")
+		}
+
+		if _, ok, _ := GetBaggage[*wrapperspb.Int64Value](x.GetBody()); !ok {
+			t.Fatal("expected the integer baggage to be retained")
+		}
+	})
+
+	t.Run("it preserves the original redaction extension when redacted twice", func(t *testing.T) {
+		r := &Redactor{HashData: true}
+
+		once, err := r.Redact(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		twice, err := r.Redact(once)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected envelope", twice, once)
+	})
+
+	t.Run("it prevents the message from being unpacked", func(t *testing.T) {
+		r := &Redactor{}
+
+		x, err := r.Redact(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, _, err := UnpackAny(x); !errors.Is(err, ErrRedacted) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("it returns an error if the envelope is invalid", func(t *testing.T) {
+		r := &Redactor{}
+
+		_, err := r.Redact(&Envelope{})
+
+		Expect(t, "unexpected error message", err.Error(), "unable to redact envelope: invalid header: must not be nil")
+	})
+
+	t.Run("func RedactMulti()", func(t *testing.T) {
+		t.Run("it redacts each body", func(t *testing.T) {
+			effects := packer.PackEffects(env, identitypb.New("handler", uuidpb.Generate()))
+			effects.PackEvent(EventA1)
+			effects.PackEvent(EventA2)
+			multi, _ := effects.Seal()
+
+			r := &Redactor{}
+
+			x, err := r.RedactMulti(multi)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, body := range x.GetBodies() {
+				Expect(t, "unexpected description", body.GetMessage().GetDescription(), RedactedDescription)
+				Expect(t, "unexpected message ID", body.GetMessageId(), multi.GetBodies()[i].GetMessageId())
+			}
+		})
+	})
+}
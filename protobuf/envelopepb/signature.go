@@ -0,0 +1,345 @@
+package envelopepb
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// A Signer produces signatures of envelope content using a specific key.
+type Signer interface {
+	// Algorithm returns the algorithm used to produce signatures.
+	Algorithm() SignatureAlgorithm
+
+	// KeyID returns the identifier of the key used to produce signatures.
+	KeyID() string
+
+	// Sign returns the signature of data.
+	Sign(data []byte) ([]byte, error)
+}
+
+// A Verifier verifies signatures produced by a [Signer].
+type Verifier interface {
+	// Algorithm returns the algorithm used to produce the signatures that the
+	// verifier accepts.
+	Algorithm() SignatureAlgorithm
+
+	// Verify returns true if signature is a valid signature of data.
+	Verify(data, signature []byte) bool
+}
+
+var (
+	// ErrNotSigned indicates that an envelope does not have a [Signature]
+	// extension.
+	ErrNotSigned = errors.New("envelope is not signed")
+
+	// ErrUnknownKey indicates that an envelope was signed using a key that is
+	// not present in the [Keyring] used to verify it.
+	ErrUnknownKey = errors.New("key is not recognized")
+
+	// ErrSignatureMismatch indicates that an envelope's signature does not
+	// match its content.
+	ErrSignatureMismatch = errors.New("signature does not match envelope content")
+)
+
+// NewEd25519Signer returns a [Signer] that signs envelopes using
+// [SignatureAlgorithm_ED25519] with the given private key.
+func NewEd25519Signer(keyID string, key ed25519.PrivateKey) Signer {
+	if len(key) != ed25519.PrivateKeySize {
+		panic(fmt.Sprintf("private key must be %d bytes", ed25519.PrivateKeySize))
+	}
+	return ed25519Signer{keyID, key}
+}
+
+// NewEd25519Verifier returns a [Verifier] that verifies signatures produced
+// using [SignatureAlgorithm_ED25519] with the private key that corresponds to
+// the given public key.
+func NewEd25519Verifier(key ed25519.PublicKey) Verifier {
+	if len(key) != ed25519.PublicKeySize {
+		panic(fmt.Sprintf("public key must be %d bytes", ed25519.PublicKeySize))
+	}
+	return ed25519Verifier{key}
+}
+
+// NewHMACSigner returns a [Signer] that signs envelopes using
+// [SignatureAlgorithm_HMAC_SHA256] with the given shared secret.
+func NewHMACSigner(keyID string, secret []byte) Signer {
+	if len(secret) == 0 {
+		panic("secret must not be empty")
+	}
+	return hmacSigner{keyID, secret}
+}
+
+// NewHMACVerifier returns a [Verifier] that verifies signatures produced using
+// [SignatureAlgorithm_HMAC_SHA256] with the given shared secret.
+func NewHMACVerifier(secret []byte) Verifier {
+	if len(secret) == 0 {
+		panic("secret must not be empty")
+	}
+	return hmacSigner{"", secret}
+}
+
+// A Keyring is a set of [Verifier] values, each associated with the ID of the
+// key that it verifies.
+//
+// Keys are rotated by adding the new key to the keyring before any envelopes
+// are signed with it, and removing the old key once envelopes signed with it
+// no longer need to be verified.
+//
+// It is safe for concurrent use. The zero-value is an empty keyring.
+type Keyring struct {
+	m         sync.RWMutex
+	verifiers map[string]Verifier
+}
+
+// Add adds v to the keyring as the verifier for the key with the given ID,
+// replacing any existing verifier for that key.
+func (k *Keyring) Add(keyID string, v Verifier) {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	if k.verifiers == nil {
+		k.verifiers = map[string]Verifier{}
+	}
+
+	k.verifiers[keyID] = v
+}
+
+// Remove removes the verifier for the key with the given ID, if present.
+func (k *Keyring) Remove(keyID string) {
+	k.m.Lock()
+	defer k.m.Unlock()
+
+	delete(k.verifiers, keyID)
+}
+
+// Lookup returns the verifier for the key with the given ID.
+func (k *Keyring) Lookup(keyID string) (Verifier, bool) {
+	k.m.RLock()
+	defer k.m.RUnlock()
+
+	v, ok := k.verifiers[keyID]
+	return v, ok
+}
+
+// CanonicalEncoding returns the encoding of header and body that is used to
+// produce and verify envelope signatures.
+//
+// It is the deterministic binary encoding of an [Envelope] containing header
+// and body, excluding any [Signature] extension within body. Because each body
+// within a [MultiEnvelope] is signed together with the shared header, each of
+// the envelopes produced by [MultiEnvelope.All] can be verified independently.
+func CanonicalEncoding(header *Header, body *Body) ([]byte, error) {
+	env := NewEnvelopeBuilder().
+		WithHeader(header).
+		WithBody(
+			NewBodyBuilder().
+				From(body).
+				WithExtensions(withoutSignature(body.GetExtensions())).
+				Build(),
+		).
+		Build()
+
+	return proto.MarshalOptions{Deterministic: true}.Marshal(env)
+}
+
+// Sign adds a [Signature] extension to x, replacing any existing signature.
+func (x *Envelope) Sign(s Signer) error {
+	return sign(x.GetHeader(), x.GetBody(), s)
+}
+
+// Sign adds a [Signature] extension to each body within x, replacing any
+// existing signatures.
+func (x *MultiEnvelope) Sign(s Signer) error {
+	for i, b := range x.GetBodies() {
+		if err := sign(x.GetHeader(), b, s); err != nil {
+			return fmt.Errorf("unable to sign body at index %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// Verify returns an error if x is not well-formed, or if its signature can not
+// be verified using the keys in k.
+//
+// The returned error describes which part of the envelope failed verification.
+// Signature failures wrap [ErrNotSigned], [ErrUnknownKey] or
+// [ErrSignatureMismatch].
+func (x *Envelope) Verify(k *Keyring) error {
+	if err := x.Validate(); err != nil {
+		return err
+	}
+
+	if err := verify(x.GetHeader(), x.GetBody(), k); err != nil {
+		return fmt.Errorf("invalid body: %w", err)
+	}
+
+	return nil
+}
+
+// Verify returns an error if x is not well-formed, or if the signature of any
+// of its bodies can not be verified using the keys in k.
+//
+// The returned error describes which part of the envelope failed verification.
+// Signature failures wrap [ErrNotSigned], [ErrUnknownKey] or
+// [ErrSignatureMismatch].
+func (x *MultiEnvelope) Verify(k *Keyring) error {
+	if err := x.Validate(); err != nil {
+		return err
+	}
+
+	for i, b := range x.GetBodies() {
+		if err := verify(x.GetHeader(), b, k); err != nil {
+			return fmt.Errorf("invalid body at index %d: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// sign adds a [Signature] extension to body.
+func sign(header *Header, body *Body, s Signer) error {
+	data, err := CanonicalEncoding(header, body)
+	if err != nil {
+		return fmt.Errorf("unable to encode envelope: %w", err)
+	}
+
+	sig, err := s.Sign(data)
+	if err != nil {
+		return fmt.Errorf("unable to sign envelope using %s key %q: %w", s.Algorithm(), s.KeyID(), err)
+	}
+
+	SetExtension(
+		body,
+		NewSignatureBuilder().
+			WithAlgorithm(s.Algorithm()).
+			WithKeyId(s.KeyID()).
+			WithSignature(sig).
+			Build(),
+	)
+
+	return nil
+}
+
+// verify returns an error if the signature of body can not be verified.
+func verify(header *Header, body *Body, k *Keyring) error {
+	sig, ok, err := GetExtension[*Signature](body)
+	if err != nil {
+		return fmt.Errorf("invalid signature: unable to unmarshal signature extension: %w", err)
+	}
+	if !ok {
+		return fmt.Errorf("invalid signature: %w", ErrNotSigned)
+	}
+
+	v, ok := k.Lookup(sig.GetKeyId())
+	if !ok {
+		return fmt.Errorf("invalid signature: %s key %q: %w", sig.GetAlgorithm(), sig.GetKeyId(), ErrUnknownKey)
+	}
+
+	if v.Algorithm() != sig.GetAlgorithm() {
+		return fmt.Errorf(
+			"invalid signature: key %q is for %s, but the signature uses %s: %w",
+			sig.GetKeyId(),
+			v.Algorithm(),
+			sig.GetAlgorithm(),
+			ErrSignatureMismatch,
+		)
+	}
+
+	data, err := CanonicalEncoding(header, body)
+	if err != nil {
+		return fmt.Errorf("invalid signature: unable to encode envelope: %w", err)
+	}
+
+	if !v.Verify(data, sig.GetSignature()) {
+		return fmt.Errorf("invalid signature: %s key %q: %w", sig.GetAlgorithm(), sig.GetKeyId(), ErrSignatureMismatch)
+	}
+
+	return nil
+}
+
+// sign adds a [Signature] extension to body if the packer has a signer.
+func (p *Packer) sign(header *Header, body *Body) error {
+	if p.Signer == nil {
+		return nil
+	}
+
+	return sign(header, body, p.Signer)
+}
+
+// withoutSignature returns a copy of values with any [Signature] extension
+// removed.
+func withoutSignature(values []*anypb.Any) []*anypb.Any {
+	var result []*anypb.Any
+
+	for _, v := range values {
+		if !v.MessageIs((*Signature)(nil)) {
+			result = append(result, v)
+		}
+	}
+
+	return result
+}
+
+type ed25519Signer struct {
+	keyID string
+	key   ed25519.PrivateKey
+}
+
+func (s ed25519Signer) Algorithm() SignatureAlgorithm {
+	return SignatureAlgorithm_ED25519
+}
+
+func (s ed25519Signer) KeyID() string {
+	return s.keyID
+}
+
+func (s ed25519Signer) Sign(data []byte) ([]byte, error) {
+	return ed25519.Sign(s.key, data), nil
+}
+
+type ed25519Verifier struct {
+	key ed25519.PublicKey
+}
+
+func (v ed25519Verifier) Algorithm() SignatureAlgorithm {
+	return SignatureAlgorithm_ED25519
+}
+
+func (v ed25519Verifier) Verify(data, signature []byte) bool {
+	return ed25519.Verify(v.key, data, signature)
+}
+
+type hmacSigner struct {
+	keyID  string
+	secret []byte
+}
+
+func (s hmacSigner) Algorithm() SignatureAlgorithm {
+	return SignatureAlgorithm_HMAC_SHA256
+}
+
+func (s hmacSigner) KeyID() string {
+	return s.keyID
+}
+
+func (s hmacSigner) Sign(data []byte) ([]byte, error) {
+	return s.sum(data), nil
+}
+
+func (s hmacSigner) Verify(data, signature []byte) bool {
+	return hmac.Equal(s.sum(data), signature)
+}
+
+func (s hmacSigner) sum(data []byte) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write(data)
+	return h.Sum(nil)
+}
@@ -0,0 +1,173 @@
+package envelopepb_test
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+func TestSignature(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	secret := []byte("<secret>")
+
+	cases := []struct {
+		Name     string
+		Signer   Signer
+		Verifier Verifier
+	}{
+		{"ED25519", NewEd25519Signer("<ed25519-key>", priv), NewEd25519Verifier(pub)},
+		{"HMAC_SHA256", NewHMACSigner("<hmac-key>", secret), NewHMACVerifier(secret)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			keys := &Keyring{}
+			keys.Add(c.Signer.KeyID(), c.Verifier)
+
+			packer := &Packer{
+				Application: identitypb.New("app", uuidpb.Generate()),
+				Signer:      c.Signer,
+			}
+
+			t.Run("it signs envelopes when they are packed", func(t *testing.T) {
+				env := packer.PackCommand(CommandA1)
+
+				if err := env.Verify(keys); err != nil {
+					t.Fatal(err)
+				}
+			})
+
+			t.Run("it signs each body in a multi-envelope", func(t *testing.T) {
+				cause := packer.PackCommand(CommandA1)
+				effects := packer.PackEffects(cause, identitypb.New("handler", uuidpb.Generate()))
+				effects.PackEvent(EventA1)
+				effects.PackEvent(EventA2)
+
+				multi, _ := effects.Seal()
+
+				if err := multi.Verify(keys); err != nil {
+					t.Fatal(err)
+				}
+
+				for env := range multi.All() {
+					if err := env.Verify(keys); err != nil {
+						t.Fatal(err)
+					}
+				}
+			})
+
+			t.Run("it detects changes to the header", func(t *testing.T) {
+				env := packer.PackCommand(CommandA1)
+				env.GetHeader().SetCorrelationId(uuidpb.Generate())
+
+				err := env.Verify(keys)
+				if !errors.Is(err, ErrSignatureMismatch) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			})
+
+			t.Run("it detects changes to the body", func(t *testing.T) {
+				cause := packer.PackCommand(CommandA1)
+				effects := packer.PackEffects(cause, identitypb.New("handler", uuidpb.Generate()))
+				effects.PackEvent(EventA1)
+				effects.PackEvent(EventA2)
+
+				multi, _ := effects.Seal()
+				multi.GetBodies()[1].GetMessage().SetData([]byte(`{"content":"<tampered>"}`))
+
+				err := multi.Verify(keys)
+				if !errors.Is(err, ErrSignatureMismatch) {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				if !strings.HasPrefix(err.Error(), "invalid body at index 1: invalid signature:") {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			})
+		})
+	}
+
+	t.Run("it supports key rotation", func(t *testing.T) {
+		oldSigner := NewHMACSigner("<old>", []byte("<old-secret>"))
+		newSigner := NewHMACSigner("<new>", []byte("<new-secret>"))
+
+		keys := &Keyring{}
+		keys.Add("<old>", NewHMACVerifier([]byte("<old-secret>")))
+
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Signer:      oldSigner,
+		}
+
+		old := packer.PackCommand(CommandA1)
+
+		keys.Add("<new>", NewHMACVerifier([]byte("<new-secret>")))
+		packer.Signer = newSigner
+
+		current := packer.PackCommand(CommandA1)
+
+		if err := old.Verify(keys); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := current.Verify(keys); err != nil {
+			t.Fatal(err)
+		}
+
+		keys.Remove("<old>")
+
+		if err := old.Verify(keys); !errors.Is(err, ErrUnknownKey) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if err := current.Verify(keys); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("it returns an error if the envelope is not signed", func(t *testing.T) {
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+		}
+
+		env := packer.PackCommand(CommandA1)
+
+		if err := env.Verify(&Keyring{}); !errors.Is(err, ErrNotSigned) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("it returns an error if the key uses a different algorithm", func(t *testing.T) {
+		keys := &Keyring{}
+		keys.Add("<key>", NewEd25519Verifier(pub))
+
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Signer:      NewHMACSigner("<key>", secret),
+		}
+
+		env := packer.PackCommand(CommandA1)
+
+		err := env.Verify(keys)
+		if err == nil || err.Error() != `invalid body: invalid signature: key "<key>" is for ED25519, but the signature uses HMAC_SHA256: signature does not match envelope content` {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("it returns an error if the envelope is not well-formed", func(t *testing.T) {
+		err := (&Envelope{}).Verify(&Keyring{})
+		if err == nil || !strings.HasPrefix(err.Error(), "invalid header:") {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package envelopepb
+
+import (
+	"context"
+	"maps"
+	"slices"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracePropagator is the propagator used to inject and extract the trace
+// context carried by an envelope's [TraceContext] baggage.
+var TracePropagator propagation.TextMapPropagator = propagation.TraceContext{}
+
+var _ propagation.TextMapCarrier = (*TraceContext)(nil)
+
+// Get returns the value associated with the given key.
+//
+// It allows [*TraceContext] to implement [propagation.TextMapCarrier].
+func (x *TraceContext) Get(key string) string {
+	return x.GetFields()[key]
+}
+
+// Set stores a key/value pair.
+//
+// It allows [*TraceContext] to implement [propagation.TextMapCarrier].
+func (x *TraceContext) Set(key, value string) {
+	fields := x.GetFields()
+	if fields == nil {
+		fields = map[string]string{}
+		x.SetFields(fields)
+	}
+	fields[key] = value
+}
+
+// Keys returns the keys for which values are stored, in sorted order.
+//
+// It allows [*TraceContext] to implement [propagation.TextMapCarrier].
+func (x *TraceContext) Keys() []string {
+	return slices.Sorted(maps.Keys(x.GetFields()))
+}
+
+// WithTraceContext adds the span context within ctx to the envelope's baggage
+// as a [TraceContext] value, using [TracePropagator].
+//
+// [Packer.PackCommandContext] and [Packer.PackEffectsContext] apply this option
+// automatically.
+//
+// Because baggage is inherited by downstream messages, messages packed via
+// [Packer.PackEffects] carry the trace context of their cause unless this
+// option is used to replace it with the span context of the handler.
+//
+// It has no effect if ctx does not contain a valid span context.
+func WithTraceContext(ctx context.Context) interface {
+	PackCommandOption
+	PackEffectsOption
+	PackEffectCommandOption
+	PackEffectEventOption
+	PackEffectDeadlineOption
+} {
+	x := &TraceContext{}
+
+	if trace.SpanContextFromContext(ctx).IsValid() {
+		TracePropagator.Inject(ctx, x)
+	}
+
+	if len(x.GetFields()) == 0 {
+		return universalOption{
+			applyToBodyFunc:   func(*Body) {},
+			applyToHeaderFunc: func(*Header) {},
+		}
+	}
+
+	return WithBaggage(x)
+}
+
+// PackCommandContext returns an envelope containing the given command, carrying
+// the span context within ctx as per [WithTraceContext].
+//
+// It panics if the command can not be packed. Use
+// [Packer.TryPackCommandContext] to handle such failures as errors.
+func (p *Packer) PackCommandContext(
+	ctx context.Context,
+	m dogma.Command,
+	options ...PackCommandOption,
+) *Envelope {
+	return mustPack(p.TryPackCommandContext(ctx, m, options...))
+}
+
+// TryPackCommandContext returns an envelope containing the given command,
+// carrying the span context within ctx as per [WithTraceContext].
+//
+// It returns the same errors as [Packer.TryPackCommand].
+func (p *Packer) TryPackCommandContext(
+	ctx context.Context,
+	m dogma.Command,
+	options ...PackCommandOption,
+) (*Envelope, error) {
+	options = append([]PackCommandOption{WithTraceContext(ctx)}, options...)
+	return p.TryPackCommand(m, options...)
+}
+
+// PackEffectsContext returns an [EffectPacker] that packs messages produced by
+// h while handling cause, carrying the span context within ctx as per
+// [WithTraceContext].
+//
+// If ctx does not contain a valid span context, the messages carry the trace
+// context of cause.
+//
+// It panics if cause is not well-formed. Use [Packer.TryPackEffectsContext] to
+// handle such failures as errors.
+func (p *Packer) PackEffectsContext(
+	ctx context.Context,
+	cause *Envelope,
+	h *identitypb.Identity,
+	options ...PackEffectsOption,
+) *EffectPacker {
+	e, err := p.TryPackEffectsContext(ctx, cause, h, options...)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// TryPackEffectsContext returns an [EffectPacker] that packs messages produced
+// by h while handling cause, carrying the span context within ctx as per
+// [WithTraceContext].
+//
+// It returns the same errors as [Packer.TryPackEffects].
+func (p *Packer) TryPackEffectsContext(
+	ctx context.Context,
+	cause *Envelope,
+	h *identitypb.Identity,
+	options ...PackEffectsOption,
+) (*EffectPacker, error) {
+	options = append([]PackEffectsOption{WithTraceContext(ctx)}, options...)
+	return p.TryPackEffects(cause, h, options...)
+}
+
+// ExtractTraceContext returns a copy of ctx that contains the remote span
+// context carried by env, such that spans started from the returned context
+// are children of the span that produced env.
+//
+// If env does not carry a trace context, ctx is returned unchanged.
+func ExtractTraceContext(ctx context.Context, env *Envelope) context.Context {
+	x, ok := traceContext(env)
+	if !ok {
+		return ctx
+	}
+
+	return TracePropagator.Extract(ctx, x)
+}
+
+// TraceLink returns a link to the span that produced env, for use when
+// starting a span that is related to, but not a child of, that span.
+//
+// ok is false if env does not carry a valid trace context.
+func TraceLink(env *Envelope) (link trace.Link, ok bool) {
+	ctx := ExtractTraceContext(context.Background(), env)
+	sc := trace.SpanContextFromContext(ctx)
+
+	if !sc.IsValid() {
+		return trace.Link{}, false
+	}
+
+	return trace.Link{SpanContext: sc}, true
+}
+
+// traceContext returns the [TraceContext] baggage value carried by env.
+func traceContext(env *Envelope) (*TraceContext, bool) {
+	if x, ok, err := GetBaggage[*TraceContext](env.GetBody()); ok && err == nil {
+		return x, true
+	}
+
+	x := &TraceContext{}
+	for _, v := range env.GetHeader().GetBaggage() {
+		if v.MessageIs(x) && v.UnmarshalTo(x) == nil {
+			return x, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,132 @@
+package envelopepb_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	. "github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceContext(t *testing.T) {
+	packer := &Packer{
+		Application: identitypb.New("app", uuidpb.Generate()),
+	}
+
+	newContext := func(traceID trace.TraceID, spanID trace.SpanID) context.Context {
+		return trace.ContextWithSpanContext(
+			context.Background(),
+			trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID:    traceID,
+				SpanID:     spanID,
+				TraceFlags: trace.FlagsSampled,
+			}),
+		)
+	}
+
+	traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	commandSpanID := trace.SpanID{1, 1, 1, 1, 1, 1, 1, 1}
+	handlerSpanID := trace.SpanID{2, 2, 2, 2, 2, 2, 2, 2}
+
+	t.Run("it propagates the span context from a command", func(t *testing.T) {
+		env := packer.PackCommand(
+			CommandA1,
+			WithTraceContext(newContext(traceID, commandSpanID)),
+		)
+
+		x, ok, err := GetBaggage[*TraceContext](env.GetBody())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected trace context baggage to be present")
+		}
+
+		Expect(t, "unexpected keys", x.Keys(), []string{"traceparent"})
+
+		sc := trace.SpanContextFromContext(ExtractTraceContext(context.Background(), env))
+
+		Expect(t, "unexpected trace ID", sc.TraceID(), traceID)
+		Expect(t, "unexpected span ID", sc.SpanID(), commandSpanID)
+
+		if !sc.IsRemote() {
+			t.Fatal("expected span context to be remote")
+		}
+	})
+
+	t.Run("it propagates the span context when packing with a context", func(t *testing.T) {
+		command := packer.PackCommandContext(newContext(traceID, commandSpanID), CommandA1)
+
+		ctx := ExtractTraceContext(context.Background(), command)
+		effects := packer.PackEffectsContext(ctx, command, identitypb.New("handler", uuidpb.Generate()))
+		event := effects.PackEvent(EventA1)
+
+		for _, env := range []*Envelope{command, event} {
+			link, ok := TraceLink(env)
+			if !ok {
+				t.Fatal("expected a trace link")
+			}
+
+			Expect(t, "unexpected trace ID", link.SpanContext.TraceID(), traceID)
+		}
+	})
+
+	t.Run("it propagates the span context to effects", func(t *testing.T) {
+		cause := packer.PackCommand(
+			CommandA1,
+			WithTraceContext(newContext(traceID, commandSpanID)),
+		)
+
+		t.Run("it inherits the span context of the cause", func(t *testing.T) {
+			effects := packer.PackEffects(cause, identitypb.New("handler", uuidpb.Generate()))
+			env := effects.PackEvent(EventA1)
+
+			sc := trace.SpanContextFromContext(ExtractTraceContext(context.Background(), env))
+			Expect(t, "unexpected span ID", sc.SpanID(), commandSpanID)
+		})
+
+		t.Run("it uses the span context of the handler", func(t *testing.T) {
+			effects := packer.PackEffects(
+				cause,
+				identitypb.New("handler", uuidpb.Generate()),
+				WithTraceContext(newContext(traceID, handlerSpanID)),
+			)
+			env := effects.PackEvent(EventA1)
+
+			sc := trace.SpanContextFromContext(ExtractTraceContext(context.Background(), env))
+			Expect(t, "unexpected trace ID", sc.TraceID(), traceID)
+			Expect(t, "unexpected span ID", sc.SpanID(), handlerSpanID)
+
+			link, ok := TraceLink(env)
+			if !ok {
+				t.Fatal("expected a link")
+			}
+
+			Expect(t, "unexpected link", link.SpanContext.SpanID(), handlerSpanID)
+		})
+	})
+
+	t.Run("it does not add baggage if there is no span context", func(t *testing.T) {
+		env := packer.PackCommand(
+			CommandA1,
+			WithTraceContext(context.Background()),
+		)
+
+		if _, ok, _ := GetBaggage[*TraceContext](env.GetBody()); ok {
+			t.Fatal("did not expect trace context baggage to be present")
+		}
+
+		ctx := context.Background()
+		if ExtractTraceContext(ctx, env) != ctx {
+			t.Fatal("expected context to be unchanged")
+		}
+
+		if _, ok := TraceLink(env); ok {
+			t.Fatal("did not expect a link")
+		}
+	})
+}
@@ -0,0 +1,141 @@
+package envelopepb
+
+import (
+	"fmt"
+	"iter"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+// UnpackAny returns the message contained within env, along with its type.
+//
+// It is equivalent to [Unpack], but for use when the message type is not
+// known statically.
+func UnpackAny(env *Envelope, options ...UnpackOption) (dogma.Message, message.Type, error) {
+	m, t, err := unpackAny(env.GetBody(), newUnpackOptions(options))
+	if err != nil {
+		return nil, message.Type{}, fmt.Errorf("unable to unpack envelope: %w", err)
+	}
+
+	return m, t, nil
+}
+
+// UnpackedMessage is a message unpacked from a [MultiEnvelope] by
+// [UnpackAll].
+type UnpackedMessage struct {
+	// Body is the envelope body that contained the message.
+	Body *Body
+
+	// Message is the unpacked message.
+	Message dogma.Message
+
+	// Type is the message's type.
+	Type message.Type
+}
+
+// UnpackAll returns an iterator that yields each message within env, in the
+// order of env's bodies.
+//
+// If a message can not be unpacked, the iterator yields an error that
+// identifies the index of the body that failed. Iteration continues with the
+// next body unless the caller stops the iteration.
+func UnpackAll(env *MultiEnvelope, options ...UnpackOption) iter.Seq2[UnpackedMessage, error] {
+	opts := newUnpackOptions(options)
+
+	return func(yield func(UnpackedMessage, error) bool) {
+		for i, b := range env.GetBodies() {
+			m, t, err := unpackAny(b, opts)
+			if err != nil {
+				err = fmt.Errorf("unable to unpack body at index %d: %w", i, err)
+			}
+
+			if !yield(UnpackedMessage{b, m, t}, err) {
+				return
+			}
+		}
+	}
+}
+
+// unpackAny returns the message contained within body.
+func unpackAny(body *Body, opts unpackOptions) (dogma.Message, message.Type, error) {
+	typeID, data, err := resolveMessage(body, opts)
+	if err != nil {
+		return nil, message.Type{}, err
+	}
+
+	m, t, err := newMessage(typeID, opts)
+	if err != nil {
+		return nil, message.Type{}, err
+	}
+
+	if err := unmarshalMessage(m, data); err != nil {
+		return nil, message.Type{}, err
+	}
+
+	return m, t, nil
+}
+
+// resolveMessage returns the type ID and data of the message within body,
+// after decrypting and decompressing the data and applying any upcasters.
+func resolveMessage(body *Body, opts unpackOptions) (*uuidpb.UUID, []byte, error) {
+	msg := body.GetMessage()
+
+	if err := msg.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	data, err := messageData(body, opts.keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if opts.upcasters == nil {
+		return msg.GetTypeId(), data, nil
+	}
+
+	return opts.upcasters.upcast(body, data)
+}
+
+// newMessage returns a new zero-value message of the type identified by
+// typeID.
+func newMessage(typeID *uuidpb.UUID, opts unpackOptions) (dogma.Message, message.Type, error) {
+	mt, ok := dogma.RegisteredMessageTypeByID(typeID.AsString())
+	if !ok {
+		return nil, message.Type{}, fmt.Errorf(
+			"%s is not a registered message type ID",
+			typeID,
+		)
+	}
+
+	t := message.TypeFromReflect(mt.GoType())
+
+	if k, ok := opts.kind.TryGet(); ok && t.Kind() != k {
+		return nil, message.Type{}, fmt.Errorf(
+			"message type %s is %s, expected %s",
+			typeID,
+			withArticle(t.Kind()),
+			withArticle(k),
+		)
+	}
+
+	return mt.New(), t, nil
+}
+
+// unmarshalMessage populates m from data.
+func unmarshalMessage(m dogma.Message, data []byte) error {
+	if err := m.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("unable to unmarshal %T: %w", m, err)
+	}
+
+	return nil
+}
+
+// withArticle returns the name of k with an indefinite article.
+func withArticle(k message.Kind) string {
+	if k == message.EventKind {
+		return "an " + k.String()
+	}
+	return "a " + k.String()
+}
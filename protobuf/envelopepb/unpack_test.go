@@ -0,0 +1,152 @@
+package envelopepb_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+func TestUnpackAny(t *testing.T) {
+	packer := &Packer{
+		Application: identitypb.New("app", uuidpb.Generate()),
+	}
+
+	t.Run("it unpacks the message and its type", func(t *testing.T) {
+		env := packer.PackCommand(CommandA1)
+
+		m, mt, err := UnpackAny(env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected message", m, dogma.Message(CommandA1))
+
+		if mt != message.TypeOf(CommandA1) {
+			t.Fatalf("unexpected message type: got %s, want %s", mt, message.TypeOf(CommandA1))
+		}
+	})
+
+	t.Run("it returns an error if the message is not of the expected kind", func(t *testing.T) {
+		env := packer.PackCommand(CommandA1)
+
+		_, _, err := UnpackAny(env, WithKind(message.EventKind))
+
+		Expect(
+			t,
+			"unexpected error message",
+			fmt.Sprint(err),
+			fmt.Sprintf(
+				"unable to unpack envelope: message type %s is a command, expected an event",
+				MessageTypeID[*CommandStub[TypeA]](),
+			),
+		)
+	})
+
+	t.Run("it returns an error if the message type is not registered", func(t *testing.T) {
+		env := NewEnvelopeBuilder().
+			WithBody(NewBodyBuilder().
+				WithMessage(NewMessageBuilder().
+					WithDescription("<description>").
+					WithTypeId(uuidpb.MustParse("f1816a71-3593-4771-8d8b-327650571288")).
+					WithData([]byte(`{"content":"A1"}`)).
+					Build()).
+				Build()).
+			Build()
+
+		_, _, err := UnpackAny(env)
+
+		Expect(
+			t,
+			"unexpected error message",
+			fmt.Sprint(err),
+			"unable to unpack envelope: f1816a71-3593-4771-8d8b-327650571288 is not a registered message type ID",
+		)
+	})
+}
+
+func TestUnpackAll(t *testing.T) {
+	packer := &Packer{
+		Application: identitypb.New("app", uuidpb.Generate()),
+	}
+
+	cause := packer.PackCommand(CommandA1)
+	handler := identitypb.New("handler", uuidpb.Generate())
+
+	t.Run("it yields each message in order", func(t *testing.T) {
+		effects := packer.PackEffects(cause, handler)
+		effects.PackEvent(EventA1)
+		effects.PackCommand(CommandB1)
+		env, _ := effects.Seal()
+
+		var got []dogma.Message
+		for u, err := range UnpackAll(env) {
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if u.Type != message.TypeOf(u.Message) {
+				t.Fatalf("unexpected message type: got %s, want %s", u.Type, message.TypeOf(u.Message))
+			}
+
+			got = append(got, u.Message)
+		}
+
+		Expect(t, "unexpected messages", got, []dogma.Message{EventA1, CommandB1})
+	})
+
+	t.Run("it identifies the index of the body that could not be unpacked", func(t *testing.T) {
+		effects := packer.PackEffects(cause, handler)
+		effects.PackEvent(EventA1)
+		effects.PackCommand(CommandB1)
+		effects.PackEvent(EventA2)
+		env, _ := effects.Seal()
+
+		var (
+			got  []dogma.Message
+			errs []string
+		)
+
+		for u, err := range UnpackAll(env, WithKind(message.EventKind)) {
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			got = append(got, u.Message)
+		}
+
+		Expect(t, "unexpected messages", got, []dogma.Message{EventA1, EventA2})
+		Expect(
+			t,
+			"unexpected errors",
+			errs,
+			[]string{
+				fmt.Sprintf(
+					"unable to unpack body at index 1: message type %s is a command, expected an event",
+					MessageTypeID[*CommandStub[TypeB]](),
+				),
+			},
+		)
+	})
+
+	t.Run("it stops when the caller stops iterating", func(t *testing.T) {
+		effects := packer.PackEffects(cause, handler)
+		effects.PackEvent(EventA1)
+		effects.PackEvent(EventA2)
+		env, _ := effects.Seal()
+
+		n := 0
+		for range UnpackAll(env) {
+			n++
+			break
+		}
+
+		Expect(t, "unexpected iteration count", n, 1)
+	})
+}
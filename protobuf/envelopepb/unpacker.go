@@ -0,0 +1,224 @@
+package envelopepb
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/message"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+	"github.com/dogmatiq/enginekit/telemetry"
+)
+
+// DefaultUnpackerCacheSize is the maximum number of messages cached by an
+// [Unpacker] when [Unpacker.MaxSize] is zero or negative.
+const DefaultUnpackerCacheSize = 1000
+
+// Unpacker unpacks messages from envelopes, caching the unpacked messages by
+// their message ID so that envelopes that are unpacked repeatedly are only
+// unmarshaled once.
+//
+// The cached message is shared by all callers that unpack the same message ID,
+// and therefore must not be modified.
+//
+// Messages with encrypted data are never cached, so that destroying the key
+// used to encrypt the data makes the message unreadable immediately, rather
+// than after it has been evicted from the cache.
+//
+// It is safe for concurrent use. The zero value is ready to use.
+type Unpacker struct {
+	// MaxSize is the maximum number of messages in the cache. When the cache is
+	// full, the least recently used message is evicted. If it is zero or
+	// negative, [DefaultUnpackerCacheSize] is used.
+	MaxSize int
+
+	// MaxAge is the maximum amount of time that a message remains in the
+	// cache after it is first unpacked. If it is zero, messages are only
+	// evicted when the cache is full.
+	MaxAge time.Duration
+
+	// Options is a set of options applied to every unpacking operation.
+	Options []UnpackOption
+
+	// Telemetry is the provider used to record cache metrics. If it is nil,
+	// no metrics are recorded.
+	Telemetry *telemetry.Provider
+
+	// Now is a function used to get the current time. If it is nil, time.Now()
+	// is used.
+	Now func() time.Time
+
+	init      sync.Once
+	opts      unpackOptions
+	hits      telemetry.Instrument[int64]
+	misses    telemetry.Instrument[int64]
+	evictions telemetry.Instrument[int64]
+
+	m       sync.Mutex
+	entries uuidpb.Map[*list.Element]
+	order   list.List // of *unpackerEntry, most recently used first
+}
+
+// unpackerEntry is a message in an [Unpacker]'s cache.
+type unpackerEntry struct {
+	ID       *uuidpb.UUID
+	Message  dogma.Message
+	Type     message.Type
+	CachedAt time.Time
+}
+
+// Unpack returns the message contained within env, along with its type.
+//
+// It behaves like [UnpackAny], except that the message is returned from the
+// cache if a message with the same ID has already been unpacked.
+//
+// It returns an error if env does not have a valid message ID.
+func (u *Unpacker) Unpack(ctx context.Context, env *Envelope) (dogma.Message, message.Type, error) {
+	u.init.Do(u.setup)
+
+	body := env.GetBody()
+	id := body.GetMessageId()
+
+	if err := id.Validate(); err != nil {
+		return nil, message.Type{}, fmt.Errorf("unable to unpack envelope: invalid message ID (%s): %w", id, err)
+	}
+
+	if _, ok, _ := GetExtension[*Encryption](body); ok {
+		m, t, err := unpackAny(body, u.opts)
+		if err != nil {
+			return nil, message.Type{}, fmt.Errorf("unable to unpack envelope: %w", err)
+		}
+		return m, t, nil
+	}
+
+	if e, ok := u.get(ctx, id); ok {
+		u.hits(ctx, 1)
+		return e.Message, e.Type, nil
+	}
+
+	u.misses(ctx, 1)
+
+	m, t, err := unpackAny(body, u.opts)
+	if err != nil {
+		return nil, message.Type{}, fmt.Errorf("unable to unpack envelope: %w", err)
+	}
+
+	u.put(ctx, &unpackerEntry{id, m, t, u.now()})
+
+	return m, t, nil
+}
+
+// Len returns the number of messages in the cache.
+func (u *Unpacker) Len() int {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	return u.order.Len()
+}
+
+// Evict removes the message with the given ID from the cache.
+func (u *Unpacker) Evict(id *uuidpb.UUID) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	if elem, ok := u.entries.Get(id); ok {
+		u.remove(elem)
+	}
+}
+
+func (u *Unpacker) setup() {
+	u.opts = newUnpackOptions(u.Options)
+
+	r := u.Telemetry.Recorder("github.com/dogmatiq/enginekit/protobuf/envelopepb")
+	u.hits = r.Counter("unpacker.cache.hits", "{message}", "The number of messages that were found in the unpacker's cache.")
+	u.misses = r.Counter("unpacker.cache.misses", "{message}", "The number of messages that were not found in the unpacker's cache.")
+	u.evictions = r.Counter("unpacker.cache.evictions", "{message}", "The number of messages that were evicted from the unpacker's cache.")
+}
+
+// get returns the cached entry for the message with the given ID, if it is
+// present and has not expired.
+func (u *Unpacker) get(ctx context.Context, id *uuidpb.UUID) (*unpackerEntry, bool) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	elem, ok := u.entries.Get(id)
+	if !ok {
+		return nil, false
+	}
+
+	e := elem.Value.(*unpackerEntry)
+
+	if u.isExpired(e) {
+		u.remove(elem)
+		u.evictions(ctx, 1, expiredReason)
+		return nil, false
+	}
+
+	u.order.MoveToFront(elem)
+
+	return e, true
+}
+
+// put adds e to the cache, evicting messages as necessary to remain within
+// the cache's limits.
+func (u *Unpacker) put(ctx context.Context, e *unpackerEntry) {
+	u.m.Lock()
+	defer u.m.Unlock()
+
+	if elem, ok := u.entries.Get(e.ID); ok {
+		// Another goroutine unpacked the same message concurrently.
+		u.order.MoveToFront(elem)
+		return
+	}
+
+	u.entries.Set(e.ID, u.order.PushFront(e))
+
+	for elem := u.order.Back(); elem != nil; elem = u.order.Back() {
+		if !u.isExpired(elem.Value.(*unpackerEntry)) {
+			break
+		}
+		u.remove(elem)
+		u.evictions(ctx, 1, expiredReason)
+	}
+
+	for u.order.Len() > u.maxSize() {
+		u.remove(u.order.Back())
+		u.evictions(ctx, 1, capacityReason)
+	}
+}
+
+func (u *Unpacker) remove(elem *list.Element) {
+	u.order.Remove(elem)
+	u.entries.Delete(elem.Value.(*unpackerEntry).ID)
+}
+
+// isExpired returns true if e has been in the cache for longer than MaxAge.
+//
+// Entries are ordered by recency of use, not by age, so the age-based
+// eviction in [Unpacker.put] only removes expired entries that have also been
+// least recently used. Any others are removed when they are next accessed.
+func (u *Unpacker) isExpired(e *unpackerEntry) bool {
+	return u.MaxAge != 0 && u.now().Sub(e.CachedAt) >= u.MaxAge
+}
+
+func (u *Unpacker) maxSize() int {
+	if u.MaxSize <= 0 {
+		return DefaultUnpackerCacheSize
+	}
+	return u.MaxSize
+}
+
+func (u *Unpacker) now() time.Time {
+	if u.Now == nil {
+		return time.Now()
+	}
+	return u.Now()
+}
+
+var (
+	expiredReason  = telemetry.String("eviction.reason", "expired")
+	capacityReason = telemetry.String("eviction.reason", "capacity")
+)
@@ -0,0 +1,277 @@
+package envelopepb_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	"github.com/dogmatiq/enginekit/message"
+	. "github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+	"github.com/dogmatiq/enginekit/telemetry"
+	"go.opentelemetry.io/otel/metric"
+	noopmetric "go.opentelemetry.io/otel/metric/noop"
+)
+
+func TestUnpacker(t *testing.T) {
+	packer := &Packer{
+		Application: identitypb.New("app", uuidpb.Generate()),
+	}
+
+	cause := packer.PackCommand(CommandA1)
+	handler := identitypb.New("handler", uuidpb.Generate())
+
+	effects := packer.PackEffects(cause, handler)
+	env1 := effects.PackEvent(EventA1)
+	env2 := effects.PackEvent(EventA2)
+	env3 := effects.PackEvent(EventA3)
+
+	setup := func() (*Unpacker, *counterProvider, *time.Time) {
+		now := time.Now()
+		metrics := &counterProvider{}
+
+		u := &Unpacker{
+			MaxSize: 2,
+			MaxAge:  time.Minute,
+			Telemetry: &telemetry.Provider{
+				MeterProvider: metrics,
+			},
+			Now: func() time.Time {
+				return now
+			},
+		}
+
+		return u, metrics, &now
+	}
+
+	t.Run("it unpacks the message", func(t *testing.T) {
+		u, _, _ := setup()
+
+		m, mt, err := u.Unpack(context.Background(), env1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected message", m, dogma.Message(EventA1))
+
+		if mt != message.TypeOf(EventA1) {
+			t.Fatalf("unexpected message type: got %s, want %s", mt, message.TypeOf(EventA1))
+		}
+	})
+
+	t.Run("it returns the cached message for subsequent calls", func(t *testing.T) {
+		u, metrics, _ := setup()
+
+		first, _, err := u.Unpack(context.Background(), env1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		second, _, err := u.Unpack(context.Background(), env1)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if first != second {
+			t.Fatal("expected the cached message to be returned")
+		}
+
+		Expect(t, "unexpected hit count", metrics.Get("unpacker.cache.hits"), 1)
+		Expect(t, "unexpected miss count", metrics.Get("unpacker.cache.misses"), 1)
+	})
+
+	t.Run("it evicts the least recently used message when the cache is full", func(t *testing.T) {
+		u, metrics, _ := setup()
+		ctx := context.Background()
+
+		for _, env := range []*Envelope{env1, env2, env1, env3} {
+			if _, _, err := u.Unpack(ctx, env); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		Expect(t, "unexpected cache size", u.Len(), 2)
+		Expect(t, "unexpected eviction count", metrics.Get("unpacker.cache.evictions"), 1)
+
+		if _, _, err := u.Unpack(ctx, env1); err != nil {
+			t.Fatal(err)
+		}
+		Expect(t, "unexpected hit count", metrics.Get("unpacker.cache.hits"), 2)
+
+		if _, _, err := u.Unpack(ctx, env2); err != nil {
+			t.Fatal(err)
+		}
+		Expect(t, "unexpected miss count", metrics.Get("unpacker.cache.misses"), 4)
+	})
+
+	t.Run("it evicts messages that exceed the maximum age", func(t *testing.T) {
+		u, metrics, now := setup()
+		ctx := context.Background()
+
+		if _, _, err := u.Unpack(ctx, env1); err != nil {
+			t.Fatal(err)
+		}
+
+		*now = now.Add(time.Minute)
+
+		if _, _, err := u.Unpack(ctx, env1); err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected hit count", metrics.Get("unpacker.cache.hits"), 0)
+		Expect(t, "unexpected miss count", metrics.Get("unpacker.cache.misses"), 2)
+		Expect(t, "unexpected eviction count", metrics.Get("unpacker.cache.evictions"), 1)
+	})
+
+	t.Run("it does not cache messages that can not be unpacked", func(t *testing.T) {
+		u, _, _ := setup()
+		u.Options = []UnpackOption{WithKind(message.CommandKind)}
+
+		_, _, err := u.Unpack(context.Background(), env1)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+
+		Expect(t, "unexpected cache size", u.Len(), 0)
+	})
+
+	t.Run("it returns an error if the envelope does not have a valid message ID", func(t *testing.T) {
+		u, _, _ := setup()
+
+		for _, m := range []dogma.Command{CommandA1, CommandB1} {
+			env := packer.PackCommand(m)
+			env.GetBody().ClearMessageId()
+
+			got, _, err := u.Unpack(context.Background(), env)
+			if err == nil {
+				t.Fatalf("expected an error, got %v", got)
+			}
+		}
+
+		Expect(t, "unexpected cache size", u.Len(), 0)
+	})
+
+	t.Run("it does not cache messages with encrypted data", func(t *testing.T) {
+		u, metrics, _ := setup()
+
+		keys := &MemoryKeyProvider{}
+		u.Options = []UnpackOption{WithKeyProvider(keys)}
+
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Encrypter:   &Encrypter{Keys: keys},
+		}
+
+		env := packer.PackCommand(CommandA1)
+
+		m, _, err := u.Unpack(context.Background(), env)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected message", m, dogma.Message(CommandA1))
+		Expect(t, "unexpected cache size", u.Len(), 0)
+		Expect(t, "unexpected miss count", metrics.Get("unpacker.cache.misses"), 0)
+
+		keys.Destroy(env.GetHeader().GetCorrelationId().AsString())
+
+		_, _, err = u.Unpack(context.Background(), env)
+		if !errors.Is(err, ErrKeyUnavailable) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("func Evict()", func(t *testing.T) {
+		t.Run("it removes the message from the cache", func(t *testing.T) {
+			u, _, _ := setup()
+
+			if _, _, err := u.Unpack(context.Background(), env1); err != nil {
+				t.Fatal(err)
+			}
+
+			u.Evict(env1.GetBody().GetMessageId())
+
+			Expect(t, "unexpected cache size", u.Len(), 0)
+		})
+	})
+
+	t.Run("it uses the default cache size if the maximum size is negative", func(t *testing.T) {
+		u := &Unpacker{MaxSize: -1}
+
+		for _, env := range []*Envelope{env1, env2, env3} {
+			if _, _, err := u.Unpack(context.Background(), env); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		Expect(t, "unexpected cache size", u.Len(), 3)
+	})
+
+	t.Run("it is safe for concurrent use", func(t *testing.T) {
+		u := &Unpacker{MaxSize: 2}
+
+		var g sync.WaitGroup
+		for range 10 {
+			g.Go(func() {
+				for _, env := range []*Envelope{env1, env2, env3} {
+					if _, _, err := u.Unpack(context.Background(), env); err != nil {
+						t.Error(err)
+					}
+				}
+			})
+		}
+		g.Wait()
+
+		Expect(t, "unexpected cache size", u.Len(), 2)
+	})
+}
+
+// counterProvider is a [metric.MeterProvider] that records the totals of each
+// int64 counter.
+type counterProvider struct {
+	noopmetric.MeterProvider
+
+	m      sync.Mutex
+	totals map[string]int
+}
+
+func (p *counterProvider) Meter(string, ...metric.MeterOption) metric.Meter {
+	return counterMeter{p: p}
+}
+
+func (p *counterProvider) Get(name string) int {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.totals[name]
+}
+
+type counterMeter struct {
+	noopmetric.Meter
+	p *counterProvider
+}
+
+func (m counterMeter) Int64Counter(name string, _ ...metric.Int64CounterOption) (metric.Int64Counter, error) {
+	return counter{p: m.p, name: name}, nil
+}
+
+type counter struct {
+	noopmetric.Int64Counter
+	p    *counterProvider
+	name string
+}
+
+func (c counter) Add(_ context.Context, v int64, _ ...metric.AddOption) {
+	c.p.m.Lock()
+	defer c.p.m.Unlock()
+
+	if c.p.totals == nil {
+		c.p.totals = map[string]int{}
+	}
+	c.p.totals[c.name] += int(v)
+}
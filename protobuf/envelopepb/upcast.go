@@ -0,0 +1,266 @@
+package envelopepb
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/dogmatiq/dogma"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+// Upcaster converts the data of a message from a retired message type, or from
+// an older version of a message type's schema, into data of a newer type or
+// schema.
+type Upcaster struct {
+	// FromTypeID is the message type ID of the messages that the upcaster
+	// converts.
+	FromTypeID string
+
+	// FromVersion is the schema version of the messages that the upcaster
+	// converts, as per the [SchemaVersion] extension. Messages without a
+	// [SchemaVersion] extension are at version zero.
+	FromVersion uint32
+
+	// ToTypeID is the message type ID of the converted data. It may be the
+	// same as FromTypeID if only the schema version changes.
+	ToTypeID string
+
+	// ToVersion is the schema version of the converted data.
+	ToVersion uint32
+
+	// Upcast converts data from the old type or schema to the new one.
+	Upcast func(data []byte) ([]byte, error)
+}
+
+// Upcasters is a registry of [Upcaster] values used to read messages with
+// retired type IDs or older schema versions.
+//
+// When a message is unpacked using the [WithUpcasters] option, the upcaster
+// registered for the message's type ID and schema version is applied to its
+// data. Upcasters are chained until there is no upcaster for the resulting type
+// ID and schema version, or until the data reaches the current schema version
+// of its type, at which point the message is unmarshaled as the registered
+// message type with the resulting type ID.
+//
+// Messages of a type with a non-zero current schema version must be packed
+// with a [SchemaVersion] extension, otherwise their data is mistaken for data
+// at version zero. See [Upcasters.SetCurrentVersion] and [Packer.Upcasters].
+//
+// It is safe for concurrent use. The zero value is ready to use.
+type Upcasters struct {
+	m       sync.RWMutex
+	from    map[upcasterKey]upcaster
+	current map[[16]byte]uint32
+}
+
+type upcasterKey struct {
+	TypeID  [16]byte
+	Version uint32
+}
+
+type upcaster struct {
+	To     upcasterKey
+	Upcast func([]byte) ([]byte, error)
+}
+
+// Register adds u to the registry.
+//
+// It panics if u's type IDs are invalid, if an upcaster is already registered
+// for the same type ID and schema version, or if u would introduce a cycle.
+func (r *Upcasters) Register(u Upcaster) {
+	if u.Upcast == nil {
+		panic("upcast function must not be nil")
+	}
+
+	from := upcasterKey{mustParseTypeID(u.FromTypeID), u.FromVersion}
+	to := upcasterKey{mustParseTypeID(u.ToTypeID), u.ToVersion}
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if _, ok := r.from[from]; ok {
+		panic(fmt.Sprintf(
+			"an upcaster is already registered for %s (version %d)",
+			u.FromTypeID,
+			u.FromVersion,
+		))
+	}
+
+	for k := to; ; {
+		if k == from {
+			panic(fmt.Sprintf(
+				"upcaster for %s (version %d) introduces a cycle",
+				u.FromTypeID,
+				u.FromVersion,
+			))
+		}
+
+		next, ok := r.from[k]
+		if !ok {
+			break
+		}
+		k = next.To
+	}
+
+	if r.from == nil {
+		r.from = map[upcasterKey]upcaster{}
+	}
+
+	r.from[from] = upcaster{to, u.Upcast}
+}
+
+// SetCurrentVersion sets the schema version of the data produced by the
+// current implementation of the message type with the given ID.
+//
+// Upcasters are not applied to data that is already at (or beyond) the current
+// version, even if an upcaster is registered for that version. A [Packer] that
+// uses r records the current version in a [SchemaVersion] extension on each
+// message of this type.
+//
+// It panics if typeID is invalid.
+func (r *Upcasters) SetCurrentVersion(typeID string, version uint32) {
+	id := mustParseTypeID(typeID)
+
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	if r.current == nil {
+		r.current = map[[16]byte]uint32{}
+	}
+
+	r.current[id] = version
+}
+
+// FindUnresolvable returns the distinct message type IDs within envelopes that
+// can not be unpacked, because they are neither registered message types nor
+// converted to a registered message type by the upcasters in r.
+//
+// It is intended to validate a journal, or some other store of envelopes,
+// before attempting to read it.
+func (r *Upcasters) FindUnresolvable(envelopes iter.Seq[*Envelope]) ([]*uuidpb.UUID, error) {
+	var (
+		checked      = map[upcasterKey]struct{}{}
+		unresolvable uuidpb.Set
+	)
+
+	for env := range envelopes {
+		body := env.GetBody()
+		typeID := body.GetMessage().GetTypeId()
+
+		version, err := schemaVersion(body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid envelope for message %s: %w", body.GetMessageId(), err)
+		}
+
+		k := upcasterKey{typeID.AsByteArray(), version}
+		if _, ok := checked[k]; ok {
+			continue
+		}
+		checked[k] = struct{}{}
+
+		id := uuidpb.FromByteArray(r.resolve(k).TypeID)
+		if _, ok := dogma.RegisteredMessageTypeByID(id.AsString()); !ok {
+			unresolvable.Add(typeID)
+		}
+	}
+
+	ids := slices.Collect(unresolvable.All())
+	slices.SortFunc(ids, (*uuidpb.UUID).Compare)
+
+	return ids, nil
+}
+
+// upcast applies the upcasters for the message within body to data, and
+// returns the resulting type ID and data.
+func (r *Upcasters) upcast(body *Body, data []byte) (*uuidpb.UUID, []byte, error) {
+	version, err := schemaVersion(body)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	typeID := body.GetMessage().GetTypeId()
+	k := upcasterKey{typeID.AsByteArray(), version}
+
+	for {
+		u, ok := r.lookup(k)
+		if !ok {
+			return uuidpb.FromByteArray(k.TypeID), data, nil
+		}
+
+		data, err = u.Upcast(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf(
+				"unable to upcast %s (version %d) to %s (version %d): %w",
+				uuidpb.FromByteArray(k.TypeID),
+				k.Version,
+				uuidpb.FromByteArray(u.To.TypeID),
+				u.To.Version,
+				err,
+			)
+		}
+
+		k = u.To
+	}
+}
+
+// resolve returns the key at the end of the chain of upcasters that begins at
+// k.
+func (r *Upcasters) resolve(k upcasterKey) upcasterKey {
+	for {
+		u, ok := r.lookup(k)
+		if !ok {
+			return k
+		}
+		k = u.To
+	}
+}
+
+// lookup returns the upcaster that converts data at k, if any. There is no
+// such upcaster if k is at or beyond the current version of its type.
+func (r *Upcasters) lookup(k upcasterKey) (upcaster, bool) {
+	if r == nil {
+		return upcaster{}, false
+	}
+
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	if v, ok := r.current[k.TypeID]; ok && k.Version >= v {
+		return upcaster{}, false
+	}
+
+	u, ok := r.from[k]
+	return u, ok
+}
+
+// currentVersion returns the current schema version of the message type with
+// the given ID, as set by [Upcasters.SetCurrentVersion].
+func (r *Upcasters) currentVersion(typeID *uuidpb.UUID) uint32 {
+	if r == nil {
+		return 0
+	}
+
+	r.m.RLock()
+	defer r.m.RUnlock()
+
+	return r.current[typeID.AsByteArray()]
+}
+
+// schemaVersion returns the schema version of the message within body.
+func schemaVersion(body *Body) (uint32, error) {
+	x, _, err := GetExtension[*SchemaVersion](body)
+	if err != nil {
+		return 0, fmt.Errorf("unable to unmarshal schema version extension: %w", err)
+	}
+	return x.GetVersion(), nil
+}
+
+func mustParseTypeID(id string) [16]byte {
+	v, err := uuidpb.ParseAsByteArray(id)
+	if err != nil {
+		panic(fmt.Sprintf("invalid message type ID %q: %s", id, err))
+	}
+	return v
+}
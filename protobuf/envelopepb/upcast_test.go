@@ -0,0 +1,259 @@
+package envelopepb_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/dogmatiq/dogma"
+	. "github.com/dogmatiq/enginekit/enginetest/stubs"
+	. "github.com/dogmatiq/enginekit/internal/test"
+	. "github.com/dogmatiq/enginekit/protobuf/envelopepb"
+	"github.com/dogmatiq/enginekit/protobuf/identitypb"
+	"github.com/dogmatiq/enginekit/protobuf/uuidpb"
+)
+
+func TestUpcasters(t *testing.T) {
+	const retiredTypeID = "8f5c2a8e-6f27-4a3e-9a43-0c3ee5d5b7a4"
+
+	packer := &Packer{
+		Application: identitypb.New("app", uuidpb.Generate()),
+	}
+
+	current := packer.PackCommand(CommandA1)
+	currentTypeID := MessageTypeID[*CommandStub[TypeA]]()
+
+	// retired returns an envelope containing a message with the given type ID,
+	// schema version and data.
+	retired := func(typeID string, version uint32, data string) *Envelope {
+		body := NewBodyBuilder().
+			From(current.GetBody()).
+			WithMessage(
+				NewMessageBuilder().
+					From(current.GetBody().GetMessage()).
+					WithTypeId(uuidpb.MustParse(typeID)).
+					WithData([]byte(data)).
+					Build(),
+			).
+			Build()
+
+		if version != 0 {
+			SetExtension(body, NewSchemaVersionBuilder().WithVersion(version).Build())
+		}
+
+		return NewEnvelopeBuilder().
+			From(current).
+			WithBody(body).
+			Build()
+	}
+
+	// rename returns an upcast function that renames a JSON field.
+	rename := func(from, to string) func([]byte) ([]byte, error) {
+		return func(data []byte) ([]byte, error) {
+			return bytes.ReplaceAll(data, []byte(`"`+from+`"`), []byte(`"`+to+`"`)), nil
+		}
+	}
+
+	upcasters := &Upcasters{}
+	upcasters.Register(Upcaster{
+		FromTypeID: retiredTypeID,
+		ToTypeID:   currentTypeID,
+		ToVersion:  1,
+		Upcast:     rename("text", "body"),
+	})
+	upcasters.Register(Upcaster{
+		FromTypeID:  currentTypeID,
+		FromVersion: 1,
+		ToTypeID:    currentTypeID,
+		ToVersion:   2,
+		Upcast:      rename("body", "content"),
+	})
+
+	t.Run("it chains upcasters to convert a retired message type", func(t *testing.T) {
+		env := retired(retiredTypeID, 0, `{"text":"A1"}`)
+
+		m, err := Unpack[dogma.Command](env, WithUpcasters(upcasters))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected message", m, dogma.Command(CommandA1))
+	})
+
+	t.Run("it converts an older schema version", func(t *testing.T) {
+		env := retired(currentTypeID, 1, `{"body":"A1"}`)
+
+		m, _, err := UnpackAny(env, WithUpcasters(upcasters))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected message", m, dogma.Message(CommandA1))
+	})
+
+	t.Run("it does not modify messages that have no upcaster", func(t *testing.T) {
+		m, err := Unpack[dogma.Command](current, WithUpcasters(upcasters))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		Expect(t, "unexpected message", m, dogma.Command(CommandA1))
+	})
+
+	t.Run("it returns an error if an upcaster fails", func(t *testing.T) {
+		const failingTypeID = "0a3b9d4e-1c55-4b6f-8f61-7d2f1e6c9b02"
+
+		r := &Upcasters{}
+		r.Register(Upcaster{
+			FromTypeID: failingTypeID,
+			ToTypeID:   currentTypeID,
+			Upcast: func([]byte) ([]byte, error) {
+				return nil, errors.New("<error>")
+			},
+		})
+
+		_, _, err := UnpackAny(retired(failingTypeID, 0, `{}`), WithUpcasters(r))
+
+		Expect(
+			t,
+			"unexpected error message",
+			fmt.Sprint(err),
+			fmt.Sprintf(
+				"unable to unpack envelope: unable to upcast %s (version 0) to %s (version 0): <error>",
+				failingTypeID,
+				currentTypeID,
+			),
+		)
+	})
+
+	t.Run("it does not upcast messages that are at the current version", func(t *testing.T) {
+		r := &Upcasters{}
+		r.Register(Upcaster{
+			FromTypeID: currentTypeID,
+			ToTypeID:   currentTypeID,
+			ToVersion:  1,
+			Upcast: func(data []byte) ([]byte, error) {
+				// Version 0 omitted the "A" prefix.
+				return bytes.ReplaceAll(data, []byte(`"content":"`), []byte(`"content":"A`)), nil
+			},
+		})
+		r.Register(Upcaster{
+			FromTypeID:  currentTypeID,
+			FromVersion: 1,
+			ToTypeID:    currentTypeID,
+			ToVersion:   2,
+			Upcast:      rename("content", "future"),
+		})
+		r.SetCurrentVersion(currentTypeID, 1)
+
+		packer := &Packer{
+			Application: identitypb.New("app", uuidpb.Generate()),
+			Upcasters:   r,
+		}
+
+		fresh := packer.PackCommand(CommandA1)
+
+		x, ok, err := GetExtension[*SchemaVersion](fresh.GetBody())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !ok {
+			t.Fatal("expected a schema version extension")
+		}
+		Expect(t, "unexpected schema version", x.GetVersion(), uint32(1))
+
+		for _, env := range []*Envelope{
+			fresh,
+			retired(currentTypeID, 0, `{"content":"1"}`),
+		} {
+			m, err := Unpack[dogma.Command](env, WithUpcasters(r))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(t, "unexpected message", m, dogma.Command(CommandA1))
+		}
+	})
+
+	t.Run("func Register()", func(t *testing.T) {
+		t.Run("it panics if an upcaster is already registered", func(t *testing.T) {
+			ExpectPanic(
+				t,
+				fmt.Sprintf("an upcaster is already registered for %s (version 0)", retiredTypeID),
+				func() {
+					upcasters.Register(Upcaster{
+						FromTypeID: retiredTypeID,
+						ToTypeID:   currentTypeID,
+						Upcast:     rename("a", "b"),
+					})
+				},
+			)
+		})
+
+		t.Run("it panics if the upcaster introduces a cycle", func(t *testing.T) {
+			ExpectPanic(
+				t,
+				fmt.Sprintf("upcaster for %s (version 2) introduces a cycle", currentTypeID),
+				func() {
+					upcasters.Register(Upcaster{
+						FromTypeID:  currentTypeID,
+						FromVersion: 2,
+						ToTypeID:    retiredTypeID,
+						Upcast:      rename("a", "b"),
+					})
+				},
+			)
+		})
+
+		t.Run("it panics if a type ID is invalid", func(t *testing.T) {
+			ExpectPanic(
+				t,
+				`invalid message type ID "<invalid>": invalid UUID format, expected 36 characters`,
+				func() {
+					upcasters.Register(Upcaster{
+						FromTypeID: "<invalid>",
+						ToTypeID:   currentTypeID,
+						Upcast:     rename("a", "b"),
+					})
+				},
+			)
+		})
+	})
+
+	t.Run("func FindUnresolvable()", func(t *testing.T) {
+		t.Run("it returns type IDs that are neither registered nor upcast", func(t *testing.T) {
+			const unknownTypeID = "3e0c7a51-92b4-4d0e-b1f3-5a6d8c2e4f90"
+
+			journal := []*Envelope{
+				current,
+				retired(retiredTypeID, 0, `{}`),
+				retired(unknownTypeID, 0, `{}`),
+				retired(unknownTypeID, 3, `{}`),
+			}
+
+			ids, err := upcasters.FindUnresolvable(slices.Values(journal))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(t, "unexpected type IDs", ids, []*uuidpb.UUID{uuidpb.MustParse(unknownTypeID)})
+
+			ids, err = (*Upcasters)(nil).FindUnresolvable(slices.Values(journal))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			Expect(
+				t,
+				"unexpected type IDs",
+				ids,
+				[]*uuidpb.UUID{
+					uuidpb.MustParse(unknownTypeID),
+					uuidpb.MustParse(retiredTypeID),
+				},
+			)
+		})
+	})
+}
@@ -20,12 +20,34 @@ func (r *Recorder) StartSpan(
 	ctx context.Context,
 	name string,
 	attrs ...Attr,
+) (context.Context, *Span) {
+	return r.startSpan(ctx, name, nil, attrs)
+}
+
+// StartLinkedSpan starts a new span that is linked to the given spans, such
+// as the spans that produced the messages being handled. It otherwise behaves
+// like [Recorder.StartSpan].
+func (r *Recorder) StartLinkedSpan(
+	ctx context.Context,
+	name string,
+	links []trace.Link,
+	attrs ...Attr,
+) (context.Context, *Span) {
+	return r.startSpan(ctx, name, links, attrs)
+}
+
+func (r *Recorder) startSpan(
+	ctx context.Context,
+	name string,
+	links []trace.Link,
+	attrs []Attr,
 ) (context.Context, *Span) {
 	ctx, underlying := r.tracer.Start(
 		ctx,
 		name,
 		trace.WithAttributes(r.attrKVs.ToSlice()...),
 		trace.WithAttributes(asAttrKeyValues(attrs)...),
+		trace.WithLinks(links...),
 	)
 
 	op := String("operation", name)